@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/db"
+	"airline-booking/internal/es"
+	"airline-booking/internal/repository"
+	"airline-booking/internal/service"
+)
+
+// airports-loader imports the airports reference CSV into MySQL and the
+// Elasticsearch autocomplete index. It is safe to re-run: rows are upserted
+// and documents are keyed by IATA code.
+func main() {
+	file := flag.String("file", "data/airports.csv", "path to the airports CSV file")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Setup logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.Fatal("Failed to open airports file", zap.String("file", *file), zap.Error(err))
+	}
+	defer f.Close()
+
+	airports, err := service.ParseAirportsCSV(f)
+	if err != nil {
+		logger.Fatal("Failed to parse airports file", zap.String("file", *file), zap.Error(err))
+	}
+
+	// Initialize database
+	database, err := db.NewDatabase(&cfg.Database, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer database.Close()
+
+	// Initialize Elasticsearch client
+	esClient, err := es.NewClient(&cfg.Elasticsearch, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to Elasticsearch", zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	if err := esClient.CreateIndex(ctx); err != nil {
+		logger.Fatal("Failed to create Elasticsearch indexes", zap.Error(err))
+	}
+
+	airportRepo := repository.NewAirportRepository(database, logger)
+	airportService := service.NewAirportService(airportRepo, esClient, logger)
+
+	if err := airportService.ImportAirports(ctx, airports); err != nil {
+		logger.Fatal("Failed to import airports", zap.Error(err))
+	}
+
+	logger.Info("Airport import completed successfully",
+		zap.String("file", *file),
+		zap.Int("airports", len(airports)))
+}
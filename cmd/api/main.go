@@ -11,15 +11,23 @@
 // @license.name MIT
 // @license.url https://opensource.org/licenses/MIT
 
-// @host localhost:8080
-// @BasePath /api/v1
+// @BasePath /
 
-// @securityDefinitions.basic BasicAuth
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description "Bearer " followed by the admin token (ADMIN_API_TOKEN)
+
+// @securityDefinitions.apikey GateToken
+// @in header
+// @name Authorization
+// @description "Bearer " followed by the gate token (GATE_API_TOKEN)
 
 package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
@@ -34,26 +42,51 @@ import (
 	"airline-booking/internal/config"
 	"airline-booking/internal/db"
 	"airline-booking/internal/es"
+	"airline-booking/internal/health"
 	"airline-booking/internal/jobs"
+	"airline-booking/internal/metrics"
+	"airline-booking/internal/payment"
 	"airline-booking/internal/repository"
 	"airline-booking/internal/service"
+	"airline-booking/internal/tracing"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables take precedence")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadFile(*configPath)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to load config: %v", err))
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *printConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	// Setup logger
-	logger := setupLogger(cfg.Log)
+	logger, logLevel := setupLogger(cfg.Log)
 	defer logger.Sync()
+	cfg.OnReload(func(live config.Live) {
+		logLevel.SetLevel(parseLogLevel(live.LogLevel))
+	})
 
 	logger.Info("Starting Airline Booking API",
 		zap.String("version", "1.0.0"),
 		zap.String("env", cfg.App.Env))
 
+	// Setup tracing
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing, logger)
+	if err != nil {
+		logger.Fatal("Failed to setup tracing", zap.Error(err))
+	}
+
 	// Initialize database
 	database, err := db.NewDatabase(&cfg.Database, logger)
 	if err != nil {
@@ -61,40 +94,90 @@ func main() {
 	}
 	defer database.Close()
 
-	// Run migrations
-	// if err := database.RunMigrations("migrations"); err != nil {
-	//	logger.Fatal("Failed to run migrations", zap.Error(err))
-	// }
+	if err := metrics.RegisterDBStats(database.DB, cfg.Database.Name); err != nil {
+		logger.Fatal("Failed to register database metrics", zap.Error(err))
+	}
 
-	// Initialize Elasticsearch client
-	esClient, err := es.NewClient(&cfg.Elasticsearch, logger)
-	if err != nil {
-		logger.Fatal("Failed to connect to Elasticsearch", zap.Error(err))
+	// Run migrations; pods starting together take turns on an advisory lock
+	if cfg.Database.MigrateOnStart {
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), cfg.Database.MigrateLockTimeout+5*time.Minute)
+		err := db.RunMigrations(migrateCtx, &cfg.Database, cfg.Database.MigrationsPath, cfg.Database.MigrateLockTimeout, logger)
+		cancelMigrate()
+		if err != nil {
+			logger.Fatal("Failed to run migrations", zap.Error(err))
+		}
 	}
 
-	// Create Elasticsearch index
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-	if err := esClient.CreateIndex(ctx); err != nil {
-		logger.Fatal("Failed to create Elasticsearch index", zap.Error(err))
+	// Initialize Elasticsearch client. The API starts without it: search
+	// falls back to MySQL until the cluster answers and the indexes are
+	// created in the background.
+	esClient, err := es.NewLazyClient(&cfg.Elasticsearch, logger)
+	if err != nil {
+		logger.Fatal("Failed to create Elasticsearch client", zap.Error(err))
 	}
+	esCtx, stopES := context.WithCancel(context.Background())
+	defer stopES()
+	esClient.ConnectInBackground(esCtx, esClient.CreateIndex)
 
 	// Initialize repositories
 	seatRepo := repository.NewSeatRepository(database, logger)
 	ticketRepo := repository.NewTicketRepository(database, logger)
 	flightRepo := repository.NewFlightRepository(database, logger)
+	airportRepo := repository.NewAirportRepository(database, logger)
+	inventoryRepo := repository.NewInventoryRepository(database, logger)
+	paymentEventRepo := repository.NewPaymentEventRepository(database, logger)
+	exchangeRateRepo := repository.NewExchangeRateRepository(database, logger)
+	fareRuleRepo := repository.NewFareRuleRepository(database, logger)
+	promotionRepo := repository.NewPromotionRepository(database, logger)
+	ancillaryRepo := repository.NewAncillaryRepository(database, logger)
+	checkInRepo := repository.NewCheckInRepository(database, logger)
+	auditRepo := repository.NewAuditRepository(database, logger)
+
+	paymentGateway, err := payment.NewGateway(&cfg.Payment)
+	if err != nil {
+		logger.Fatal("Failed to initialize payment gateway", zap.Error(err))
+	}
 
 	// Initialize services
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo, database, &cfg.Currency, logger)
+	promotionService := service.NewPromotionService(promotionRepo, logger)
+	ancillaryService := service.NewAncillaryService(ancillaryRepo, exchangeRateService, logger)
+	auditService := service.NewAuditService(auditRepo, logger)
 	bookingService := service.NewBookingService(
 		seatRepo,
 		ticketRepo,
 		flightRepo,
+		airportRepo,
+		inventoryRepo,
+		fareRuleRepo,
+		paymentGateway,
+		exchangeRateService,
+		promotionService,
+		ancillaryService,
+		auditService,
 		esClient,
 		database,
 		cfg,
 		logger,
 	)
 
+	airportService := service.NewAirportService(airportRepo, esClient, logger)
+	overbookingService := service.NewOverbookingService(inventoryRepo, ticketRepo, logger)
+	checkInService := service.NewCheckInService(
+		checkInRepo,
+		ticketRepo,
+		flightRepo,
+		seatRepo,
+		inventoryRepo,
+		airportRepo,
+		ancillaryRepo,
+		auditService,
+		database,
+		&cfg.CheckIn,
+		logger,
+	)
+	paymentWebhookService := service.NewPaymentWebhookService(paymentEventRepo, ticketRepo, auditService, database, &cfg.Payment, logger)
+
 	// Initialize cleanup job
 	cleanupJob := jobs.NewCleanupJob(bookingService, logger)
 	if err := cleanupJob.Start(); err != nil {
@@ -104,7 +187,20 @@ func main() {
 
 	// Initialize API handlers and router
 	bookingHandler := api.NewBookingHandler(bookingService, logger)
-	router := api.NewRouter(bookingHandler, cfg, logger)
+	airportHandler := api.NewAirportHandler(airportService, logger)
+	adminHandler := api.NewAdminHandler(overbookingService, exchangeRateService, promotionService, checkInService, auditService, logger)
+	webhookHandler := api.NewWebhookHandler(paymentWebhookService, logger)
+	checkInHandler := api.NewCheckInHandler(checkInService, logger)
+	healthChecker := health.NewChecker(database, esClient, cleanupJob, cfg.Database.MigrationsPath, logger)
+	healthHandler := api.NewHealthHandler(healthChecker, logger)
+	router := api.NewRouter(api.Handlers{
+		Booking:  bookingHandler,
+		Airports: airportHandler,
+		Admin:    adminHandler,
+		Webhooks: webhookHandler,
+		CheckIn:  checkInHandler,
+		Health:   healthHandler,
+	}, cfg, logger)
 	router.Setup()
 
 	// Setup HTTP server
@@ -124,6 +220,15 @@ func main() {
 		}
 	}()
 
+	// SIGHUP reloads the hold timers, rate limit and log level
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			reloadConfig(cfg, logger)
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -132,17 +237,39 @@ func main() {
 	logger.Info("Shutting down server...")
 
 	// Give outstanding requests a deadline for completion
-	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Flush spans of the last requests
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error("Failed to flush traces", zap.Error(err))
+	}
+
 	logger.Info("Server exited")
 }
 
-func setupLogger(cfg config.LogConfig) *zap.Logger {
+// reloadConfig reloads the config file and environment and applies the
+// settings that can change while running. An invalid configuration is
+// rejected as a whole.
+func reloadConfig(cfg *config.Config, logger *zap.Logger) {
+	next, err := config.LoadFile(cfg.Path())
+	if err != nil {
+		logger.Error("Config reload rejected", zap.Error(err))
+		return
+	}
+
+	changed, needRestart := cfg.Reload(next)
+	logger.Info("Config reloaded", zap.Strings("changed", changed))
+	if len(needRestart) > 0 {
+		logger.Warn("Config changes need a restart to apply", zap.Strings("settings", needRestart))
+	}
+}
+
+func setupLogger(cfg config.LogConfig) (*zap.Logger, zap.AtomicLevel) {
 	var zapConfig zap.Config
 
 	if cfg.Format == "json" {
@@ -152,24 +279,26 @@ func setupLogger(cfg config.LogConfig) *zap.Logger {
 		zapConfig.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 	}
 
-	// Set log level
-	switch cfg.Level {
-	case "debug":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-	case "info":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	case "warn":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
-	case "error":
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.ErrorLevel)
-	default:
-		zapConfig.Level = zap.NewAtomicLevelAt(zap.InfoLevel)
-	}
+	// Set log level; reloads change it through the atomic level
+	zapConfig.Level = zap.NewAtomicLevelAt(parseLogLevel(cfg.Level))
 
 	logger, err := zapConfig.Build()
 	if err != nil {
 		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
 	}
 
-	return logger
+	return logger, zapConfig.Level
+}
+
+func parseLogLevel(level string) zapcore.Level {
+	switch level {
+	case "debug":
+		return zap.DebugLevel
+	case "warn":
+		return zap.WarnLevel
+	case "error":
+		return zap.ErrorLevel
+	default:
+		return zap.InfoLevel
+	}
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/db"
+	"airline-booking/internal/repository"
+	"airline-booking/internal/service"
+)
+
+// exchange-rates-loader imports a dated exchange rate CSV into MySQL. It is
+// safe to re-run: a rate for an existing pair and effective time is replaced.
+func main() {
+	file := flag.String("file", "data/exchange_rates.csv", "path to the exchange rates CSV file")
+	flag.Parse()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Setup logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	f, err := os.Open(*file)
+	if err != nil {
+		logger.Fatal("Failed to open exchange rates file", zap.String("file", *file), zap.Error(err))
+	}
+	defer f.Close()
+
+	rates, err := service.ParseExchangeRatesCSV(f)
+	if err != nil {
+		logger.Fatal("Failed to parse exchange rates file", zap.String("file", *file), zap.Error(err))
+	}
+
+	// Initialize database
+	database, err := db.NewDatabase(&cfg.Database, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	rateRepo := repository.NewExchangeRateRepository(database, logger)
+	rateService := service.NewExchangeRateService(rateRepo, database, &cfg.Currency, logger)
+
+	if _, err := rateService.SetRates(ctx, rates, "file"); err != nil {
+		logger.Fatal("Failed to import exchange rates", zap.Error(err))
+	}
+
+	logger.Info("Exchange rate import completed successfully",
+		zap.String("file", *file),
+		zap.String("base_currency", cfg.Currency.Base),
+		zap.Int("rates", len(rates)))
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/apis"
+	"airline-booking/internal/config"
+	"airline-booking/internal/db"
+	"airline-booking/internal/repository"
+	"airline-booking/internal/service"
+)
+
+// manifest exports the advance passenger information (APIS) of a flight's
+// checked-in passengers, writing one manifest-<flight id>.<ext> file per
+// requested format to the output directory.
+func main() {
+	flightID := flag.Int64("flight-id", 0, "flight ID to export")
+	formats := flag.String("format", "csv,json,paxlst", "comma-separated formats: csv, json, paxlst")
+	outDir := flag.String("out", ".", "directory to write the files to")
+	flag.Parse()
+
+	if *flightID <= 0 {
+		fmt.Fprintln(os.Stderr, "usage: manifest -flight-id <id> [-format csv,json,paxlst] [-out dir]")
+		os.Exit(2)
+	}
+
+	var selected []apis.Format
+	for _, name := range strings.Split(*formats, ",") {
+		format, err := apis.ParseFormat(strings.TrimSpace(name))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		selected = append(selected, format)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Setup logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Initialize database
+	database, err := db.NewDatabase(&cfg.Database, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer database.Close()
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		logger.Fatal("Failed to create output directory", zap.String("dir", *outDir), zap.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	checkInService := service.NewCheckInService(
+		repository.NewCheckInRepository(database, logger),
+		repository.NewTicketRepository(database, logger),
+		repository.NewFlightRepository(database, logger),
+		repository.NewSeatRepository(database, logger),
+		repository.NewInventoryRepository(database, logger),
+		repository.NewAirportRepository(database, logger),
+		repository.NewAncillaryRepository(database, logger),
+		service.NewAuditService(repository.NewAuditRepository(database, logger), logger),
+		database,
+		&cfg.CheckIn,
+		logger,
+	)
+
+	for _, format := range selected {
+		data, err := checkInService.ExportManifest(ctx, *flightID, format)
+		if err != nil {
+			logger.Fatal("Failed to export passenger manifest", zap.Int64("flight_id", *flightID), zap.Error(err))
+		}
+
+		path := filepath.Join(*outDir, fmt.Sprintf("manifest-%d.%s", *flightID, format.Extension()))
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			logger.Fatal("Failed to write manifest", zap.String("path", path), zap.Error(err))
+		}
+		logger.Info("Manifest written", zap.String("path", path))
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/db"
+)
+
+const usage = `usage: migrate [-path dir] [-lock-timeout 5m] <command>
+
+commands:
+  up          apply all pending migrations
+  down N      roll back the last N migrations
+  goto V      migrate up or down to version V
+  version     print the current version
+  force V     mark version V as applied and clear the dirty flag, without
+              running it, after fixing a failed migration by hand`
+
+// migrate applies the migrations in migrations/, the only source of the
+// schema, holding the same advisory lock the API takes with
+// MIGRATE_ON_START.
+func main() {
+	flag.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	path := flag.String("path", "", "migrations directory (default MIGRATIONS_PATH)")
+	lockTimeout := flag.Duration("lock-timeout", 0, "how long to wait for another migration (default MIGRATE_LOCK_TIMEOUT_SECONDS)")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command := args[0]
+	run, err := parseCommand(args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	if *path == "" {
+		*path = cfg.Database.MigrationsPath
+	}
+	if *lockTimeout == 0 {
+		*lockTimeout = cfg.Database.MigrateLockTimeout
+	}
+
+	// Setup logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	ctx := context.Background()
+	migrator, err := db.NewMigrator(ctx, &cfg.Database, *path, logger)
+	if err != nil {
+		logger.Fatal("Failed to open migrations", zap.String("path", *path), zap.Error(err))
+	}
+	defer migrator.Close()
+
+	if run == nil {
+		printVersion(migrator, logger)
+		return
+	}
+
+	logger.Info("Waiting for the migration lock", zap.Duration("timeout", *lockTimeout))
+	if err := migrator.Lock(ctx, *lockTimeout); err != nil {
+		logger.Fatal("Failed to lock migrations", zap.Error(err))
+	}
+	err = run(migrator)
+	if unlockErr := migrator.Unlock(ctx); unlockErr != nil {
+		logger.Error("Failed to release the migration lock", zap.Error(unlockErr))
+	}
+	if err != nil {
+		logger.Fatal("Migration failed", zap.String("command", command), zap.Error(err))
+	}
+
+	printVersion(migrator, logger)
+}
+
+// parseCommand returns the migration to run for the command line args, or
+// nil for version, which only reads
+func parseCommand(args []string) (func(*db.Migrator) error, error) {
+	command := args[0]
+	switch command {
+	case "version", "up":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("%s takes no arguments", command)
+		}
+		if command == "up" {
+			return (*db.Migrator).Up, nil
+		}
+		return nil, nil
+	case "down", "goto", "force":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("%s needs exactly one number", command)
+		}
+		n, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid number %q", command, args[1])
+		}
+		switch command {
+		case "down":
+			return func(m *db.Migrator) error { return m.Down(int(n)) }, nil
+		case "goto":
+			return func(m *db.Migrator) error { return m.Goto(uint(n)) }, nil
+		default:
+			return func(m *db.Migrator) error { return m.Force(int(n)) }, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown command %q", command)
+}
+
+func printVersion(migrator *db.Migrator, logger *zap.Logger) {
+	version, dirty, err := migrator.Version()
+	if err != nil {
+		logger.Fatal("Failed to read migration version", zap.Error(err))
+	}
+	if dirty {
+		fmt.Printf("%d (dirty)\n", version)
+		return
+	}
+	fmt.Println(version)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/openapi"
+)
+
+// openapi converts the Swagger 2.0 document swag generates from the handler
+// annotations into the OpenAPI 3 document the API embeds and serves. Run it
+// through `make openapi`.
+func main() {
+	in := flag.String("in", "tmp/swagger/swagger.json", "Swagger 2.0 document generated by swag")
+	out := flag.String("out", "internal/openapi/openapi.json", "where to write the OpenAPI 3 document")
+	flag.Parse()
+
+	// Setup logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		logger.Fatal("Failed to read Swagger document", zap.String("file", *in), zap.Error(err))
+	}
+
+	doc, err := openapi.FromSwagger2(data)
+	if err != nil {
+		logger.Fatal("Failed to convert Swagger document", zap.String("file", *in), zap.Error(err))
+	}
+
+	if err := os.WriteFile(*out, doc, 0o644); err != nil {
+		logger.Fatal("Failed to write OpenAPI document", zap.String("file", *out), zap.Error(err))
+	}
+	logger.Info("OpenAPI document written", zap.String("file", *out))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/db"
+	"airline-booking/internal/repository"
+	"airline-booking/internal/service"
+)
+
+// payment-webhook-replay re-applies stored payment webhook events to their
+// tickets, either one event by provider event ID or every event still
+// pending, e.g. after a webhook arrived before its ticket was committed.
+func main() {
+	eventID := flag.String("event-id", "", "provider event ID to replay")
+	pending := flag.Bool("pending", false, "replay every unprocessed event")
+	flag.Parse()
+
+	if (*eventID == "") == !*pending {
+		fmt.Fprintln(os.Stderr, "usage: payment-webhook-replay -event-id <id> | -pending")
+		os.Exit(2)
+	}
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Setup logger
+	logger, _ := zap.NewDevelopment()
+	defer logger.Sync()
+
+	// Initialize database
+	database, err := db.NewDatabase(&cfg.Database, logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer database.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	eventRepo := repository.NewPaymentEventRepository(database, logger)
+	ticketRepo := repository.NewTicketRepository(database, logger)
+	auditService := service.NewAuditService(repository.NewAuditRepository(database, logger), logger)
+	webhookService := service.NewPaymentWebhookService(eventRepo, ticketRepo, auditService, database, &cfg.Payment, logger)
+
+	if *pending {
+		applied, err := webhookService.ReplayPending(ctx)
+		if err != nil {
+			logger.Fatal("Failed to replay pending payment events", zap.Int("applied", applied), zap.Error(err))
+		}
+		logger.Info("Pending payment events replayed", zap.Int("applied", applied))
+		return
+	}
+
+	processed, err := webhookService.ReplayEvent(ctx, *eventID)
+	if err != nil {
+		logger.Fatal("Failed to replay payment event", zap.String("event_id", *eventID), zap.Error(err))
+	}
+	logger.Info("Payment event replayed", zap.String("event_id", *eventID), zap.Bool("processed", processed))
+}
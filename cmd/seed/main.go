@@ -75,6 +75,7 @@ func getSampleFlights() []models.Flight {
 			Airline:       "AA",
 			Aircraft:      "Boeing 737",
 			FareClass:     "economy",
+			BasePrice:     29900,
 		},
 		{
 			Origin:        "LAX",
@@ -84,6 +85,7 @@ func getSampleFlights() []models.Flight {
 			Airline:       "AA",
 			Aircraft:      "Boeing 737",
 			FareClass:     "economy",
+			BasePrice:     29900,
 		},
 		{
 			Origin:        "JFK",
@@ -93,6 +95,7 @@ func getSampleFlights() []models.Flight {
 			Airline:       "DL",
 			Aircraft:      "Airbus A320",
 			FareClass:     "business",
+			BasePrice:     89900,
 		},
 		{
 			Origin:        "MIA",
@@ -102,6 +105,7 @@ func getSampleFlights() []models.Flight {
 			Airline:       "DL",
 			Aircraft:      "Airbus A320",
 			FareClass:     "business",
+			BasePrice:     89900,
 		},
 		{
 			Origin:        "ORD",
@@ -111,6 +115,7 @@ func getSampleFlights() []models.Flight {
 			Airline:       "UA",
 			Aircraft:      "Boeing 777",
 			FareClass:     "economy",
+			BasePrice:     29900,
 		},
 		{
 			Origin:        "DFW",
@@ -120,6 +125,7 @@ func getSampleFlights() []models.Flight {
 			Airline:       "UA",
 			Aircraft:      "Boeing 777",
 			FareClass:     "first",
+			BasePrice:     149900,
 		},
 	}
 }
@@ -122,16 +122,16 @@ func seedDatabase(database *db.Database) error {
 		(2, '8B', 'user789', DATE_ADD(NOW(), INTERVAL 12 MINUTE)),
 		(3, '2A', 'user111', DATE_ADD(NOW(), INTERVAL 8 MINUTE))`,
 
-		`INSERT INTO tickets (flight_id, seat_no, user_id, price_amount, currency, pnr_code, payment_ref) VALUES
-		(1, '10A', 'customer001', 29900, 'USD', 'ABC001', 'pay_001_12345'),
-		(1, '10B', 'customer002', 29900, 'USD', 'ABC002', 'pay_002_12346'),
-		(1, '1A', 'customer003', 149900, 'USD', 'ABC003', 'pay_003_12347'),
-		(2, '5A', 'customer004', 31900, 'USD', 'DEF001', 'pay_004_12348'),
-		(2, '5B', 'customer005', 31900, 'USD', 'DEF002', 'pay_005_12349'),
-		(3, '1A', 'customer006', 89900, 'USD', 'GHI001', 'pay_006_12350'),
-		(3, '10A', 'customer007', 49900, 'USD', 'GHI002', 'pay_007_12351'),
-		(4, '15A', 'customer008', 27900, 'USD', 'JKL001', 'pay_008_12352'),
-		(5, '5A', 'customer009', 19900, 'USD', 'MNO001', 'pay_009_12353')`,
+		`INSERT INTO tickets (flight_id, seat_no, user_id, price_amount, currency, base_price_amount, base_currency, pnr_code, payment_ref) VALUES
+		(1, '10A', 'customer001', 29900, 'USD', 29900, 'USD', 'ABC001', 'pay_001_12345'),
+		(1, '10B', 'customer002', 29900, 'USD', 29900, 'USD', 'ABC002', 'pay_002_12346'),
+		(1, '1A', 'customer003', 149900, 'USD', 149900, 'USD', 'ABC003', 'pay_003_12347'),
+		(2, '5A', 'customer004', 31900, 'USD', 31900, 'USD', 'DEF001', 'pay_004_12348'),
+		(2, '5B', 'customer005', 31900, 'USD', 31900, 'USD', 'DEF002', 'pay_005_12349'),
+		(3, '1A', 'customer006', 89900, 'USD', 89900, 'USD', 'GHI001', 'pay_006_12350'),
+		(3, '10A', 'customer007', 49900, 'USD', 49900, 'USD', 'GHI002', 'pay_007_12351'),
+		(4, '15A', 'customer008', 27900, 'USD', 27900, 'USD', 'JKL001', 'pay_008_12352'),
+		(5, '5A', 'customer009', 19900, 'USD', 19900, 'USD', 'MNO001', 'pay_009_12353')`,
 	}
 
 	for _, query := range holdsAndTicketsQueries {
@@ -257,7 +257,7 @@ func seedFlightsToES(esClient *es.Client, database *db.Database, logger *zap.Log
 			Airline:       flight["airline"].(string),
 			Aircraft:      flight["aircraft"].(string),
 			FareClass:     flight["fare_class"].(string),
-			BasePrice:     399.99, // Default price
+			BasePrice:     flight["base_price"].(int64),
 		}
 	}
 
@@ -331,7 +331,7 @@ func seedTicketsToES(esClient *es.Client, database *db.Database, logger *zap.Log
 func getFlightsFromDB(database *db.Database) ([]map[string]interface{}, error) {
 	query := `
 		SELECT id, origin, destination, departure_time, arrival_time, 
-		       airline, aircraft, fare_class, created_at, updated_at
+		       airline, aircraft, fare_class, base_price, created_at, updated_at
 		FROM flights
 		ORDER BY id
 	`
@@ -346,10 +346,11 @@ func getFlightsFromDB(database *db.Database) ([]map[string]interface{}, error) {
 	for rows.Next() {
 		var id int64
 		var origin, destination, airline, aircraft, fareClass string
+		var basePrice int64
 		var departureTime, arrivalTime, createdAt, updatedAt time.Time
 
 		err := rows.Scan(&id, &origin, &destination, &departureTime, &arrivalTime,
-			&airline, &aircraft, &fareClass, &createdAt, &updatedAt)
+			&airline, &aircraft, &fareClass, &basePrice, &createdAt, &updatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -363,6 +364,7 @@ func getFlightsFromDB(database *db.Database) ([]map[string]interface{}, error) {
 			"airline":        airline,
 			"aircraft":       aircraft,
 			"fare_class":     fareClass,
+			"base_price":     basePrice,
 			"created_at":     createdAt,
 			"updated_at":     updatedAt,
 		}
@@ -0,0 +1,305 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/apis"
+	"airline-booking/internal/models"
+	"airline-booking/internal/service"
+)
+
+// AdminHandler serves the operational endpoints under /api/v1/admin
+type AdminHandler struct {
+	overbookingService *service.OverbookingService
+	rateService        *service.ExchangeRateService
+	promotionService   *service.PromotionService
+	checkInService     *service.CheckInService
+	auditService       *service.AuditService
+	logger             *zap.Logger
+}
+
+func NewAdminHandler(overbookingService *service.OverbookingService, rateService *service.ExchangeRateService, promotionService *service.PromotionService, checkInService *service.CheckInService, auditService *service.AuditService, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{
+		overbookingService: overbookingService,
+		rateService:        rateService,
+		promotionService:   promotionService,
+		checkInService:     checkInService,
+		auditService:       auditService,
+		logger:             logger,
+	}
+}
+
+// SetOverbookingLimit godoc
+// @Summary Set a cabin's overbooking limit
+// @Description Set how many seatless tickets a cabin may sell beyond its physical capacity
+// @Tags admin
+// @Security AdminToken
+// @Accept json
+// @Produce json
+// @Param flight_id path int true "Flight ID"
+// @Param cabin_class path string true "Cabin class"
+// @Param request body models.SetOverbookingLimitRequest true "Overbooking limit"
+// @Success 204
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/flights/{flight_id}/cabins/{cabin_class}/overbooking [put]
+func (h *AdminHandler) SetOverbookingLimit(c *gin.Context) {
+	flightID, err := strconv.ParseInt(c.Param("flight_id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_FLIGHT_ID", "Invalid flight ID", nil)
+		return
+	}
+
+	var req models.SetOverbookingLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, "Invalid request body")
+		return
+	}
+
+	err = h.overbookingService.SetOverbookingLimit(c.Request.Context(), flightID, c.Param("cabin_class"), *req.Limit)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to set overbooking limit")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// FlightsAtRisk godoc
+// @Summary Overbooked flights at risk
+// @Description List upcoming cabins where tickets and active holds outnumber the seats
+// @Tags admin
+// @Security AdminToken
+// @Produce json
+// @Success 200 {object} models.FlightsAtRiskResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/overbooking/at-risk [get]
+func (h *AdminHandler) FlightsAtRisk(c *gin.Context) {
+	response, err := h.overbookingService.FlightsAtRisk(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to list flights at risk")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeniedBoardingList godoc
+// @Summary Denied-boarding volunteer list
+// @Description Rank a flight's passengers for voluntary denied boarding: lowest fare first, then most recent booking
+// @Tags admin
+// @Security AdminToken
+// @Produce json
+// @Param flight_id path int true "Flight ID"
+// @Param cabin_class query string false "Restrict to one cabin class"
+// @Success 200 {object} models.DeniedBoardingListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/flights/{flight_id}/denied-boarding [get]
+func (h *AdminHandler) DeniedBoardingList(c *gin.Context) {
+	flightID, err := strconv.ParseInt(c.Param("flight_id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_FLIGHT_ID", "Invalid flight ID", nil)
+		return
+	}
+
+	response, err := h.overbookingService.DeniedBoardingList(c.Request.Context(), flightID, c.Query("cabin_class"))
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to build denied boarding list")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ExportPassengerManifest godoc
+// @Summary Export a flight's passenger manifest (APIS)
+// @Description Download the advance passenger information of a flight's checked-in passengers (names, travel documents, seats and PNRs) as CSV, JSON or a UN/EDIFACT PAXLST message
+// @Tags admin
+// @Security AdminToken
+// @Produce text/csv
+// @Produce json
+// @Produce application/edifact
+// @Param flight_id path int true "Flight ID"
+// @Param format query string false "csv (default), json or paxlst"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/flights/{flight_id}/passenger-manifest [get]
+func (h *AdminHandler) ExportPassengerManifest(c *gin.Context) {
+	flightID, err := strconv.ParseInt(c.Param("flight_id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_FLIGHT_ID", "Invalid flight ID", nil)
+		return
+	}
+
+	format, err := apis.ParseFormat(c.DefaultQuery("format", string(apis.CSV)))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_FORMAT", "format must be csv, json or paxlst", nil)
+		return
+	}
+
+	data, err := h.checkInService.ExportManifest(c.Request.Context(), flightID, format)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to export passenger manifest")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=manifest-%d.%s", flightID, format.Extension()))
+	c.Data(http.StatusOK, format.ContentType(), data)
+}
+
+// SetExchangeRates godoc
+// @Summary Set exchange rates
+// @Description Store rates from the base currency, effective now or from a given time. A rate for an existing pair and effective time is replaced.
+// @Tags admin
+// @Security AdminToken
+// @Accept json
+// @Produce json
+// @Param request body models.SetExchangeRatesRequest true "Exchange rates"
+// @Success 200 {object} models.ExchangeRatesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/exchange-rates [put]
+func (h *AdminHandler) SetExchangeRates(c *gin.Context) {
+	var req models.SetExchangeRatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, "Invalid request body")
+		return
+	}
+
+	rates, err := h.rateService.SetRates(c.Request.Context(), req.Rates, "admin")
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to set exchange rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ExchangeRatesResponse{
+		BaseCurrency: h.rateService.BaseCurrency(),
+		Rates:        rates,
+	})
+}
+
+// ListExchangeRates godoc
+// @Summary List exchange rates
+// @Description List every rate from the base currency, newest first per currency, including scheduled rates
+// @Tags admin
+// @Security AdminToken
+// @Produce json
+// @Success 200 {object} models.ExchangeRatesResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/exchange-rates [get]
+func (h *AdminHandler) ListExchangeRates(c *gin.Context) {
+	response, err := h.rateService.ListRates(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to list exchange rates")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreatePromotion godoc
+// @Summary Create a promotion
+// @Description Create a promo code. Percent discounts are in basis points of the base fare, fixed discounts in minor units of the base currency.
+// @Tags admin
+// @Security AdminToken
+// @Accept json
+// @Produce json
+// @Param request body models.CreatePromotionRequest true "Promotion"
+// @Success 201 {object} models.Promotion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/promotions [post]
+func (h *AdminHandler) CreatePromotion(c *gin.Context) {
+	var req models.CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, "Invalid request body")
+		return
+	}
+
+	promotion, err := h.promotionService.CreatePromotion(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to create promotion")
+		return
+	}
+
+	c.JSON(http.StatusCreated, promotion)
+}
+
+// ListPromotions godoc
+// @Summary List promotions
+// @Description List every promotion with its redemption count, newest first
+// @Tags admin
+// @Security AdminToken
+// @Produce json
+// @Success 200 {object} models.PromotionsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/promotions [get]
+func (h *AdminHandler) ListPromotions(c *gin.Context) {
+	response, err := h.promotionService.ListPromotions(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to list promotions")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListAuditEvents godoc
+// @Summary Booking audit log
+// @Description List booking state changes (holds, confirmations, cancellations, ancillary purchases), newest first, with who made them, from which request and the state before and after
+// @Tags admin
+// @Security AdminToken
+// @Produce json
+// @Param pnr query string false "Ticket PNR"
+// @Param user query string false "Actor: the User-ID that made the change, or system"
+// @Param flight_id query int false "Flight ID"
+// @Param from query string false "Changes at or after this RFC 3339 time"
+// @Param to query string false "Changes before this RFC 3339 time"
+// @Param limit query int false "Maximum events, 1 to 1000 (default 100)"
+// @Success 200 {object} models.AuditEventsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/audit [get]
+func (h *AdminHandler) ListAuditEvents(c *gin.Context) {
+	var filter models.AuditEventFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		respondBindError(c, err, "Invalid query parameters")
+		return
+	}
+
+	response, err := h.auditService.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to list audit events")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
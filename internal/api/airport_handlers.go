@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/service"
+)
+
+type AirportHandler struct {
+	airportService *service.AirportService
+	logger         *zap.Logger
+}
+
+func NewAirportHandler(airportService *service.AirportService, logger *zap.Logger) *AirportHandler {
+	return &AirportHandler{
+		airportService: airportService,
+		logger:         logger,
+	}
+}
+
+// SuggestAirports godoc
+// @Summary Airport and city autocomplete
+// @Description Suggest airports whose IATA code, name or city starts with the typed text. Exact code matches rank first, then by popularity.
+// @Tags airports
+// @Produce json
+// @Param q query string true "Partial airport code, name or city"
+// @Param size query int false "Maximum number of suggestions (default: 10, max: 25)"
+// @Success 200 {object} models.AirportSuggestResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/airports/suggest [get]
+func (h *AirportHandler) SuggestAirports(c *gin.Context) {
+	var req models.AirportSuggestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err, "Invalid query parameters")
+		return
+	}
+
+	response, err := h.airportService.Suggest(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to suggest airports")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenAuthMiddleware lets through requests carrying one of tokens as
+// "Authorization: Bearer <token>". Requests without a token get a 401,
+// those with any other token a 403.
+func tokenAuthMiddleware(tokens ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="airline-booking"`)
+			respondError(c, http.StatusUnauthorized, "UNAUTHORIZED", "A bearer token is required", nil)
+			c.Abort()
+			return
+		}
+		if !tokenAllowed(token, tokens) {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "The token is not allowed to use this route", nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// adminAuthMiddleware guards the /admin operations routes
+func (r *Router) adminAuthMiddleware() gin.HandlerFunc {
+	return tokenAuthMiddleware(r.config.Auth.AdminToken)
+}
+
+// gateAuthMiddleware guards the gate routes, which gate staff and admins use
+func (r *Router) gateAuthMiddleware() gin.HandlerFunc {
+	return tokenAuthMiddleware(r.config.Auth.GateToken, r.config.Auth.AdminToken)
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// tokenAllowed compares token with each allowed token in constant time.
+// Empty tokens never match, so routes whose token is unset stay closed.
+func tokenAllowed(token string, allowed []string) bool {
+	match := 0
+	for _, candidate := range allowed {
+		if candidate != "" {
+			match |= subtle.ConstantTimeCompare([]byte(token), []byte(candidate))
+		}
+	}
+	return match == 1
+}
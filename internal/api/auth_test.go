@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/models"
+)
+
+func newTestRouter(t *testing.T) *Router {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	cfg.Auth.AdminToken = "admin-test-token"
+	cfg.Auth.GateToken = "gate-test-token"
+	return NewRouter(Handlers{}, cfg, zap.NewNop())
+}
+
+// serveRoute sends a request through the router set up by Router.Setup
+func serveRoute(t *testing.T, router *Router, method, path, authorization string) (int, models.ErrorResponse) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.GetEngine().ServeHTTP(w, req)
+
+	var response models.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &response)
+	return w.Code, response
+}
+
+func TestAdminRoutesRequireToken(t *testing.T) {
+	router := newTestRouter(t)
+	router.Setup()
+
+	routes := []struct{ method, path string }{
+		{http.MethodPut, "/api/v1/admin/flights/1/cabins/economy/overbooking"},
+		{http.MethodGet, "/api/v1/admin/flights/1/denied-boarding"},
+		{http.MethodGet, "/api/v1/admin/overbooking/at-risk"},
+		{http.MethodGet, "/api/v1/admin/exchange-rates"},
+		{http.MethodPut, "/api/v1/admin/exchange-rates"},
+		{http.MethodGet, "/api/v1/admin/promotions"},
+		{http.MethodPost, "/api/v1/admin/promotions"},
+		{http.MethodGet, "/api/v1/admin/flights/1/passenger-manifest"},
+		{http.MethodGet, "/api/v1/admin/audit"},
+	}
+	for _, route := range routes {
+		status, response := serveRoute(t, router, route.method, route.path, "")
+		if status != http.StatusUnauthorized || response.Code != "UNAUTHORIZED" {
+			t.Errorf("%s %s without a token: got %d %s, want 401 UNAUTHORIZED", route.method, route.path, status, response.Code)
+		}
+		status, response = serveRoute(t, router, route.method, route.path, "Bearer not-the-token")
+		if status != http.StatusForbidden || response.Code != "FORBIDDEN" {
+			t.Errorf("%s %s with a wrong token: got %d %s, want 403 FORBIDDEN", route.method, route.path, status, response.Code)
+		}
+	}
+}
+
+func TestGateRoutesRequireToken(t *testing.T) {
+	router := newTestRouter(t)
+	router.Setup()
+
+	routes := []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/flights/1/boarding/scan"},
+		{http.MethodGet, "/api/v1/flights/1/manifest"},
+	}
+	for _, route := range routes {
+		status, response := serveRoute(t, router, route.method, route.path, "")
+		if status != http.StatusUnauthorized || response.Code != "UNAUTHORIZED" {
+			t.Errorf("%s %s without a token: got %d %s, want 401 UNAUTHORIZED", route.method, route.path, status, response.Code)
+		}
+		status, response = serveRoute(t, router, route.method, route.path, "Bearer not-the-token")
+		if status != http.StatusForbidden || response.Code != "FORBIDDEN" {
+			t.Errorf("%s %s with a wrong token: got %d %s, want 403 FORBIDDEN", route.method, route.path, status, response.Code)
+		}
+	}
+
+	// The gate token doesn't open the admin routes
+	status, _ := serveRoute(t, router, http.MethodGet, "/api/v1/admin/overbooking/at-risk", "Bearer "+router.config.Auth.GateToken)
+	if status != http.StatusForbidden {
+		t.Errorf("admin route with the gate token: got %d, want 403", status)
+	}
+}
+
+func TestTokenAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", tokenAuthMiddleware("secret", ""), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	tests := []struct {
+		authorization string
+		status        int
+	}{
+		{"Bearer secret", http.StatusNoContent},
+		{"bearer secret", http.StatusNoContent},
+		{"", http.StatusUnauthorized},
+		{"Basic c2VjcmV0", http.StatusUnauthorized},
+		{"Bearer ", http.StatusUnauthorized},
+		{"Bearer secre", http.StatusForbidden},
+		{"Bearer secret2", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("Authorization %q: got %d, want %d", tt.authorization, w.Code, tt.status)
+		}
+	}
+
+	// An unset token keeps its routes closed
+	closed := gin.New()
+	closed.GET("/", tokenAuthMiddleware(""), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+	closed.ServeHTTP(w, req)
+	if w.Code == http.StatusNoContent {
+		t.Errorf("route with an unset token let a request through")
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/boardingpass"
+	"airline-booking/internal/models"
+	"airline-booking/internal/service"
+)
+
+// CheckInHandler serves online check-in, boarding passes and the gate
+type CheckInHandler struct {
+	checkInService *service.CheckInService
+	logger         *zap.Logger
+}
+
+func NewCheckInHandler(checkInService *service.CheckInService, logger *zap.Logger) *CheckInHandler {
+	return &CheckInHandler{
+		checkInService: checkInService,
+		logger:         logger,
+	}
+}
+
+// CheckIn godoc
+// @Summary Check in for a flight
+// @Description Check in a confirmed ticket between CHECKIN_OPENS_HOURS and CHECKIN_CLOSES_MINUTES before departure. The passenger's travel document is validated (a passport is required on international flights, and it must be valid until arrival). seat_no moves the ticket to another free seat in its cabin; seatless tickets are given a seat here.
+// @Tags check-in
+// @Accept json
+// @Produce json
+// @Param User-ID header string true "User ID that owns the ticket"
+// @Param pnr_code path string true "PNR code"
+// @Param request body models.CheckInRequest true "Passenger and travel document"
+// @Success 201 {object} models.BoardingPass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/tickets/{pnr_code}/check-in [post]
+func (h *CheckInHandler) CheckIn(c *gin.Context) {
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, "Invalid request body")
+		return
+	}
+
+	userID := c.GetHeader("User-ID")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "MISSING_USER_ID", "User-ID header is required", nil)
+		return
+	}
+
+	pass, err := h.checkInService.CheckIn(c.Request.Context(), c.Param("pnr_code"), req, userID)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to check in")
+		return
+	}
+
+	c.JSON(http.StatusCreated, pass)
+}
+
+// GetBoardingPass godoc
+// @Summary Get a boarding pass
+// @Description Retrieve the boarding pass of a checked-in ticket as JSON with its IATA BCBP barcode data, as a PNG of the barcode, or as a printable PDF
+// @Tags check-in
+// @Produce json
+// @Produce png
+// @Produce application/pdf
+// @Param User-ID header string true "User ID that owns the ticket"
+// @Param pnr_code path string true "PNR code"
+// @Param format query string false "json (default), png or pdf"
+// @Param barcode query string false "pdf417 (default) or qr"
+// @Success 200 {object} models.BoardingPass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/tickets/{pnr_code}/boarding-pass [get]
+func (h *CheckInHandler) GetBoardingPass(c *gin.Context) {
+	userID := c.GetHeader("User-ID")
+	if userID == "" {
+		respondError(c, http.StatusBadRequest, "MISSING_USER_ID", "User-ID header is required", nil)
+		return
+	}
+
+	symbology, err := boardingpass.ParseSymbology(c.Query("barcode"))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_BARCODE", err.Error(), nil)
+		return
+	}
+
+	var contentType string
+	format := service.BoardingPassFormat(strings.ToLower(c.DefaultQuery("format", "json")))
+	switch format {
+	case "json":
+		pass, err := h.checkInService.GetBoardingPass(c.Request.Context(), c.Param("pnr_code"), userID)
+		if err != nil {
+			respondServiceError(c, h.logger, err, "Failed to get boarding pass")
+			return
+		}
+		c.JSON(http.StatusOK, pass)
+		return
+	case service.BoardingPassPNG:
+		contentType = "image/png"
+	case service.BoardingPassPDF:
+		contentType = "application/pdf"
+	default:
+		respondError(c, http.StatusBadRequest, "INVALID_FORMAT", "format must be json, png or pdf", nil)
+		return
+	}
+
+	data, err := h.checkInService.RenderBoardingPass(c.Request.Context(), c.Param("pnr_code"), userID, format, symbology)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to render boarding pass")
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ScanBoardingPass godoc
+// @Summary Board a passenger at the gate
+// @Description Parse the BCBP barcode read from a boarding pass, check it is the current pass of a checked-in ticket on this flight and record the passenger as boarded. Passes for another flight, superseded passes and repeated scans are rejected.
+// @Tags boarding
+// @Security GateToken
+// @Security AdminToken
+// @Accept json
+// @Produce json
+// @Param flight_id path int true "Flight ID of the gate"
+// @Param request body models.ScanBoardingPassRequest true "Scanned barcode data"
+// @Success 200 {object} models.BoardingScanResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/flights/{flight_id}/boarding/scan [post]
+func (h *CheckInHandler) ScanBoardingPass(c *gin.Context) {
+	flightID, err := strconv.ParseInt(c.Param("flight_id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_FLIGHT_ID", "Invalid flight ID", nil)
+		return
+	}
+
+	var req models.ScanBoardingPassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, "Invalid request body")
+		return
+	}
+
+	response, err := h.checkInService.ScanBoardingPass(c.Request.Context(), flightID, req.Barcode)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to scan boarding pass")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFlightManifest godoc
+// @Summary Get a flight's passenger manifest
+// @Description List the confirmed tickets of a flight with each passenger's status (booked, checked_in, boarded or no_show) and totals per status
+// @Tags boarding
+// @Security GateToken
+// @Security AdminToken
+// @Produce json
+// @Param flight_id path int true "Flight ID"
+// @Success 200 {object} models.FlightManifest
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/flights/{flight_id}/manifest [get]
+func (h *CheckInHandler) GetFlightManifest(c *gin.Context) {
+	flightID, err := strconv.ParseInt(c.Param("flight_id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_FLIGHT_ID", "Invalid flight ID", nil)
+		return
+	}
+
+	manifest, err := h.checkInService.GetFlightManifest(c.Request.Context(), flightID)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to get flight manifest")
+		return
+	}
+
+	c.JSON(http.StatusOK, manifest)
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/boardingpass"
+	"airline-booking/internal/models"
+	"airline-booking/internal/payment"
+	"airline-booking/internal/service"
+	"airline-booking/internal/tracing"
+)
+
+// errorMapping is how a domain error is reported to clients. The message is
+// the error's own text unless one is set.
+type errorMapping struct {
+	err     error
+	status  int
+	code    string
+	message string
+}
+
+// errorCatalog maps the errors handlers can return to their HTTP status and
+// ErrorResponse.Code. Wrapped errors match, and the first match wins.
+var errorCatalog = []errorMapping{
+	// Requests the service rejects
+	{err: service.ErrValidation, status: http.StatusBadRequest, code: "VALIDATION_FAILED"},
+	{err: service.ErrInvalidDate, status: http.StatusBadRequest, code: "INVALID_DATE"},
+	{err: service.ErrUnknownAirport, status: http.StatusBadRequest, code: "UNKNOWN_AIRPORT"},
+	{err: service.ErrInvalidBasePrice, status: http.StatusBadRequest, code: "INVALID_BASE_PRICE"},
+	{err: service.ErrUnsupportedCurrency, status: http.StatusBadRequest, code: "UNSUPPORTED_CURRENCY"},
+	{err: service.ErrInvalidExchangeRate, status: http.StatusBadRequest, code: "INVALID_EXCHANGE_RATE"},
+	{err: service.ErrInvalidPromotion, status: http.StatusBadRequest, code: "INVALID_PROMOTION"},
+	{err: service.ErrInvalidAuditFilter, status: http.StatusBadRequest, code: "INVALID_AUDIT_FILTER"},
+	{err: service.ErrInvalidTravelDocument, status: http.StatusBadRequest, code: "INVALID_DOCUMENT"},
+	{err: boardingpass.ErrInvalidBCBP, status: http.StatusBadRequest, code: "INVALID_BARCODE"},
+
+	// Missing or someone else's
+	{err: service.ErrFlightNotFound, status: http.StatusNotFound, code: "FLIGHT_NOT_FOUND"},
+	{err: service.ErrSeatNotFound, status: http.StatusNotFound, code: "SEAT_NOT_FOUND"},
+	{err: service.ErrCabinNotFound, status: http.StatusNotFound, code: "CABIN_NOT_FOUND"},
+	{err: service.ErrTicketNotFound, status: http.StatusNotFound, code: "TICKET_NOT_FOUND"},
+	{err: service.ErrTicketNotOwned, status: http.StatusForbidden, code: "TICKET_NOT_OWNED"},
+
+	// Seats and holds
+	{err: service.ErrSeatHeld, status: http.StatusConflict, code: "SEAT_UNAVAILABLE"},
+	{err: service.ErrSeatSold, status: http.StatusConflict, code: "SEAT_UNAVAILABLE"},
+	{err: service.ErrSeatUnavailable, status: http.StatusConflict, code: "SEAT_UNAVAILABLE"},
+	{err: service.ErrNoSeatAvailable, status: http.StatusConflict, code: "NO_SEAT_AVAILABLE"},
+	{err: service.ErrNoValidHold, status: http.StatusConflict, code: "NO_VALID_HOLD"},
+	{err: service.ErrCabinFull, status: http.StatusConflict, code: "CABIN_FULL"},
+
+	// Tickets, check-in and boarding
+	{err: service.ErrTicketNotActive, status: http.StatusConflict, code: "TICKET_NOT_ACTIVE"},
+	{err: service.ErrCheckInNotOpen, status: http.StatusConflict, code: "CHECKIN_NOT_OPEN"},
+	{err: service.ErrCheckInClosed, status: http.StatusConflict, code: "CHECKIN_CLOSED"},
+	{err: service.ErrAlreadyCheckedIn, status: http.StatusConflict, code: "ALREADY_CHECKED_IN"},
+	{err: service.ErrNotCheckedIn, status: http.StatusConflict, code: "NOT_CHECKED_IN"},
+	{err: service.ErrWrongFlight, status: http.StatusConflict, code: "WRONG_FLIGHT"},
+	{err: service.ErrBoardingPassMismatch, status: http.StatusConflict, code: "BOARDING_PASS_MISMATCH"},
+	{err: service.ErrAlreadyBoarded, status: http.StatusConflict, code: "ALREADY_BOARDED"},
+
+	// Ancillaries
+	{err: service.ErrAncillaryNotOffered, status: http.StatusBadRequest, code: "ANCILLARY_NOT_AVAILABLE"},
+	{err: service.ErrAncillaryLimitExceeded, status: http.StatusBadRequest, code: "ANCILLARY_LIMIT_EXCEEDED"},
+	{err: service.ErrAncillarySoldOut, status: http.StatusConflict, code: "ANCILLARY_SOLD_OUT"},
+
+	// Payments
+	{err: payment.ErrTimeout, status: http.StatusGatewayTimeout, code: "PAYMENT_TIMEOUT", message: "Payment provider did not respond, the payment was not completed"},
+	{err: payment.ErrInvalidSignature, status: http.StatusUnauthorized, code: "INVALID_SIGNATURE", message: "Invalid webhook signature"},
+	{err: payment.ErrInvalidEvent, status: http.StatusBadRequest, code: "INVALID_EVENT"},
+}
+
+// respondServiceError writes the response for an error returned by a
+// service. Errors outside the catalog are logged and answered with a 500
+// and message, without their details.
+func respondServiceError(c *gin.Context, logger *zap.Logger, err error, message string) {
+	if respondKnownError(c, err) {
+		return
+	}
+	logger.Error(message, append(requestLogFields(c.Request.Context()), zap.Error(err))...)
+	respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", message, nil)
+}
+
+// respondKnownError writes the response for an error the client can act on
+// and reports whether err was one
+func respondKnownError(c *gin.Context, err error) bool {
+	var promoErr *service.PromoCodeError
+	var decline *payment.DeclineError
+	var challenge *payment.ChallengeError
+	switch {
+	// A code that has run out of uses is a conflict; any other rejection
+	// is a bad request
+	case errors.As(err, &promoErr):
+		details := map[string]string{
+			"promo_code": promoErr.Code,
+			"reason":     promoErr.Reason,
+		}
+		if promoErr.Exhausted() {
+			respondError(c, http.StatusConflict, "PROMO_CODE_EXHAUSTED", promoErr.Error(), details)
+		} else {
+			respondError(c, http.StatusBadRequest, "PROMO_CODE_INVALID", promoErr.Error(), details)
+		}
+		return true
+	case errors.As(err, &decline):
+		respondError(c, http.StatusPaymentRequired, "PAYMENT_DECLINED", decline.Message, map[string]string{
+			"decline_code": decline.Code,
+		})
+		return true
+	case errors.As(err, &challenge):
+		respondError(c, http.StatusPaymentRequired, "PAYMENT_CHALLENGE_REQUIRED", "Complete the 3-D Secure challenge and retry with challenge_id", map[string]string{
+			"challenge_id": challenge.ChallengeID,
+			"redirect_url": challenge.RedirectURL,
+		})
+		return true
+	}
+
+	for _, mapping := range errorCatalog {
+		if errors.Is(err, mapping.err) {
+			message := mapping.message
+			if message == "" {
+				message = err.Error()
+			}
+			respondError(c, mapping.status, mapping.code, message, nil)
+			return true
+		}
+	}
+	return false
+}
+
+func respondError(c *gin.Context, statusCode int, code, message string, details interface{}) {
+	ctx := c.Request.Context()
+	response := models.ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: service.RequestInfoFrom(ctx).ID,
+		TraceID:   tracing.TraceID(ctx),
+	}
+	c.JSON(statusCode, response)
+}
+
+// requestLogFields identify the request and trace a log line was written in
+func requestLogFields(ctx context.Context) []zap.Field {
+	fields := tracing.LogFields(ctx)
+	if id := service.RequestInfoFrom(ctx).ID; id != "" {
+		fields = append([]zap.Field{zap.String("request_id", id)}, fields...)
+	}
+	return fields
+}
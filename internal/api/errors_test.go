@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/service"
+)
+
+// serveError answers one request through requestIDMiddleware with err
+func serveError(t *testing.T, err error, requestID string) (*httptest.ResponseRecorder, models.ErrorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := &Router{}
+	engine := gin.New()
+	engine.Use(r.requestIDMiddleware())
+	engine.GET("/", func(c *gin.Context) {
+		respondServiceError(c, zap.NewNop(), err, "Failed to do it")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if requestID != "" {
+		req.Header.Set(requestIDHeader, requestID)
+	}
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	var body models.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body.String(), err)
+	}
+	return w, body
+}
+
+func TestRespondServiceError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{"flight not found", service.ErrFlightNotFound, http.StatusNotFound, "FLIGHT_NOT_FOUND", "flight not found"},
+		{"wrapped", fmt.Errorf("loading flight: %w", service.ErrFlightNotFound), http.StatusNotFound, "FLIGHT_NOT_FOUND", "loading flight: flight not found"},
+		{"seat held", service.ErrSeatHeld, http.StatusConflict, "SEAT_UNAVAILABLE", service.ErrSeatHeld.Error()},
+		{"seat sold", service.ErrSeatSold, http.StatusConflict, "SEAT_UNAVAILABLE", "seat is already sold"},
+		{"no valid hold", service.ErrNoValidHold, http.StatusConflict, "NO_VALID_HOLD", service.ErrNoValidHold.Error()},
+		{"validation", fmt.Errorf("%w: arrival must be after departure", service.ErrValidation), http.StatusBadRequest, "VALIDATION_FAILED", "validation failed: arrival must be after departure"},
+		{"unknown", errors.New("connection refused"), http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to do it"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, body := serveError(t, tt.err, "req-123")
+			if w.Code != tt.status || body.Code != tt.code || body.Message != tt.message {
+				t.Fatalf("got %d %s %q, want %d %s %q", w.Code, body.Code, body.Message, tt.status, tt.code, tt.message)
+			}
+			if body.RequestID != "req-123" {
+				t.Fatalf("got request_id %q, want req-123", body.RequestID)
+			}
+		})
+	}
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	w, body := serveError(t, service.ErrFlightNotFound, "")
+	generated := w.Header().Get(requestIDHeader)
+	if len(generated) != 32 || body.RequestID != generated {
+		t.Fatalf("got header %q and request_id %q, want the same generated ID", generated, body.RequestID)
+	}
+
+	for _, id := range []string{"has space", strings.Repeat("a", maxRequestIDLength+1), "tab\there"} {
+		w, body = serveError(t, service.ErrFlightNotFound, id)
+		if got := w.Header().Get(requestIDHeader); got == id || body.RequestID != got {
+			t.Fatalf("got header %q and request_id %q for %q, want a generated ID", got, body.RequestID, id)
+		}
+	}
+
+	w, _ = serveError(t, service.ErrFlightNotFound, "abc-123")
+	if got := w.Header().Get(requestIDHeader); got != "abc-123" {
+		t.Fatalf("got header %q, want the caller's abc-123", got)
+	}
+}
@@ -36,11 +36,11 @@ func NewBookingHandler(bookingService *service.BookingService, logger *zap.Logge
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /holds [post]
+// @Router /api/v1/holds [post]
 func (h *BookingHandler) CreateHold(c *gin.Context) {
 	var req models.CreateHoldRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		respondBindError(c, err, "Invalid request body")
 		return
 	}
 	
@@ -59,12 +59,7 @@ func (h *BookingHandler) CreateHold(c *gin.Context) {
 	
 	response, err := h.bookingService.CreateHold(c.Request.Context(), req, userID, idempotencyKey)
 	if err != nil {
-		if err.Error() == "seat is already held by another user" || err.Error() == "seat is already sold" {
-			h.respondError(c, http.StatusConflict, "SEAT_UNAVAILABLE", err.Error(), nil)
-			return
-		}
-		h.logger.Error("Failed to create hold", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create hold", nil)
+		respondServiceError(c, h.logger, err, "Failed to create hold")
 		return
 	}
 	
@@ -82,7 +77,7 @@ func (h *BookingHandler) CreateHold(c *gin.Context) {
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /holds/{flight_id}/{seat_no} [delete]
+// @Router /api/v1/holds/{flight_id}/{seat_no} [delete]
 func (h *BookingHandler) ReleaseHold(c *gin.Context) {
 	userID := c.GetHeader("User-ID")
 	if userID == "" {
@@ -105,8 +100,7 @@ func (h *BookingHandler) ReleaseHold(c *gin.Context) {
 	
 	err = h.bookingService.ReleaseHold(c.Request.Context(), flightID, seatNo, userID)
 	if err != nil {
-		h.logger.Error("Failed to release hold", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to release hold", nil)
+		respondServiceError(c, h.logger, err, "Failed to release hold")
 		return
 	}
 	
@@ -124,13 +118,15 @@ func (h *BookingHandler) ReleaseHold(c *gin.Context) {
 // @Param request body models.ConfirmTicketRequest true "Ticket confirmation request"
 // @Success 201 {object} models.ConfirmTicketResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 402 {object} models.ErrorResponse
 // @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /tickets/confirm [post]
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/tickets/confirm [post]
 func (h *BookingHandler) ConfirmTicket(c *gin.Context) {
 	var req models.ConfirmTicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		respondBindError(c, err, "Invalid request body")
 		return
 	}
 	
@@ -144,28 +140,214 @@ func (h *BookingHandler) ConfirmTicket(c *gin.Context) {
 	
 	response, err := h.bookingService.ConfirmTicket(c.Request.Context(), req, userID, idempotencyKey)
 	if err != nil {
-		if err.Error() == "no valid hold found to confirm" {
-			h.respondError(c, http.StatusConflict, "NO_VALID_HOLD", err.Error(), nil)
-			return
-		}
-		h.logger.Error("Failed to confirm ticket", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to confirm ticket", nil)
+		respondServiceError(c, h.logger, err, "Failed to confirm ticket")
 		return
 	}
 	
 	c.JSON(http.StatusCreated, response)
 }
 
+// ConfirmSeatlessTicket godoc
+// @Summary Buy a seatless ticket
+// @Description Sell a ticket without a seat against the cabin's overbooking limit. The seat is assigned at check-in.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Idempotency key for request deduplication"
+// @Param User-ID header string true "User ID for the ticket"
+// @Param request body models.ConfirmSeatlessTicketRequest true "Seatless ticket request"
+// @Success 201 {object} models.ConfirmTicketResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 402 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/tickets/seatless [post]
+func (h *BookingHandler) ConfirmSeatlessTicket(c *gin.Context) {
+	var req models.ConfirmSeatlessTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, "Invalid request body")
+		return
+	}
+	
+	userID := c.GetHeader("User-ID")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "MISSING_USER_ID", "User-ID header is required", nil)
+		return
+	}
+	
+	response, err := h.bookingService.ConfirmSeatlessTicket(c.Request.Context(), req, userID, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to confirm ticket")
+		return
+	}
+	
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetTicket godoc
+// @Summary Retrieve a booking
+// @Description Get a ticket by PNR with its fare breakdown
+// @Tags tickets
+// @Produce json
+// @Param User-ID header string true "User ID that owns the ticket"
+// @Param pnr_code path string true "PNR code"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/tickets/{pnr_code} [get]
+func (h *BookingHandler) GetTicket(c *gin.Context) {
+	userID := c.GetHeader("User-ID")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "MISSING_USER_ID", "User-ID header is required", nil)
+		return
+	}
+	
+	ticket, err := h.bookingService.GetBooking(c.Request.Context(), c.Param("pnr_code"), userID)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to get ticket")
+		return
+	}
+	
+	c.JSON(http.StatusOK, ticket)
+}
+
+// PurchaseAncillaries godoc
+// @Summary Buy ancillaries for a booking
+// @Description Add bags, meals, extra legroom, priority boarding or lounge access to an issued ticket before departure. They are charged as a separate payment at the ticket's exchange rate and returned as line items of the booking.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Idempotency key for request deduplication"
+// @Param User-ID header string true "User ID that owns the ticket"
+// @Param pnr_code path string true "PNR code"
+// @Param request body models.PurchaseAncillariesRequest true "Ancillaries to buy"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 402 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/tickets/{pnr_code}/ancillaries [post]
+func (h *BookingHandler) PurchaseAncillaries(c *gin.Context) {
+	var req models.PurchaseAncillariesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, "Invalid request body")
+		return
+	}
+	
+	userID := c.GetHeader("User-ID")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "MISSING_USER_ID", "User-ID header is required", nil)
+		return
+	}
+	
+	ticket, err := h.bookingService.PurchaseAncillaries(c.Request.Context(), c.Param("pnr_code"), req, userID, c.GetHeader("Idempotency-Key"))
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to purchase ancillaries")
+		return
+	}
+	
+	c.JSON(http.StatusOK, ticket)
+}
+
+// CancelTicket godoc
+// @Summary Cancel a ticket
+// @Description Cancel a ticket by PNR and return its seat to inventory
+// @Tags tickets
+// @Produce json
+// @Param User-ID header string true "User ID that owns the ticket"
+// @Param pnr_code path string true "PNR code"
+// @Success 200 {object} models.CancelTicketResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/tickets/{pnr_code}/cancel [post]
+func (h *BookingHandler) CancelTicket(c *gin.Context) {
+	userID := c.GetHeader("User-ID")
+	if userID == "" {
+		h.respondError(c, http.StatusBadRequest, "MISSING_USER_ID", "User-ID header is required", nil)
+		return
+	}
+	
+	response, err := h.bookingService.CancelTicket(c.Request.Context(), c.Param("pnr_code"), userID)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to cancel ticket")
+		return
+	}
+	
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFlightAvailability godoc
+// @Summary Get flight availability per cabin
+// @Description Get capacity and remaining seats for each cabin class of a flight
+// @Tags flights
+// @Param flight_id path int true "Flight ID"
+// @Success 200 {object} models.FlightAvailabilityResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/flights/{flight_id}/availability [get]
+func (h *BookingHandler) GetFlightAvailability(c *gin.Context) {
+	flightID, err := strconv.ParseInt(c.Param("flight_id"), 10, 64)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_FLIGHT_ID", "Invalid flight ID", nil)
+		return
+	}
+	
+	availability, err := h.bookingService.GetFlightAvailability(c.Request.Context(), flightID)
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to get flight availability")
+		return
+	}
+	
+	c.JSON(http.StatusOK, availability)
+}
+
+// GetFlightAncillaries godoc
+// @Summary List a flight's ancillaries
+// @Description List the extras sold on a flight with their price and, for limited ones, the units left
+// @Tags flights
+// @Produce json
+// @Param flight_id path int true "Flight ID"
+// @Param currency query string false "ISO 4217 currency for prices (default: base currency)"
+// @Success 200 {object} models.FlightAncillariesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/flights/{flight_id}/ancillaries [get]
+func (h *BookingHandler) GetFlightAncillaries(c *gin.Context) {
+	flightID, err := strconv.ParseInt(c.Param("flight_id"), 10, 64)
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, "INVALID_FLIGHT_ID", "Invalid flight ID", nil)
+		return
+	}
+	
+	response, err := h.bookingService.GetFlightAncillaries(c.Request.Context(), flightID, c.Query("currency"))
+	if err != nil {
+		respondServiceError(c, h.logger, err, "Failed to get flight ancillaries")
+		return
+	}
+	
+	c.JSON(http.StatusOK, response)
+}
+
 // GetFlightSeats godoc
 // @Summary Get flight seat availability
 // @Description Get the availability status of all seats for a flight
 // @Tags flights
 // @Param flight_id path int true "Flight ID"
+// @Param currency query string false "ISO 4217 currency for seat prices (default: base currency)"
 // @Success 200 {array} models.SeatAvailability
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /flights/{flight_id}/seats [get]
+// @Router /api/v1/flights/{flight_id}/seats [get]
 func (h *BookingHandler) GetFlightSeats(c *gin.Context) {
 	flightIDStr := c.Param("flight_id")
 	flightID, err := strconv.ParseInt(flightIDStr, 10, 64)
@@ -174,10 +356,9 @@ func (h *BookingHandler) GetFlightSeats(c *gin.Context) {
 		return
 	}
 	
-	seats, err := h.bookingService.GetFlightSeatAvailability(c.Request.Context(), flightID)
+	seats, err := h.bookingService.GetFlightSeatAvailability(c.Request.Context(), flightID, c.Query("currency"))
 	if err != nil {
-		h.logger.Error("Failed to get flight seats", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to get flight seats", nil)
+		respondServiceError(c, h.logger, err, "Failed to get flight seats")
 		return
 	}
 	
@@ -186,23 +367,24 @@ func (h *BookingHandler) GetFlightSeats(c *gin.Context) {
 
 // SearchFlights godoc
 // @Summary Search for flights
-// @Description Search for flights using various criteria
+// @Description Search for flights using various criteria. Each result's base_price is in minor units of the base currency (39999 for 399.99), not the decimal amount flights are created with; price and currency give the fare in the requested currency.
 // @Tags flights
 // @Param origin query string true "Origin airport code"
 // @Param destination query string true "Destination airport code"
-// @Param date query string true "Departure date (YYYY-MM-DD)"
+// @Param date query string true "Departure date (YYYY-MM-DD) in the origin airport's local time"
 // @Param fare_class query string false "Fare class"
 // @Param airline query string false "Airline code"
+// @Param currency query string false "ISO 4217 currency for prices (default: base currency)"
 // @Param page query int false "Page number (default: 1)"
 // @Param size query int false "Page size (default: 10)"
 // @Success 200 {object} models.FlightSearchResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /flights/search [get]
+// @Router /api/v1/flights/search [get]
 func (h *BookingHandler) SearchFlights(c *gin.Context) {
 	var req models.FlightSearchRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		h.respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid query parameters", err.Error())
+		respondBindError(c, err, "Invalid query parameters")
 		return
 	}
 	
@@ -219,8 +401,7 @@ func (h *BookingHandler) SearchFlights(c *gin.Context) {
 	
 	response, err := h.bookingService.SearchFlights(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to search flights", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to search flights", nil)
+		respondServiceError(c, h.logger, err, "Failed to search flights")
 		return
 	}
 	
@@ -237,42 +418,23 @@ func (h *BookingHandler) SearchFlights(c *gin.Context) {
 // @Success 201 {object} models.CreateFlightResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /flights [post]
+// @Router /api/v1/flights [post]
 func (h *BookingHandler) CreateFlight(c *gin.Context) {
 	var req models.CreateFlightRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request body", err.Error())
+		respondBindError(c, err, "Invalid request body")
 		return
 	}
 	
 	response, err := h.bookingService.CreateFlight(c.Request.Context(), req)
 	if err != nil {
-		h.logger.Error("Failed to create flight", zap.Error(err))
-		h.respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create flight", nil)
+		respondServiceError(c, h.logger, err, "Failed to create flight")
 		return
 	}
 	
 	c.JSON(http.StatusCreated, response)
 }
 
-// Health godoc
-// @Summary Health check
-// @Description Check the health of the service
-// @Tags health
-// @Success 200 {object} map[string]string
-// @Router /api/v1/health [get]
-func (h *BookingHandler) Health(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
-		"status": "healthy",
-		"time":   c.Request.Header.Get("X-Request-Time"),
-	})
-}
-
 func (h *BookingHandler) respondError(c *gin.Context, statusCode int, code, message string, details interface{}) {
-	response := models.ErrorResponse{
-		Code:    code,
-		Message: message,
-		Details: details,
-	}
-	c.JSON(statusCode, response)
+	respondError(c, statusCode, code, message, details)
 }
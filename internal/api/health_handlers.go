@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/health"
+	"airline-booking/internal/models"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+	logger  *zap.Logger
+}
+
+func NewHealthHandler(checker *health.Checker, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Report that the process is up. Checks no dependencies, so a restart is only triggered when the process itself is stuck.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Router /health/live [get]
+// @Router /api/v1/health [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, h.checker.Live())
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Check MySQL, the schema migration version, the cleanup job scheduler and Elasticsearch, reporting each one's status and latency. Returns 503 when a required dependency is down. Elasticsearch is optional: while it is down the status is "degraded" and the service keeps taking traffic.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Failure 503 {object} models.HealthResponse
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	response := h.checker.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if response.Status == models.HealthStatusUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, response)
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/gin-gonic/gin"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/openapi"
+)
+
+// serveOpenAPISpec serves the OpenAPI 3 document the requests are validated
+// against
+func serveOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec())
+}
+
+// serveSwaggerUI serves a Swagger UI page for /openapi.json
+func serveSwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(openapi.UIPage))
+}
+
+// openAPIValidationMiddleware rejects requests whose parameters or body
+// don't match the OpenAPI document with a 400 VALIDATION_FAILED, one
+// models.FieldError per problem. Routes the document doesn't describe pass
+// through untouched.
+func (r *Router) openAPIValidationMiddleware() gin.HandlerFunc {
+	doc, err := openapi.Load()
+	if err != nil {
+		panic(fmt.Sprintf("loading OpenAPI document: %v", err))
+	}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		panic(fmt.Sprintf("routing OpenAPI document: %v", err))
+	}
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		err = openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			respondOpenAPIError(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// respondOpenAPIError answers a request the OpenAPI document rejects the way
+// respondBindError answers one the handler's binding rejects
+func respondOpenAPIError(c *gin.Context, err error) {
+	const message = "Request does not match the API specification"
+
+	var details []models.FieldError
+	for _, requestErr := range requestErrors(err) {
+		var parseErr *openapi3filter.ParseError
+		if errors.As(requestErr.Err, &parseErr) && requestErr.RequestBody != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", message, parseErr.Error())
+			return
+		}
+		details = append(details, openAPIFieldErrors(requestErr)...)
+	}
+	respondError(c, http.StatusBadRequest, "VALIDATION_FAILED", message, details)
+}
+
+// requestErrors flattens the errors ValidateRequest reports with MultiError
+// set
+func requestErrors(err error) []*openapi3filter.RequestError {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		var all []*openapi3filter.RequestError
+		for _, e := range multi {
+			all = append(all, requestErrors(e)...)
+		}
+		return all
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		return []*openapi3filter.RequestError{requestErr}
+	}
+	return []*openapi3filter.RequestError{{Err: err}}
+}
+
+// openAPIFieldErrors describes a rejected parameter or body as field errors.
+// Parameters are reported by name, e.g. "User-ID", and body fields by their
+// path, e.g. "seat_config.economy_rows".
+func openAPIFieldErrors(requestErr *openapi3filter.RequestError) []models.FieldError {
+	name := ""
+	if requestErr.Parameter != nil {
+		name = requestErr.Parameter.Name
+	}
+
+	if errors.Is(requestErr.Err, openapi3filter.ErrInvalidRequired) {
+		if name == "" {
+			name = "body"
+		}
+		return []models.FieldError{{Field: name, Rule: "required", Message: "is required"}}
+	}
+
+	var schemaErrs []*openapi3.SchemaError
+	var multi openapi3.MultiError
+	var schemaErr *openapi3.SchemaError
+	switch {
+	case errors.As(requestErr.Err, &multi):
+		for _, e := range multi {
+			if errors.As(e, &schemaErr) {
+				schemaErrs = append(schemaErrs, schemaErr)
+			}
+		}
+	case errors.As(requestErr.Err, &schemaErr):
+		schemaErrs = append(schemaErrs, schemaErr)
+	}
+
+	if len(schemaErrs) == 0 {
+		return []models.FieldError{{Field: name, Rule: "schema", Message: requestErr.Error()}}
+	}
+	details := make([]models.FieldError, 0, len(schemaErrs))
+	for _, schemaErr := range schemaErrs {
+		field := jsonPointerPath(schemaErr.JSONPointer())
+		if name != "" {
+			field = name
+		}
+		details = append(details, models.FieldError{
+			Field:   field,
+			Rule:    schemaErr.SchemaField,
+			Message: schemaErr.Reason,
+		})
+	}
+	return details
+}
+
+// jsonPointerPath turns a JSON pointer into a field path like the binding
+// errors use, e.g. ["ancillaries", "0", "code"] into "ancillaries[0].code"
+func jsonPointerPath(pointer []string) string {
+	var b strings.Builder
+	for _, token := range pointer {
+		if token != "" && strings.Trim(token, "0123456789") == "" {
+			b.WriteString("[" + token + "]")
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/openapi"
+)
+
+// undocumentedRoutes are served outside the API and left out of the spec
+var undocumentedRoutes = map[string]bool{
+	"GET /metrics":      true,
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+	"POST /debug/holds": true,
+}
+
+var ginPathParam = regexp.MustCompile(`:([^/]+)`)
+
+func TestRoutesDocumentedInOpenAPISpec(t *testing.T) {
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := newTestRouter(t)
+	router.Setup()
+
+	for _, route := range router.GetEngine().Routes() {
+		if undocumentedRoutes[route.Method+" "+route.Path] {
+			continue
+		}
+		path := ginPathParam.ReplaceAllString(route.Path, "{$1}")
+		pathItem := doc.Paths.Value(path)
+		if pathItem == nil || pathItem.GetOperation(route.Method) == nil {
+			t.Errorf("%s %s is missing from the OpenAPI spec; annotate its handler and run make openapi", route.Method, path)
+		}
+	}
+}
+
+// validate serves req through the OpenAPI validation middleware
+func validate(t *testing.T, req *http.Request) (int, []models.FieldError, models.ErrorResponse) {
+	t.Helper()
+	router := newTestRouter(t)
+	engine := gin.New()
+	engine.Use(router.openAPIValidationMiddleware())
+	engine.POST("/api/v1/holds", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	engine.GET("/api/v1/flights/:flight_id/seats", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	var response models.ErrorResponse
+	var body struct {
+		Details []models.FieldError `json:"details"`
+	}
+	if w.Code == http.StatusNoContent {
+		return w.Code, nil, response
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body.String(), err)
+	}
+	if response.Code == "VALIDATION_FAILED" {
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decoding %s: %v", w.Body.String(), err)
+		}
+	}
+	return w.Code, body.Details, response
+}
+
+func TestOpenAPIValidationMiddleware(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/holds", strings.NewReader(`{"flight_id": 1, "seat_no": "12A"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-ID", "user-1")
+	if status, details, _ := validate(t, req); status != http.StatusNoContent {
+		t.Fatalf("got %d %v for a valid hold, want it accepted", status, details)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/holds", strings.NewReader(`{"flight_id": "one"}`))
+	req.Header.Set("Content-Type", "application/json")
+	status, details, response := validate(t, req)
+	if status != http.StatusBadRequest || response.Code != "VALIDATION_FAILED" {
+		t.Fatalf("got %d %s, want 400 VALIDATION_FAILED", status, response.Code)
+	}
+	rules := map[string]string{}
+	for _, detail := range details {
+		rules[detail.Field] = detail.Rule
+	}
+	want := map[string]string{
+		"User-ID":   "required",
+		"flight_id": "type",
+		"seat_no":   "required",
+	}
+	for field, rule := range want {
+		if rules[field] != rule {
+			t.Fatalf("got details %v, want %s to fail %s", rules, field, rule)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/flights/abc/seats", nil)
+	if status, details, _ := validate(t, req); status != http.StatusBadRequest || len(details) != 1 || details[0].Field != "flight_id" {
+		t.Fatalf("got %d %v for a non-numeric flight_id, want 400 on flight_id", status, details)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/holds", strings.NewReader(`{"flight_id": `))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-ID", "user-1")
+	if status, _, response := validate(t, req); status != http.StatusBadRequest || response.Code != "INVALID_REQUEST" {
+		t.Fatalf("got %d %s for a malformed body, want 400 INVALID_REQUEST", status, response.Code)
+	}
+}
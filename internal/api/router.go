@@ -1,66 +1,139 @@
 package api
 
 import (
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 
 	"airline-booking/internal/config"
+	"airline-booking/internal/metrics"
+	"airline-booking/internal/service"
 )
 
+// Handlers groups the HTTP handlers mounted by the router
+type Handlers struct {
+	Booking  *BookingHandler
+	Airports *AirportHandler
+	Admin    *AdminHandler
+	Webhooks *WebhookHandler
+	CheckIn  *CheckInHandler
+	Health   *HealthHandler
+}
+
 type Router struct {
-	engine  *gin.Engine
-	handler *BookingHandler
-	config  *config.Config
-	logger  *zap.Logger
+	engine   *gin.Engine
+	handlers Handlers
+	config   *config.Config
+	logger   *zap.Logger
 }
 
-func NewRouter(handler *BookingHandler, cfg *config.Config, logger *zap.Logger) *Router {
+func NewRouter(handlers Handlers, cfg *config.Config, logger *zap.Logger) *Router {
 	// Set gin mode based on environment
 	if cfg.App.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 	
 	engine := gin.New()
+	registerValidators()
 	
 	return &Router{
-		engine:  engine,
-		handler: handler,
-		config:  cfg,
-		logger:  logger,
+		engine:   engine,
+		handlers: handlers,
+		config:   cfg,
+		logger:   logger,
 	}
 }
 
 func (r *Router) Setup() {
-	// Global middleware
+	// Prometheus scrapes and orchestrator probes bypass logging and rate
+	// limiting
+	r.engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r.engine.GET("/health/live", r.handlers.Health.Live)
+	r.engine.GET("/health/ready", r.handlers.Health.Ready)
+	
+	// API documentation
+	r.engine.GET("/openapi.json", serveOpenAPISpec)
+	r.engine.GET("/docs", serveSwaggerUI)
+	
+	// Global middleware; the request span comes first so everything after
+	// it runs, and logs, inside the trace
+	r.engine.Use(otelgin.Middleware(r.config.Tracing.ServiceName))
+	r.engine.Use(r.requestIDMiddleware())
 	r.engine.Use(r.loggerMiddleware())
+	r.engine.Use(r.metricsMiddleware())
 	r.engine.Use(r.recoveryMiddleware())
 	r.engine.Use(r.corsMiddleware())
 	r.engine.Use(r.rateLimitMiddleware())
 	
+	// Requests are checked against the OpenAPI document after
+	// authentication, so callers without access learn nothing from it
+	validate := r.openAPIValidationMiddleware()
+	
 	// API routes
 	api := r.engine.Group("/api/v1")
+	public := api.Group("", validate)
 	{
-		// Health check endpoint
-		api.GET("/health", r.handler.Health)
+		// Kept for existing monitors; same as /health/live
+		public.GET("/health", r.handlers.Health.Live)
 		
 		// Flight search and management
-		api.GET("/flights/search", r.handler.SearchFlights)
-		api.POST("/flights", r.handler.CreateFlight)
-		api.GET("/flights/:flight_id/seats", r.handler.GetFlightSeats)
+		public.GET("/flights/search", r.handlers.Booking.SearchFlights)
+		public.POST("/flights", r.handlers.Booking.CreateFlight)
+		public.GET("/flights/:flight_id/seats", r.handlers.Booking.GetFlightSeats)
+		public.GET("/flights/:flight_id/availability", r.handlers.Booking.GetFlightAvailability)
+		public.GET("/flights/:flight_id/ancillaries", r.handlers.Booking.GetFlightAncillaries)
+
+		// Airport autocomplete
+		public.GET("/airports/suggest", r.handlers.Airports.SuggestAirports)
 		
 		// Seat holds
-		api.POST("/holds", r.handler.CreateHold)
-		api.DELETE("/holds/:flight_id/:seat_no", r.handler.ReleaseHold)
+		public.POST("/holds", r.handlers.Booking.CreateHold)
+		public.DELETE("/holds/:flight_id/:seat_no", r.handlers.Booking.ReleaseHold)
 		
 		// Ticket confirmation
-		api.POST("/tickets/confirm", r.handler.ConfirmTicket)
+		public.POST("/tickets/confirm", r.handlers.Booking.ConfirmTicket)
+		public.POST("/tickets/seatless", r.handlers.Booking.ConfirmSeatlessTicket)
+		public.GET("/tickets/:pnr_code", r.handlers.Booking.GetTicket)
+		public.POST("/tickets/:pnr_code/cancel", r.handlers.Booking.CancelTicket)
+		public.POST("/tickets/:pnr_code/ancillaries", r.handlers.Booking.PurchaseAncillaries)
+		
+		// Online check-in
+		public.POST("/tickets/:pnr_code/check-in", r.handlers.CheckIn.CheckIn)
+		public.GET("/tickets/:pnr_code/boarding-pass", r.handlers.CheckIn.GetBoardingPass)
+		
+		// Provider callbacks
+		public.POST("/webhooks/payments", r.handlers.Webhooks.PaymentWebhook)
+	}
+	
+	// Gate operations, for gate staff and admins
+	gate := api.Group("/flights/:flight_id", r.gateAuthMiddleware(), validate)
+	{
+		gate.POST("/boarding/scan", r.handlers.CheckIn.ScanBoardingPass)
+		gate.GET("/manifest", r.handlers.CheckIn.GetFlightManifest)
+	}
+	
+	// Operations, for holders of the admin token
+	admin := api.Group("/admin", r.adminAuthMiddleware(), validate)
+	{
+		admin.PUT("/flights/:flight_id/cabins/:cabin_class/overbooking", r.handlers.Admin.SetOverbookingLimit)
+		admin.GET("/flights/:flight_id/denied-boarding", r.handlers.Admin.DeniedBoardingList)
+		admin.GET("/flights/:flight_id/passenger-manifest", r.handlers.Admin.ExportPassengerManifest)
+		admin.GET("/overbooking/at-risk", r.handlers.Admin.FlightsAtRisk)
+		admin.GET("/exchange-rates", r.handlers.Admin.ListExchangeRates)
+		admin.PUT("/exchange-rates", r.handlers.Admin.SetExchangeRates)
+		admin.GET("/promotions", r.handlers.Admin.ListPromotions)
+		admin.POST("/promotions", r.handlers.Admin.CreatePromotion)
+		admin.GET("/audit", r.handlers.Admin.ListAuditEvents)
 	}
 	
 	// Debug route without middleware
-	r.engine.POST("/debug/holds", r.handler.CreateHold)
+	r.engine.POST("/debug/holds", r.handlers.Booking.CreateHold)
 }
 
 func (r *Router) GetEngine() *gin.Engine {
@@ -69,31 +142,74 @@ func (r *Router) GetEngine() *gin.Engine {
 
 // Middleware functions
 
+// loggerMiddleware logs every request with the trace it was served in
 func (r *Router) loggerMiddleware() gin.HandlerFunc {
-	return gin.LoggerWithConfig(gin.LoggerConfig{
-		Formatter: func(param gin.LogFormatterParams) string {
-			r.logger.Info("HTTP Request",
-				zap.String("method", param.Method),
-				zap.String("path", param.Path),
-				zap.Int("status", param.StatusCode),
-				zap.Duration("latency", param.Latency),
-				zap.String("ip", param.ClientIP),
-			)
-			return ""
-		},
-	})
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			path += "?" + raw
+		}
+		
+		c.Next()
+		
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("ip", c.ClientIP()),
+		}
+		r.logger.Info("HTTP Request", append(fields, requestLogFields(c.Request.Context())...)...)
+	}
+}
+
+// requestIDMiddleware gives every request an ID: the caller's X-Request-ID
+// when it is usable, otherwise a new one. It is echoed in the response
+// header and carried in the request context, with the caller's IP, for error
+// bodies, log lines and the audit log.
+func (r *Router) requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(requestIDHeader, requestID)
+		
+		ctx := service.WithRequestInfo(c.Request.Context(), service.RequestInfo{
+			ID: requestID,
+			IP: c.ClientIP(),
+		})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// metricsMiddleware records request counts and latencies by route template,
+// so /tickets/ABC123 and /tickets/XYZ789 share a series
+func (r *Router) metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		metrics.HTTPRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(metrics.Since(start))
+	}
 }
 
 func (r *Router) recoveryMiddleware() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		r.logger.Error("Panic recovered",
+		fields := []zap.Field{
 			zap.Any("error", recovered),
 			zap.String("path", c.Request.URL.Path),
-		)
-		c.JSON(500, gin.H{
-			"code":    "INTERNAL_ERROR",
-			"message": "Internal server error",
-		})
+		}
+		r.logger.Error("Panic recovered", append(fields, requestLogFields(c.Request.Context())...)...)
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Internal server error", nil)
 	})
 }
 
@@ -101,7 +217,8 @@ func (r *Router) corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, User-ID, Idempotency-Key")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, User-ID, Idempotency-Key, X-Request-ID, traceparent, tracestate")
+		c.Header("Access-Control-Expose-Headers", requestIDHeader)
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -114,14 +231,19 @@ func (r *Router) corsMiddleware() gin.HandlerFunc {
 
 func (r *Router) rateLimitMiddleware() gin.HandlerFunc {
 	// Create a rate limiter: allow N requests per minute per IP
-	limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(r.config.RateLimit.PerMinute)), r.config.RateLimit.PerMinute)
+	perMinute := r.config.Live().RateLimit.PerMinute
+	limiter := rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+	
+	// Config reloads retune it in place
+	r.config.OnReload(func(live config.Live) {
+		perMinute := live.RateLimit.PerMinute
+		limiter.SetLimit(rate.Every(time.Minute / time.Duration(perMinute)))
+		limiter.SetBurst(perMinute)
+	})
 	
 	return func(c *gin.Context) {
 		if !limiter.Allow() {
-			c.JSON(429, gin.H{
-				"code":    "RATE_LIMIT_EXCEEDED",
-				"message": "Too many requests",
-			})
+			respondError(c, http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", "Too many requests", nil)
 			c.Abort()
 			return
 		}
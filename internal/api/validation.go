@@ -0,0 +1,156 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"airline-booking/internal/models"
+)
+
+var (
+	iataAirportPattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+	// Airline designators are two letters or digits, at least one a letter
+	iataAirlinePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]|[0-9][A-Za-z])$`)
+	// Seats are a row from 1 to 999 and a seat letter, e.g. 12A
+	seatNoPattern = regexp.MustCompile(`^[1-9][0-9]{0,2}[A-Z]$`)
+)
+
+// fareClasses are the cabins a flight can be sold in
+var fareClasses = []string{"economy", "business", "first"}
+
+var registerValidatorsOnce sync.Once
+
+// registerValidators adds the iata_airport, iata_airline, fare_class and
+// seat_no binding tags to gin's validator, and reports fields by their JSON
+// or form name
+func registerValidators() {
+	registerValidatorsOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			panic("gin binding validator is not go-playground/validator")
+		}
+
+		v.RegisterTagNameFunc(fieldName)
+		rules := map[string]validator.Func{
+			"iata_airport": matches(iataAirportPattern),
+			"iata_airline": matches(iataAirlinePattern),
+			"seat_no":      matches(seatNoPattern),
+			"fare_class": func(fl validator.FieldLevel) bool {
+				for _, fareClass := range fareClasses {
+					if fl.Field().String() == fareClass {
+						return true
+					}
+				}
+				return false
+			},
+		}
+		for tag, rule := range rules {
+			if err := v.RegisterValidation(tag, rule); err != nil {
+				panic(fmt.Sprintf("registering %s validator: %v", tag, err))
+			}
+		}
+	})
+}
+
+func matches(pattern *regexp.Regexp) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return pattern.MatchString(fl.Field().String())
+	}
+}
+
+// fieldName is the name a client sends the field as
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// respondBindError answers a request that could not be bound: a 400
+// VALIDATION_FAILED with one models.FieldError per invalid field, or
+// INVALID_REQUEST when the body or query could not be decoded at all
+func respondBindError(c *gin.Context, err error, message string) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", message, err.Error())
+		return
+	}
+
+	details := make([]models.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		details = append(details, models.FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	respondError(c, http.StatusBadRequest, "VALIDATION_FAILED", message, details)
+}
+
+// fieldPath is the field's path from the top of the request, e.g.
+// "seat_config.economy_rows" or "ancillaries[0].code"
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "iata_airport":
+		return "must be a 3-letter IATA airport code"
+	case "iata_airline":
+		return "must be a 2-character IATA airline code"
+	case "fare_class":
+		return "must be one of " + strings.Join(fareClasses, ", ")
+	case "seat_no":
+		return "must be a row number followed by a seat letter, e.g. 12A"
+	case "nefield":
+		return "must differ from " + toSnakeCase(fe.Param())
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "len":
+		return "must have length " + fe.Param()
+	case "min":
+		return "must be at least " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	}
+	return "failed the " + fe.Tag() + " rule"
+}
+
+// toSnakeCase turns the Go field name nefield refers to into its JSON name
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
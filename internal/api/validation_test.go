@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"airline-booking/internal/models"
+)
+
+// bindJSON binds body into req the way the handlers do
+func bindJSON(t *testing.T, body string, req interface{}) (int, map[string]string, models.ErrorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	registerValidators()
+	engine := gin.New()
+	engine.POST("/", func(c *gin.Context) {
+		if err := c.ShouldBindJSON(req); err != nil {
+			respondBindError(c, err, "Invalid request body")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	var response models.ErrorResponse
+	rules := map[string]string{}
+	if w.Code == http.StatusNoContent {
+		return w.Code, rules, response
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decoding %s: %v", w.Body.String(), err)
+	}
+	if response.Code == "VALIDATION_FAILED" {
+		var body struct {
+			Details []models.FieldError `json:"details"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decoding %s: %v", w.Body.String(), err)
+		}
+		for _, detail := range body.Details {
+			rules[detail.Field] = detail.Rule
+		}
+	}
+	return w.Code, rules, response
+}
+
+func TestCreateFlightRequestValidation(t *testing.T) {
+	valid := `{"origin": "GRU", "destination": "gig", "departure_time": "2026-03-01T08:00:00Z",
+		"arrival_time": "2026-03-01T09:00:00Z", "airline": "G3", "aircraft": "Boeing 737",
+		"fare_class": "economy", "base_price": 399.99}`
+	if status, rules, _ := bindJSON(t, valid, &models.CreateFlightRequest{}); status != http.StatusNoContent {
+		t.Fatalf("got %d %v for a valid flight, want it accepted", status, rules)
+	}
+
+	invalid := `{"origin": "GRU", "destination": "GRU", "departure_time": "2026-03-01T08:00:00Z",
+		"arrival_time": "2026-03-01T09:00:00Z", "airline": "GOL", "aircraft": "Boeing 737",
+		"fare_class": "premium", "base_price": -5, "seat_config": {"economy_rows": 0, "seats_per_row": 6}}`
+	status, rules, response := bindJSON(t, invalid, &models.CreateFlightRequest{})
+	if status != http.StatusBadRequest || response.Code != "VALIDATION_FAILED" {
+		t.Fatalf("got %d %s, want 400 VALIDATION_FAILED", status, response.Code)
+	}
+	want := map[string]string{
+		"destination":              "nefield",
+		"airline":                  "iata_airline",
+		"fare_class":               "fare_class",
+		"base_price":               "gt",
+		"seat_config.economy_rows": "min",
+	}
+	if len(rules) != len(want) {
+		t.Fatalf("got details %v, want %v", rules, want)
+	}
+	for field, rule := range want {
+		if rules[field] != rule {
+			t.Fatalf("got details %v, want %s to fail %s", rules, field, rule)
+		}
+	}
+}
+
+func TestSeatNoValidation(t *testing.T) {
+	for _, seatNo := range []string{"12A", "1F", "101K"} {
+		body := `{"flight_id": 1, "seat_no": "` + seatNo + `"}`
+		if status, rules, _ := bindJSON(t, body, &models.CreateHoldRequest{}); status != http.StatusNoContent {
+			t.Fatalf("got %d %v for seat %s, want it accepted", status, rules, seatNo)
+		}
+	}
+	for _, seatNo := range []string{"ZZZ99", "0A", "12a", "1234A", "12"} {
+		body := `{"flight_id": 1, "seat_no": "` + seatNo + `"}`
+		if _, rules, _ := bindJSON(t, body, &models.CreateHoldRequest{}); rules["seat_no"] != "seat_no" {
+			t.Fatalf("got details %v for seat %s, want seat_no to fail seat_no", rules, seatNo)
+		}
+	}
+}
+
+func TestRespondBindErrorMalformedBody(t *testing.T) {
+	status, _, response := bindJSON(t, `{"flight_id": `, &models.CreateHoldRequest{})
+	if status != http.StatusBadRequest || response.Code != "INVALID_REQUEST" {
+		t.Fatalf("got %d %s, want 400 INVALID_REQUEST", status, response.Code)
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/payment"
+	"airline-booking/internal/service"
+)
+
+// maxWebhookBodyBytes bounds the payload read before the signature is checked
+const maxWebhookBodyBytes = 1 << 20
+
+// WebhookHandler receives notifications from external providers
+type WebhookHandler struct {
+	paymentWebhookService *service.PaymentWebhookService
+	logger                *zap.Logger
+}
+
+func NewWebhookHandler(paymentWebhookService *service.PaymentWebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		paymentWebhookService: paymentWebhookService,
+		logger:                logger,
+	}
+}
+
+// PaymentWebhook godoc
+// @Summary Receive a payment provider event
+// @Description Verify the HMAC signature of an asynchronous payment result (capture, refund, chargeback), store it once per event ID and apply it to the ticket paid with its authorization
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "t=<unix seconds>,v1=<hex HMAC-SHA256 of t.body>"
+// @Success 200 {object} models.PaymentWebhookResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/webhooks/payments [post]
+func (h *WebhookHandler) PaymentWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodyBytes))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body", nil)
+		return
+	}
+
+	response, err := h.paymentWebhookService.HandleWebhook(c.Request.Context(), body, c.GetHeader(payment.SignatureHeader))
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			h.logger.Warn("Rejected payment webhook", append(requestLogFields(c.Request.Context()), zap.Error(err), zap.String("ip", c.ClientIP()))...)
+		}
+		// Providers retry on 5xx, so the event is not lost
+		respondServiceError(c, h.logger, err, "Failed to process webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
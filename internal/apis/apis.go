@@ -0,0 +1,156 @@
+// Package apis exports a flight's advance passenger information (APIS):
+// the names, travel documents, seats and PNRs of its checked-in passengers,
+// as CSV, JSON or a UN/EDIFACT PAXLST message for border authorities.
+package apis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownFormat is returned for an export format other than CSV, JSON or
+// PAXLST
+var ErrUnknownFormat = errors.New("unknown manifest format")
+
+// Format is a manifest export format
+type Format string
+
+const (
+	CSV    Format = "csv"
+	JSON   Format = "json"
+	PAXLST Format = "paxlst"
+)
+
+// Formats lists every export format
+var Formats = []Format{CSV, JSON, PAXLST}
+
+// ParseFormat returns the format named s
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, JSON, PAXLST:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv"
+	case JSON:
+		return "application/json"
+	}
+	return "application/edifact"
+}
+
+// Extension returns the file extension for the format, without the dot
+func (f Format) Extension() string {
+	if f == PAXLST {
+		return "edi"
+	}
+	return string(f)
+}
+
+// Manifest is the advance passenger information of one flight leg
+type Manifest struct {
+	FlightID      int64       `json:"flight_id"`
+	Airline       string      `json:"airline"`
+	FlightNumber  string      `json:"flight_number"`
+	Origin        string      `json:"origin"`
+	Destination   string      `json:"destination"`
+	DepartureTime time.Time   `json:"departure_time"` // wall clock at the origin
+	ArrivalTime   time.Time   `json:"arrival_time"`   // wall clock at the destination
+	Passengers    []Passenger `json:"passengers"`
+}
+
+// Passenger is a checked-in passenger with the travel document presented at
+// check-in
+type Passenger struct {
+	TicketID         int64     `json:"ticket_id"`
+	PNRCode          string    `json:"pnr_code"`
+	FirstName        string    `json:"first_name"`
+	LastName         string    `json:"last_name"`
+	DateOfBirth      time.Time `json:"date_of_birth"`
+	Nationality      string    `json:"nationality"`   // ISO 3166-1 alpha-2
+	DocumentType     string    `json:"document_type"` // passport or national_id
+	DocumentNumber   string    `json:"document_number"`
+	DocumentCountry  string    `json:"document_country"` // ISO 3166-1 alpha-2
+	DocumentExpiry   time.Time `json:"document_expiry"`
+	CabinClass       string    `json:"cabin_class"`
+	SeatNo           string    `json:"seat_no"`
+	BoardingSequence int       `json:"boarding_sequence"`
+	Boarded          bool      `json:"boarded"`
+}
+
+// Write encodes the manifest in the given format. PAXLST messages are
+// addressed with the given interchange header.
+func (m *Manifest) Write(w io.Writer, format Format, header Interchange) error {
+	switch format {
+	case CSV:
+		return m.WriteCSV(w)
+	case JSON:
+		return m.WriteJSON(w)
+	case PAXLST:
+		_, err := io.WriteString(w, m.PAXLST(header))
+		return err
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// csvHeader names the columns written by WriteCSV
+var csvHeader = []string{
+	"pnr_code", "ticket_id", "last_name", "first_name", "date_of_birth", "nationality",
+	"document_type", "document_number", "document_country", "document_expiry",
+	"cabin_class", "seat_no", "boarding_sequence", "boarded",
+}
+
+// WriteCSV writes one row per passenger under a header row
+func (m *Manifest) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, p := range m.Passengers {
+		row := []string{
+			p.PNRCode,
+			strconv.FormatInt(p.TicketID, 10),
+			p.LastName,
+			p.FirstName,
+			p.DateOfBirth.Format(time.DateOnly),
+			p.Nationality,
+			p.DocumentType,
+			p.DocumentNumber,
+			p.DocumentCountry,
+			p.DocumentExpiry.Format(time.DateOnly),
+			p.CabinClass,
+			p.SeatNo,
+			strconv.Itoa(p.BoardingSequence),
+			strconv.FormatBool(p.Boarded),
+		}
+		if err := cw.Write(row); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// WriteJSON writes the manifest as an indented JSON document
+func (m *Manifest) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("failed to write json: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package apis
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testManifest() *Manifest {
+	return &Manifest{
+		FlightID:      42,
+		Airline:       "XX",
+		FlightNumber:  "42",
+		Origin:        "GRU",
+		Destination:   "LIS",
+		DepartureTime: time.Date(2025, 9, 1, 22, 30, 0, 0, time.UTC),
+		ArrivalTime:   time.Date(2025, 9, 2, 11, 15, 0, 0, time.UTC),
+		Passengers: []Passenger{
+			{
+				TicketID:         7,
+				PNRCode:          "ABC123",
+				FirstName:        "José",
+				LastName:         "D'Ávila",
+				DateOfBirth:      time.Date(1990, 4, 12, 0, 0, 0, 0, time.UTC),
+				Nationality:      "BR",
+				DocumentType:     "passport",
+				DocumentNumber:   "FX123456",
+				DocumentCountry:  "BR",
+				DocumentExpiry:   time.Date(2030, 1, 31, 0, 0, 0, 0, time.UTC),
+				CabinClass:       "economy",
+				SeatNo:           "14C",
+				BoardingSequence: 3,
+				Boarded:          true,
+			},
+			{
+				TicketID:         8,
+				PNRCode:          "DEF456",
+				FirstName:        "Ana",
+				LastName:         "Costa",
+				DateOfBirth:      time.Date(1985, 12, 1, 0, 0, 0, 0, time.UTC),
+				Nationality:      "PT",
+				DocumentType:     "national_id",
+				DocumentNumber:   "12345678",
+				DocumentCountry:  "PT",
+				DocumentExpiry:   time.Date(2028, 6, 30, 0, 0, 0, 0, time.UTC),
+				CabinClass:       "business",
+				SeatNo:           "2A",
+				BoardingSequence: 1,
+			},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"csv", "JSON", "paxlst"} {
+		format, err := ParseFormat(name)
+		require.NoError(t, err)
+		assert.Equal(t, strings.ToLower(name), string(format))
+	}
+
+	_, err := ParseFormat("xml")
+	assert.True(t, errors.Is(err, ErrUnknownFormat))
+	assert.Equal(t, "edi", PAXLST.Extension())
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testManifest().WriteCSV(&buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{
+		"ABC123", "7", "D'Ávila", "José", "1990-04-12", "BR",
+		"passport", "FX123456", "BR", "2030-01-31",
+		"economy", "14C", "3", "true",
+	}, rows[1])
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testManifest().WriteJSON(&buf))
+
+	var decoded Manifest
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, *testManifest(), decoded)
+}
+
+func TestPAXLST(t *testing.T) {
+	message := testManifest().PAXLST(Interchange{
+		Sender:    "XXAPIS",
+		Receiver:  "SEF",
+		Reference: "000000000001",
+		Prepared:  time.Date(2025, 9, 1, 20, 0, 0, 0, time.UTC),
+	})
+	segments := strings.Split(strings.TrimSuffix(message, "'\n"), "'\n")
+
+	assert.Equal(t, "UNA:+.? ", segments[0])
+	assert.Equal(t, "UNB+UNOA:4+XXAPIS+SEF+250901:2000+000000000001", segments[1])
+	assert.Equal(t, "UNH+1+PAXLST:D:05B:UN:IATA+XX42/250901/2230+01:F", segments[2])
+	assert.Contains(t, segments, "TDT+20+XX42")
+	assert.Contains(t, segments, "DTM+189:2509012230:201")
+	assert.Contains(t, segments, "DTM+232:2509021115:201")
+
+	// names are transliterated and service characters escaped
+	assert.Contains(t, segments, "NAD+FL+++D?'AVILA:JOSE")
+	assert.Contains(t, segments, "NAT+2+BRA")
+	assert.Contains(t, segments, "DOC+P+FX123456")
+	assert.Contains(t, segments, "DOC+I+12345678")
+	assert.Contains(t, segments, "LOC+91+PRT")
+	assert.Contains(t, segments, "RFF+AVF:ABC123")
+	assert.Contains(t, segments, "RFF+SEA:14C")
+	assert.Contains(t, segments, "CNT+42:2")
+
+	// UNT counts UNH through UNT
+	unh, unt := -1, -1
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, "UNH+"):
+			unh = i
+		case strings.HasPrefix(segment, "UNT+"):
+			unt = i
+		}
+	}
+	require.True(t, unh > 0 && unt > unh)
+	assert.Equal(t, "UNT+"+strconv.Itoa(unt-unh+1)+"+1", segments[unt])
+	assert.Equal(t, "UNZ+1+000000000001", segments[len(segments)-1])
+}
@@ -0,0 +1,110 @@
+package apis
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Interchange addresses a PAXLST message
+type Interchange struct {
+	Sender    string // interchange sender ID, e.g. the carrier's APIS ID
+	Receiver  string // interchange recipient ID, e.g. the border authority
+	Reference string // interchange control reference, unique per transmission
+	Prepared  time.Time
+}
+
+// PAXLST returns the manifest as a UN/EDIFACT PAXLST D.05B passenger list
+// (IATA implementation) for the flight leg, one segment per line. Country
+// codes are converted to ISO 3166-1 alpha-3.
+func (m *Manifest) PAXLST(header Interchange) string {
+	var segments []string
+	add := func(elements ...string) {
+		segments = append(segments, strings.Join(elements, "+"))
+	}
+
+	flight := edifactText(m.Airline + m.FlightNumber)
+	add("UNH", "1", "PAXLST:D:05B:UN:IATA", flight+"/"+m.DepartureTime.Format("060102/1504"), "01:F")
+	add("BGM", "745")
+	add("NAD", "MS", "", "", edifactText(header.Sender))
+	add("TDT", "20", flight)
+	add("LOC", "125", edifactText(m.Origin))
+	add("DTM", "189:"+m.DepartureTime.Format("0601021504")+":201")
+	add("LOC", "87", edifactText(m.Destination))
+	add("DTM", "232:"+m.ArrivalTime.Format("0601021504")+":201")
+
+	for _, p := range m.Passengers {
+		add("NAD", "FL", "", "", edifactText(p.LastName)+":"+edifactText(p.FirstName))
+		add("DTM", "329:"+p.DateOfBirth.Format("060102"))
+		add("LOC", "178", edifactText(m.Origin))
+		add("LOC", "179", edifactText(m.Destination))
+		add("NAT", "2", alpha3(p.Nationality))
+		add("RFF", "AVF:"+edifactText(p.PNRCode))
+		if p.SeatNo != "" {
+			add("RFF", "SEA:"+edifactText(p.SeatNo))
+		}
+		add("DOC", documentCode(p.DocumentType), edifactText(p.DocumentNumber))
+		add("DTM", "36:"+p.DocumentExpiry.Format("060102"))
+		add("LOC", "91", alpha3(p.DocumentCountry))
+	}
+
+	add("CNT", fmt.Sprintf("42:%d", len(m.Passengers)))
+	// UNT counts the segments from UNH to UNT inclusive
+	add("UNT", fmt.Sprintf("%d", len(segments)+1), "1")
+
+	var b strings.Builder
+	b.WriteString("UNA:+.? '\n")
+	fmt.Fprintf(&b, "UNB+UNOA:4+%s+%s+%s+%s'\n",
+		edifactText(header.Sender), edifactText(header.Receiver),
+		header.Prepared.UTC().Format("060102:1504"), edifactText(header.Reference))
+	for _, segment := range segments {
+		b.WriteString(segment)
+		b.WriteString("'\n")
+	}
+	fmt.Fprintf(&b, "UNZ+1+%s'\n", edifactText(header.Reference))
+	return b.String()
+}
+
+// documentCode returns the PAXLST document name code: P for passports and I
+// for identity cards
+func documentCode(documentType string) string {
+	if documentType == "national_id" {
+		return "I"
+	}
+	return "P"
+}
+
+// alpha3 converts an ISO 3166-1 alpha-2 country code to alpha-3, returning
+// codes it does not know unchanged
+func alpha3(country string) string {
+	region, err := language.ParseRegion(country)
+	if err != nil || !region.IsCountry() {
+		return edifactText(country)
+	}
+	return region.ISO3()
+}
+
+// edifactText upper-cases s, drops accents and characters outside the UNOA
+// character set and escapes the service characters with the release
+// character
+func edifactText(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.TrimSpace(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(unicode.ToUpper(r))
+		case r == '\'' || r == '+' || r == ':' || r == '?':
+			b.WriteByte('?')
+			b.WriteRune(r)
+		case r >= ' ' && r <= 'Z':
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,212 @@
+// Package boardingpass encodes boarding passes as IATA Resolution 792 bar
+// coded boarding pass (BCBP) strings, renders them, with a PDF417 or QR
+// barcode, as PNG or PDF, and decodes the strings read at the gate.
+package boardingpass
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	// ErrUnknownSymbology is returned for a barcode type other than PDF417
+	// or QR
+	ErrUnknownSymbology = errors.New("unknown barcode symbology")
+	// ErrInvalidBCBP is returned when scanned data is not a BCBP string
+	ErrInvalidBCBP = errors.New("invalid BCBP data")
+)
+
+// bcbpLength is the length of the mandatory items of a one-leg BCBP string
+const bcbpLength = 60
+
+// Symbology is the barcode type printed on a boarding pass
+type Symbology string
+
+const (
+	PDF417 Symbology = "pdf417"
+	QR     Symbology = "qr"
+)
+
+// ParseSymbology returns the symbology named s; empty means PDF417, the
+// symbology Resolution 792 requires on paper passes
+func ParseSymbology(s string) (Symbology, error) {
+	switch Symbology(strings.ToLower(s)) {
+	case "", PDF417:
+		return PDF417, nil
+	case QR:
+		return QR, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownSymbology, s)
+}
+
+// Pass holds what is printed on a single-leg boarding pass
+type Pass struct {
+	FirstName     string
+	LastName      string
+	PNRCode       string
+	Origin        string // IATA airport code
+	Destination   string
+	Airline       string    // IATA carrier designator
+	FlightNumber  string    // up to four digits, optionally followed by a letter
+	DepartureTime time.Time // wall clock at the origin
+	BoardingTime  time.Time // wall clock at the origin
+	CabinClass    string
+	SeatNo        string
+	Sequence      int // check-in sequence number
+	BoardingGroup string
+}
+
+// BCBP returns the pass's mandatory Resolution 792 items in format M with
+// one leg and no conditional items. The result is always bcbpLength
+// characters.
+func (p Pass) BCBP() string {
+	var b strings.Builder
+	b.WriteString("M1")
+	b.WriteString(field(bcbpName(p.LastName, p.FirstName), 20))
+	b.WriteString("E")
+	b.WriteString(field(p.PNRCode, 7))
+	b.WriteString(field(p.Origin, 3))
+	b.WriteString(field(p.Destination, 3))
+	b.WriteString(field(p.Airline, 3))
+	b.WriteString(bcbpFlightNumber(p.FlightNumber))
+	fmt.Fprintf(&b, "%03d", p.DepartureTime.YearDay())
+	b.WriteByte(CompartmentCode(p.CabinClass))
+	b.WriteString(bcbpSeat(p.SeatNo))
+	b.WriteString(field(fmt.Sprintf("%04d", p.Sequence%100000), 5))
+	b.WriteString("1")  // passenger checked in
+	b.WriteString("00") // no conditional items
+	return b.String()
+}
+
+// CompartmentCode returns the IATA booking class letter printed for a cabin
+func CompartmentCode(cabinClass string) byte {
+	switch strings.ToLower(cabinClass) {
+	case "first":
+		return 'F'
+	case "business":
+		return 'J'
+	case "premium_economy":
+		return 'W'
+	}
+	return 'Y'
+}
+
+// field upper-cases s, keeps only characters allowed in BCBP and pads or
+// truncates it to n characters
+func field(s string, n int) string {
+	s = asciiUpper(s)
+	if len(s) > n {
+		return s[:n]
+	}
+	return s + strings.Repeat(" ", n-len(s))
+}
+
+func bcbpName(last, first string) string {
+	name := asciiUpper(last)
+	if first != "" {
+		name += "/" + asciiUpper(first)
+	}
+	return name
+}
+
+// bcbpFlightNumber formats a flight number as four digits and an optional
+// operational suffix, e.g. "0123 "
+func bcbpFlightNumber(number string) string {
+	number = asciiUpper(number)
+	digits := strings.TrimRightFunc(number, func(r rune) bool { return r < '0' || r > '9' })
+	suffix := strings.TrimPrefix(number, digits)
+	if len(digits) > 4 {
+		digits = digits[len(digits)-4:]
+	}
+	return strings.Repeat("0", 4-len(digits)) + digits + field(suffix, 1)
+}
+
+// bcbpSeat formats a seat as a three digit row and a letter, e.g. "012A"
+func bcbpSeat(seatNo string) string {
+	seatNo = asciiUpper(seatNo)
+	row := strings.TrimRightFunc(seatNo, func(r rune) bool { return r < '0' || r > '9' })
+	letter := strings.TrimPrefix(seatNo, row)
+	if len(row) > 3 {
+		row = row[len(row)-3:]
+	}
+	return strings.Repeat("0", 3-len(row)) + row + field(letter, 1)
+}
+
+// asciiUpper transliterates s to upper-case ASCII letters, digits, spaces
+// and slashes, dropping accents ("José" becomes "JOSE")
+func asciiUpper(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.TrimSpace(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(unicode.ToUpper(r))
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == ' ', r == '/':
+			b.WriteRune(r)
+		case r == '-' || r == '\'':
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+// Decoded holds the mandatory items of the first leg of a scanned BCBP
+// barcode, with padding removed
+type Decoded struct {
+	PassengerName string // LAST/FIRST
+	PNRCode       string
+	Origin        string
+	Destination   string
+	Airline       string
+	FlightNumber  string // without leading zeros
+	JulianDate    int    // day of the year of the flight
+	Compartment   byte
+	SeatNo        string // without leading zeros, e.g. "12A"
+	Sequence      int
+	Status        byte
+}
+
+// Decode parses the mandatory items of a format M BCBP string. Conditional
+// items and further legs are ignored.
+func Decode(data string) (*Decoded, error) {
+	if len(data) < bcbpLength {
+		return nil, fmt.Errorf("%w: %d characters, want at least %d", ErrInvalidBCBP, len(data), bcbpLength)
+	}
+	if data[0] != 'M' || data[1] < '1' || data[1] > '4' {
+		return nil, fmt.Errorf("%w: not a format M boarding pass", ErrInvalidBCBP)
+	}
+
+	julianDate, err := strconv.Atoi(data[44:47])
+	if err != nil || julianDate < 1 || julianDate > 366 {
+		return nil, fmt.Errorf("%w: bad flight date %q", ErrInvalidBCBP, data[44:47])
+	}
+	sequence, err := strconv.Atoi(strings.TrimSpace(data[52:57]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad check-in sequence %q", ErrInvalidBCBP, data[52:57])
+	}
+
+	decoded := &Decoded{
+		PassengerName: strings.TrimSpace(data[2:22]),
+		PNRCode:       strings.TrimSpace(data[23:30]),
+		Origin:        strings.TrimSpace(data[30:33]),
+		Destination:   strings.TrimSpace(data[33:36]),
+		Airline:       strings.TrimSpace(data[36:39]),
+		FlightNumber:  strings.TrimLeft(strings.TrimSpace(data[39:44]), "0"),
+		JulianDate:    julianDate,
+		Compartment:   data[47],
+		SeatNo:        strings.TrimLeft(strings.TrimSpace(data[48:52]), "0"),
+		Sequence:      sequence,
+		Status:        data[57],
+	}
+	if decoded.PNRCode == "" || decoded.Origin == "" || decoded.Destination == "" {
+		return nil, fmt.Errorf("%w: missing PNR or route", ErrInvalidBCBP)
+	}
+	return decoded, nil
+}
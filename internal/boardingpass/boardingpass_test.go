@@ -0,0 +1,141 @@
+package boardingpass
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func testPass() Pass {
+	departure := time.Date(2025, time.March, 14, 9, 30, 0, 0, time.UTC)
+	return Pass{
+		FirstName:     "José María",
+		LastName:      "García-López",
+		PNRCode:       "ABC123",
+		Origin:        "gru",
+		Destination:   "JFK",
+		Airline:       "AA",
+		FlightNumber:  "123",
+		DepartureTime: departure,
+		BoardingTime:  departure.Add(-40 * time.Minute),
+		CabinClass:    "economy",
+		SeatNo:        "12A",
+		Sequence:      7,
+		BoardingGroup: "3",
+	}
+}
+
+func TestBCBP(t *testing.T) {
+	got := testPass().BCBP()
+	want := "M1GARCIA LOPEZ/JOSE MAEABC123 GRUJFKAA 0123 073Y012A0007 100"
+	if got != want {
+		t.Errorf("BCBP mismatch\n got %q\nwant %q", got, want)
+	}
+	if len(got) != 60 {
+		t.Errorf("expected 60 characters, got %d", len(got))
+	}
+}
+
+func TestBCBPFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*Pass)
+		from   int
+		want   string
+	}{
+		{"short name is padded", func(p *Pass) { p.FirstName, p.LastName = "Ana", "Li" }, 2, "LI/ANA              "},
+		{"flight suffix", func(p *Pass) { p.FlightNumber = "45A" }, 39, "0045A"},
+		{"long flight number keeps last digits", func(p *Pass) { p.FlightNumber = "12345" }, 39, "2345 "},
+		{"business compartment", func(p *Pass) { p.CabinClass = "business" }, 47, "J"},
+		{"three digit row", func(p *Pass) { p.SeatNo = "101c" }, 48, "101C"},
+	}
+
+	for _, tt := range tests {
+		pass := testPass()
+		tt.mutate(&pass)
+		got := pass.BCBP()
+		if len(got) != 60 {
+			t.Errorf("%s: expected 60 characters, got %d", tt.name, len(got))
+			continue
+		}
+		if field := got[tt.from : tt.from+len(tt.want)]; field != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, field, tt.want)
+		}
+	}
+}
+
+func TestParseSymbology(t *testing.T) {
+	for input, want := range map[string]Symbology{"": PDF417, "PDF417": PDF417, "qr": QR} {
+		got, err := ParseSymbology(input)
+		if err != nil || got != want {
+			t.Errorf("ParseSymbology(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseSymbology("aztec"); !errors.Is(err, ErrUnknownSymbology) {
+		t.Errorf("expected ErrUnknownSymbology, got %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	pass := testPass()
+	for _, symbology := range []Symbology{PDF417, QR} {
+		data, err := pass.PNG(symbology)
+		if err != nil {
+			t.Fatalf("%s png: %v", symbology, err)
+		}
+		if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+			t.Errorf("%s png does not decode: %v", symbology, err)
+		}
+
+		doc, err := pass.PDF(symbology)
+		if err != nil {
+			t.Fatalf("%s pdf: %v", symbology, err)
+		}
+		if !bytes.HasPrefix(doc, []byte("%PDF-")) {
+			t.Errorf("%s pdf has no PDF header", symbology)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	decoded, err := Decode(testPass().BCBP())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := Decoded{
+		PassengerName: "GARCIA LOPEZ/JOSE MA",
+		PNRCode:       "ABC123",
+		Origin:        "GRU",
+		Destination:   "JFK",
+		Airline:       "AA",
+		FlightNumber:  "123",
+		JulianDate:    73,
+		Compartment:   'Y',
+		SeatNo:        "12A",
+		Sequence:      7,
+		Status:        '1',
+	}
+	if *decoded != want {
+		t.Errorf("decoded %+v, want %+v", *decoded, want)
+	}
+}
+
+func TestDecodeRejects(t *testing.T) {
+	valid := testPass().BCBP()
+	for name, data := range map[string]string{
+		"empty":         "",
+		"truncated":     valid[:59],
+		"wrong format":  "S" + valid[1:],
+		"too many legs": "M9" + valid[2:],
+		"bad date":      valid[:44] + "ABC" + valid[47:],
+		"bad sequence":  valid[:52] + "00X7 " + valid[57:],
+		"blank PNR":     valid[:23] + "       " + valid[30:],
+	} {
+		if _, err := Decode(data); !errors.Is(err, ErrInvalidBCBP) {
+			t.Errorf("%s: expected ErrInvalidBCBP, got %v", name, err)
+		}
+	}
+}
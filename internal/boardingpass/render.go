@@ -0,0 +1,106 @@
+package boardingpass
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/pdf417"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+)
+
+// pdf417SecurityLevel is the error correction level for PDF417 symbols;
+// Resolution 792 asks for at least level 2
+const pdf417SecurityLevel = 2
+
+// Barcode returns the pass's BCBP data as a scannable image
+func (p Pass) Barcode(symbology Symbology) (image.Image, error) {
+	var (
+		code  barcode.Barcode
+		scale int
+		err   error
+	)
+	switch symbology {
+	case PDF417:
+		code, err = pdf417.Encode(p.BCBP(), pdf417SecurityLevel)
+		scale = 2
+	case QR:
+		code, err = qr.Encode(p.BCBP(), qr.M, qr.Auto)
+		scale = 6
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSymbology, symbology)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode barcode: %w", err)
+	}
+
+	bounds := code.Bounds()
+	return barcode.Scale(code, bounds.Dx()*scale, bounds.Dy()*scale)
+}
+
+// PNG renders the pass's barcode alone, for display on a phone
+func (p Pass) PNG(symbology Symbology) ([]byte, error) {
+	img, err := p.Barcode(symbology)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// PDF renders a printable boarding pass on a landscape A5 page
+func (p Pass) PDF(symbology Symbology) ([]byte, error) {
+	code, err := p.PNG(symbology)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := fpdf.New("L", "mm", "A5", "")
+	doc.SetTitle("Boarding pass "+p.PNRCode, true)
+	doc.AddPage()
+
+	doc.SetFont("Helvetica", "B", 20)
+	doc.CellFormat(0, 12, "BOARDING PASS", "", 1, "L", false, 0, "")
+	doc.SetFont("Helvetica", "", 12)
+	doc.CellFormat(0, 8, bcbpName(p.LastName, p.FirstName), "", 1, "L", false, 0, "")
+	doc.Ln(4)
+
+	rows := [][2][2]string{
+		{{"FROM", field(p.Origin, 3)}, {"TO", field(p.Destination, 3)}},
+		{{"FLIGHT", p.Airline + p.FlightNumber}, {"DATE", p.DepartureTime.Format("02 Jan 2006")}},
+		{{"DEPARTURE", p.DepartureTime.Format("15:04")}, {"BOARDING", p.BoardingTime.Format("15:04")}},
+		{{"SEAT", p.SeatNo}, {"CLASS", string(CompartmentCode(p.CabinClass))}},
+		{{"GROUP", p.BoardingGroup}, {"SEQ", fmt.Sprintf("%03d", p.Sequence)}},
+		{{"PNR", p.PNRCode}, {"", ""}},
+	}
+	for _, row := range rows {
+		for _, cell := range row {
+			doc.SetFont("Helvetica", "", 8)
+			doc.CellFormat(22, 8, cell[0], "", 0, "L", false, 0, "")
+			doc.SetFont("Helvetica", "B", 14)
+			doc.CellFormat(45, 8, cell[1], "", 0, "L", false, 0, "")
+		}
+		doc.Ln(8)
+	}
+
+	options := fpdf.ImageOptions{ImageType: "PNG"}
+	doc.RegisterImageOptionsReader("barcode", options, bytes.NewReader(code))
+	width := 55.0
+	if symbology == QR {
+		width = 45
+	}
+	doc.ImageOptions("barcode", 145, 30, width, 0, false, options, 0, "")
+
+	var buf bytes.Buffer
+	if err := doc.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render pdf: %w", err)
+	}
+	return buf.Bytes(), nil
+}
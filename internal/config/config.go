@@ -1,8 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -15,6 +19,21 @@ type Config struct {
 	Hold       HoldConfig
 	RateLimit  RateLimitConfig
 	Log        LogConfig
+	Payment    PaymentConfig
+	Currency   CurrencyConfig
+	CheckIn    CheckInConfig
+	Tracing    TracingConfig
+	Auth       AuthConfig
+
+	// path is the config file loaded, reread on reload
+	path     string
+	settings []Setting
+
+	// live is set by the first Reload; until then Live reads Hold,
+	// RateLimit and Log
+	live      atomic.Pointer[Live]
+	mu        sync.Mutex
+	listeners []func(Live)
 }
 
 type AppConfig struct {
@@ -32,94 +51,295 @@ type DatabaseConfig struct {
 	Charset   string
 	ParseTime bool
 	Loc       string
+	// MigrationsPath is the directory of golang-migrate files, the only
+	// source of the schema
+	MigrationsPath string
+	// MigrateOnStart applies pending migrations when the API starts;
+	// MigrateLockTimeout bounds the wait for another pod migrating
+	MigrateOnStart     bool
+	MigrateLockTimeout time.Duration
 }
 
 type ElasticsearchConfig struct {
 	Addresses []string
 	Username  string
 	Password  string
+	// BreakerFailures is how many consecutive failed requests open the
+	// circuit breaker; requests then fail fast for BreakerCooldown before
+	// one is let through to probe the cluster
+	BreakerFailures int
+	BreakerCooldown time.Duration
 }
 
 type HoldConfig struct {
 	TTLMinutes int
 	TTL        time.Duration
+	// PaymentTTL is how long a payment_pending hold is kept before it is
+	// rolled back to released
+	PaymentTTL time.Duration
 }
 
 type RateLimitConfig struct {
 	PerMinute int
 }
 
+type PaymentConfig struct {
+	// Provider selects the payment gateway; only "fake" is available, and
+	// production refuses it
+	Provider string
+	Timeout  time.Duration
+	// WebhookSecret signs the provider's webhook deliveries
+	WebhookSecret string
+	// WebhookTolerance is the allowed age of a webhook signature
+	WebhookTolerance time.Duration
+}
+
+type CurrencyConfig struct {
+	// Base is the currency fares are defined in; other currencies are
+	// converted from it with the exchange_rates table
+	Base string
+}
+
+type CheckInConfig struct {
+	// Opens is how long before departure online check-in opens
+	Opens time.Duration
+	// Closes is how long before departure online check-in closes
+	Closes time.Duration
+	// APISSender and APISReceiver address the PAXLST messages exported
+	// with a flight's passenger manifest
+	APISSender   string
+	APISReceiver string
+}
+
+type TracingConfig struct {
+	// Exporter selects where spans go: "otlp" (OTLP over HTTP to Endpoint),
+	// "stdout", or "none" to disable tracing
+	Exporter string
+	// Endpoint is the OTLP collector's base URL; http:// sends unencrypted
+	Endpoint    string
+	ServiceName string
+	// SampleRatio is the fraction of new traces recorded, from 0 to 1
+	SampleRatio float64
+}
+
+type AuthConfig struct {
+	// AdminToken is the bearer token for the /admin operations routes
+	AdminToken string
+	// GateToken is the bearer token for the gate routes, boarding scans
+	// and the boarding manifest; the admin token is accepted there too
+	GateToken string
+}
+
 type LogConfig struct {
 	Level  string
 	Format string
 }
 
+// Load reads the configuration from the file named by CONFIG_FILE, if
+// set, with environment variables taking precedence. See LoadFile.
 func Load() (*Config, error) {
+	return LoadFile(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadFile layers the configuration: defaults, then the YAML or TOML file
+// at path (none when path is empty), then environment variables, including
+// those in a .env file. Every invalid value is reported in one
+// *ValidationError.
+func LoadFile(path string) (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
-	holdTTLMinutes := getEnvAsInt("HOLD_TTL_MINUTES", 15)
+	file, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l := &loader{file: file, used: map[string]bool{}}
 
-	return &Config{
+	cfg := &Config{
 		App: AppConfig{
-			Env:  getEnv("APP_ENV", "development"),
-			Host: getEnv("APP_HOST", "0.0.0.0"),
-			Port: getEnv("APP_PORT", "8080"),
+			Env:  l.str("app.env", "APP_ENV", "development"),
+			Host: l.str("app.host", "APP_HOST", "0.0.0.0"),
+			Port: l.str("app.port", "APP_PORT", "8080"),
 		},
 		Database: DatabaseConfig{
-			Host:      getEnv("DB_HOST", "localhost"),
-			Port:      getEnv("DB_PORT", "3306"),
-			User:      getEnv("DB_USER", "airline_user"),
-			Password:  getEnv("DB_PASSWORD", "airline_pass"),
-			Name:      getEnv("DB_NAME", "airline_booking"),
-			Charset:   getEnv("DB_CHARSET", "utf8mb4"),
-			ParseTime: getEnvAsBool("DB_PARSE_TIME", true),
-			Loc:       getEnv("DB_LOC", "UTC"),
+			Host:               l.str("database.host", "DB_HOST", "localhost"),
+			Port:               l.str("database.port", "DB_PORT", "3306"),
+			User:               l.str("database.user", "DB_USER", "airline_user"),
+			Password:           l.secret("database.password", "DB_PASSWORD", "airline_pass"),
+			Name:               l.str("database.name", "DB_NAME", "airline_booking"),
+			Charset:            l.str("database.charset", "DB_CHARSET", "utf8mb4"),
+			ParseTime:          l.bool("database.parse_time", "DB_PARSE_TIME", true),
+			Loc:                l.str("database.loc", "DB_LOC", "UTC"),
+			MigrationsPath:     l.str("database.migrations_path", "MIGRATIONS_PATH", "migrations"),
+			MigrateOnStart:     l.bool("database.migrate_on_start", "MIGRATE_ON_START", false),
+			MigrateLockTimeout: l.seconds("database.migrate_lock_timeout_seconds", "MIGRATE_LOCK_TIMEOUT_SECONDS", 300),
 		},
 		Elasticsearch: ElasticsearchConfig{
-			Addresses: []string{getEnv("ES_ADDRESSES", "http://localhost:9200")},
-			Username:  getEnv("ES_USERNAME", ""),
-			Password:  getEnv("ES_PASSWORD", ""),
+			Addresses:       l.list("elasticsearch.addresses", "ES_ADDRESSES", "http://localhost:9200"),
+			Username:        l.str("elasticsearch.username", "ES_USERNAME", ""),
+			Password:        l.secret("elasticsearch.password", "ES_PASSWORD", ""),
+			BreakerFailures: l.int("elasticsearch.breaker_failures", "ES_BREAKER_FAILURES", 5),
+			BreakerCooldown: l.seconds("elasticsearch.breaker_cooldown_seconds", "ES_BREAKER_COOLDOWN_SECONDS", 30),
 		},
 		Hold: HoldConfig{
-			TTLMinutes: holdTTLMinutes,
-			TTL:        time.Duration(holdTTLMinutes) * time.Minute,
+			TTLMinutes: l.int("hold.ttl_minutes", "HOLD_TTL_MINUTES", 15),
+			PaymentTTL: time.Duration(l.int("hold.payment_ttl_minutes", "HOLD_PAYMENT_TTL_MINUTES", 10)) * time.Minute,
 		},
 		RateLimit: RateLimitConfig{
-			PerMinute: getEnvAsInt("RATE_LIMIT_PER_MINUTE", 60),
+			PerMinute: l.int("rate_limit.per_minute", "RATE_LIMIT_PER_MINUTE", 60),
 		},
 		Log: LogConfig{
-			Level:  getEnv("LOG_LEVEL", "info"),
-			Format: getEnv("LOG_FORMAT", "json"),
+			Level:  strings.ToLower(l.str("log.level", "LOG_LEVEL", "info")),
+			Format: strings.ToLower(l.str("log.format", "LOG_FORMAT", "json")),
+		},
+		Payment: PaymentConfig{
+			Provider:         l.str("payment.provider", "PAYMENT_PROVIDER", "fake"),
+			Timeout:          l.seconds("payment.timeout_seconds", "PAYMENT_TIMEOUT_SECONDS", 10),
+			WebhookSecret:    l.secret("payment.webhook_secret", "PAYMENT_WEBHOOK_SECRET", DevWebhookSecret),
+			WebhookTolerance: l.seconds("payment.webhook_tolerance_seconds", "PAYMENT_WEBHOOK_TOLERANCE_SECONDS", 300),
+		},
+		Currency: CurrencyConfig{
+			Base: strings.ToUpper(l.str("currency.base", "BASE_CURRENCY", "USD")),
+		},
+		CheckIn: CheckInConfig{
+			Opens:        time.Duration(l.int("check_in.opens_hours", "CHECKIN_OPENS_HOURS", 24)) * time.Hour,
+			Closes:       time.Duration(l.int("check_in.closes_minutes", "CHECKIN_CLOSES_MINUTES", 45)) * time.Minute,
+			APISSender:   l.str("check_in.apis_sender_id", "APIS_SENDER_ID", "AIRLINEBOOKING"),
+			APISReceiver: l.str("check_in.apis_receiver_id", "APIS_RECEIVER_ID", "APIS"),
+		},
+		Tracing: TracingConfig{
+			Exporter:    strings.ToLower(l.str("tracing.exporter", "OTEL_TRACES_EXPORTER", "none")),
+			Endpoint:    l.str("tracing.otlp_endpoint", "OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+			ServiceName: l.str("tracing.service_name", "OTEL_SERVICE_NAME", "airline-booking"),
+			SampleRatio: l.float("tracing.sample_ratio", "OTEL_TRACES_SAMPLER_RATIO", 1),
 		},
-	}, nil
+		Auth: AuthConfig{
+			AdminToken: l.secret("auth.admin_token", "ADMIN_API_TOKEN", DevAdminToken),
+			GateToken:  l.secret("auth.gate_token", "GATE_API_TOKEN", DevGateToken),
+		},
+		path:     path,
+		settings: l.settings,
+	}
+	cfg.Hold.TTL = time.Duration(cfg.Hold.TTLMinutes) * time.Minute
+
+	problems := l.problems
+	for _, key := range sortedKeys(file) {
+		if !l.used[key] {
+			problems = append(problems, fmt.Sprintf("%s: unknown setting in %s", key, path))
+		}
+	}
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return cfg, nil
 }
 
 func (d DatabaseConfig) DSN() string {
 	return d.User + ":" + d.Password + "@tcp(" + d.Host + ":" + d.Port + ")/" + d.Name + "?charset=" + d.Charset + "&parseTime=" + strconv.FormatBool(d.ParseTime) + "&loc=" + d.Loc
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// Setting sources, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+)
+
+// Setting is one configuration value as loaded, before conversion
+type Setting struct {
+	// Key names the setting in the config file, e.g. "hold.ttl_minutes"
+	Key string
+	Env string
+	// Value is the raw value; secrets are kept so reloads can compare them,
+	// and redacted when printed
+	Value  string
+	Source string
+	Secret bool
+}
+
+// Settings returns every setting in load order
+func (c *Config) Settings() []Setting {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.settings
+}
+
+// loader resolves each setting from the environment, the config file or
+// its default, recording parse errors instead of falling back silently
+type loader struct {
+	file     map[string]string
+	used     map[string]bool
+	settings []Setting
+	problems []string
+}
+
+func (l *loader) lookup(key, env, defaultValue string, secret bool) string {
+	value, source := defaultValue, SourceDefault
+	if fileValue, ok := l.file[key]; ok {
+		l.used[key] = true
+		value, source = fileValue, SourceFile
+	}
+	if envValue := os.Getenv(env); envValue != "" {
+		value, source = envValue, SourceEnv
 	}
-	return defaultValue
+	l.settings = append(l.settings, Setting{Key: key, Env: env, Value: value, Source: source, Secret: secret})
+	return value
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+func (l *loader) invalid(key, env, value, kind string) {
+	l.problems = append(l.problems, fmt.Sprintf("%s (%s): %q is not %s", key, env, value, kind))
+}
+
+func (l *loader) str(key, env, defaultValue string) string {
+	return l.lookup(key, env, defaultValue, false)
+}
+
+func (l *loader) secret(key, env, defaultValue string) string {
+	return l.lookup(key, env, defaultValue, true)
+}
+
+func (l *loader) int(key, env string, defaultValue int) int {
+	value := l.lookup(key, env, strconv.Itoa(defaultValue), false)
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		l.invalid(key, env, value, "an integer")
+		return defaultValue
+	}
+	return intValue
+}
+
+func (l *loader) seconds(key, env string, defaultValue int) time.Duration {
+	return time.Duration(l.int(key, env, defaultValue)) * time.Second
+}
+
+func (l *loader) bool(key, env string, defaultValue bool) bool {
+	value := l.lookup(key, env, strconv.FormatBool(defaultValue), false)
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		l.invalid(key, env, value, "a boolean")
+		return defaultValue
+	}
+	return boolValue
+}
+
+func (l *loader) float(key, env string, defaultValue float64) float64 {
+	value := l.lookup(key, env, strconv.FormatFloat(defaultValue, 'g', -1, 64), false)
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		l.invalid(key, env, value, "a number")
+		return defaultValue
 	}
-	return defaultValue
+	return floatValue
 }
 
-func getEnvAsBool(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
-			return boolValue
+// list splits a comma-separated value
+func (l *loader) list(key, env, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(l.lookup(key, env, defaultValue, false), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
 		}
 	}
-	return defaultValue
+	return values
 }
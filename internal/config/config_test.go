@@ -1,8 +1,13 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestConfigDefaults(t *testing.T) {
@@ -37,6 +42,18 @@ func TestConfigDefaults(t *testing.T) {
 	if cfg.App.Env != "development" {
 		t.Errorf("Expected default environment 'development', got %s", cfg.App.Env)
 	}
+
+	if cfg.Payment.Provider != "fake" {
+		t.Errorf("Expected default payment provider 'fake', got %s", cfg.Payment.Provider)
+	}
+
+	if cfg.Currency.Base != "USD" {
+		t.Errorf("Expected default base currency 'USD', got %s", cfg.Currency.Base)
+	}
+
+	if cfg.CheckIn.Opens != 24*time.Hour || cfg.CheckIn.Closes != 45*time.Minute {
+		t.Errorf("Expected check-in from 24h to 45m before departure, got %s to %s", cfg.CheckIn.Opens, cfg.CheckIn.Closes)
+	}
 }
 
 func TestConfigEnvironmentOverride(t *testing.T) {
@@ -63,3 +80,246 @@ func TestConfigEnvironmentOverride(t *testing.T) {
 		t.Errorf("Expected environment 'test', got %s", cfg.App.Env)
 	}
 }
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoadFileLayersEnvOverFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+app:
+  port: 7070
+hold:
+  ttl_minutes: 20
+elasticsearch:
+  addresses:
+    - http://es1:9200
+    - http://es2:9200
+rate_limit:
+  per_minute: 30
+`)
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "90")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.App.Port != "7070" {
+		t.Errorf("Expected port 7070 from the file, got %s", cfg.App.Port)
+	}
+	if cfg.Hold.TTL != 20*time.Minute {
+		t.Errorf("Expected hold TTL 20m from the file, got %s", cfg.Hold.TTL)
+	}
+	if len(cfg.Elasticsearch.Addresses) != 2 || cfg.Elasticsearch.Addresses[1] != "http://es2:9200" {
+		t.Errorf("Expected both Elasticsearch addresses from the file, got %v", cfg.Elasticsearch.Addresses)
+	}
+	if cfg.RateLimit.PerMinute != 90 {
+		t.Errorf("Expected RATE_LIMIT_PER_MINUTE to override the file, got %d", cfg.RateLimit.PerMinute)
+	}
+
+	sources := map[string]string{}
+	for _, setting := range cfg.Settings() {
+		sources[setting.Key] = setting.Source
+	}
+	if sources["app.port"] != SourceFile || sources["rate_limit.per_minute"] != SourceEnv || sources["log.level"] != SourceDefault {
+		t.Errorf("Unexpected setting sources: %v", sources)
+	}
+}
+
+func TestLoadFileTOML(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+[hold]
+payment_ttl_minutes = 5
+
+[log]
+level = "DEBUG"
+`)
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	if cfg.Hold.PaymentTTL != 5*time.Minute {
+		t.Errorf("Expected payment hold TTL 5m, got %s", cfg.Hold.PaymentTTL)
+	}
+	if cfg.Log.Level != "debug" {
+		t.Errorf("Expected log level debug, got %s", cfg.Log.Level)
+	}
+}
+
+func TestLoadFileReportsAllProblems(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+hold:
+  ttl_minutes: 0
+rate_limt:
+  per_minute: 10
+`)
+	t.Setenv("RATE_LIMIT_PER_MINUTE", "0")
+	t.Setenv("ES_ADDRESSES", "es:9200")
+	t.Setenv("APP_PORT", "http")
+
+	_, err := LoadFile(path)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+
+	for _, key := range []string{"hold.ttl_minutes", "rate_limt.per_minute", "rate_limit.per_minute", "elasticsearch.addresses", "app.port"} {
+		found := false
+		for _, problem := range validationErr.Problems {
+			if strings.HasPrefix(problem, key+":") || strings.HasPrefix(problem, key+" ") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected a problem for %s, got %v", key, validationErr.Problems)
+		}
+	}
+}
+
+func TestProductionRejectsDevWebhookSecret(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+app:
+  env: production
+`)
+	t.Setenv("APP_ENV", "")
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "")
+	t.Setenv("ADMIN_API_TOKEN", "admin_private")
+	t.Setenv("GATE_API_TOKEN", "gate_private")
+
+	_, err := LoadFile(path)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || !strings.Contains(err.Error(), "payment.webhook_secret") {
+		t.Fatalf("Expected a payment.webhook_secret problem, got %v", err)
+	}
+
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "whsec_private")
+	if _, err := LoadFile(path); err != nil && strings.Contains(err.Error(), "payment.webhook_secret") {
+		t.Fatalf("Expected a private secret to be accepted, got %v", err)
+	}
+}
+
+func TestProductionRejectsDevAPITokens(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+app:
+  env: production
+`)
+	t.Setenv("APP_ENV", "")
+	t.Setenv("PAYMENT_WEBHOOK_SECRET", "whsec_private")
+	t.Setenv("ADMIN_API_TOKEN", "")
+	t.Setenv("GATE_API_TOKEN", "")
+
+	_, err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), "auth.admin_token") || !strings.Contains(err.Error(), "auth.gate_token") {
+		t.Fatalf("Expected auth.admin_token and auth.gate_token problems, got %v", err)
+	}
+
+	t.Setenv("ADMIN_API_TOKEN", "admin_private")
+	t.Setenv("GATE_API_TOKEN", "gate_private")
+	if _, err := LoadFile(path); err != nil && strings.Contains(err.Error(), "auth.") {
+		t.Fatalf("Expected private tokens to be accepted, got %v", err)
+	}
+}
+
+func TestProductionRejectsFakePaymentProvider(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+app:
+  env: production
+payment:
+  provider: fake
+`)
+	t.Setenv("APP_ENV", "")
+	t.Setenv("PAYMENT_PROVIDER", "")
+
+	_, err := LoadFile(path)
+	if err == nil || !strings.Contains(err.Error(), "payment.provider") {
+		t.Fatalf("Expected a payment.provider problem, got %v", err)
+	}
+
+	t.Setenv("APP_ENV", "development")
+	if _, err := LoadFile(path); err != nil && strings.Contains(err.Error(), "payment.provider") {
+		t.Fatalf("Expected the fake provider outside production, got %v", err)
+	}
+}
+
+func TestLoadFileUnsupportedFormat(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{}`)
+
+	if _, err := LoadFile(path); err == nil {
+		t.Fatal("Expected an error for a .json config file")
+	}
+}
+
+func TestReloadAppliesLiveSettingsOnly(t *testing.T) {
+	cfg, err := LoadFile("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	var notified []Live
+	cfg.OnReload(func(live Live) {
+		notified = append(notified, live)
+	})
+
+	t.Setenv("HOLD_TTL_MINUTES", "30")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("APP_PORT", "9191")
+	next, err := LoadFile("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	changed, needRestart := cfg.Reload(next)
+	if strings.Join(changed, ",") != "hold.ttl_minutes,log.level" {
+		t.Errorf("Expected hold.ttl_minutes and log.level to change, got %v", changed)
+	}
+	if strings.Join(needRestart, ",") != "app.port" {
+		t.Errorf("Expected app.port to need a restart, got %v", needRestart)
+	}
+
+	live := cfg.Live()
+	if live.Hold.TTL != 30*time.Minute || live.LogLevel != "debug" {
+		t.Errorf("Expected the reloaded hold TTL and log level, got %s and %s", live.Hold.TTL, live.LogLevel)
+	}
+	if cfg.App.Port == "9191" {
+		t.Error("Expected app.port to keep its value until restart")
+	}
+	if len(notified) != 1 || notified[0].Hold.TTL != 30*time.Minute {
+		t.Errorf("Expected one reload notification, got %v", notified)
+	}
+
+	// Reloading the same values again changes nothing
+	if changed, _ := cfg.Reload(next); len(changed) != 0 {
+		t.Errorf("Expected no changes on a repeated reload, got %v", changed)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "hunter2")
+	cfg, err := LoadFile("")
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("Failed to print config: %v", err)
+	}
+
+	if strings.Contains(out.String(), "hunter2") {
+		t.Error("Expected the database password to be redacted")
+	}
+	if !strings.Contains(out.String(), `password: "<redacted>" # env (DB_PASSWORD)`) {
+		t.Errorf("Expected a redacted database password line, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "hold:\n  ttl_minutes: 15 # default (HOLD_TTL_MINUTES)") {
+		t.Errorf("Expected the hold section with its default TTL, got:\n%s", out.String())
+	}
+}
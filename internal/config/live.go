@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// liveKeys are the settings Reload applies to a running service; the rest
+// take a restart
+var liveKeys = map[string]bool{
+	"hold.ttl_minutes":         true,
+	"hold.payment_ttl_minutes": true,
+	"rate_limit.per_minute":    true,
+	"log.level":                true,
+}
+
+// Live is the part of the configuration that can change while the
+// service runs
+type Live struct {
+	Hold      HoldConfig
+	RateLimit RateLimitConfig
+	LogLevel  string
+}
+
+// Live returns the current hold timers, rate limit and log level. Read it
+// where the value is used rather than keeping a copy, so reloads apply.
+func (c *Config) Live() Live {
+	if live := c.live.Load(); live != nil {
+		return *live
+	}
+	return Live{Hold: c.Hold, RateLimit: c.RateLimit, LogLevel: c.Log.Level}
+}
+
+// OnReload registers fn to be called with the new values after each
+// Reload that changes them
+func (c *Config) OnReload(fn func(Live)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, fn)
+}
+
+// Path returns the config file the configuration was loaded from, if any
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Reload applies the live settings of next, a freshly loaded
+// configuration, and returns the keys it changed and those that differ but
+// take a restart and were left as they are
+func (c *Config) Reload(next *Config) (changed, needRestart []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := map[string]string{}
+	for _, setting := range c.settings {
+		current[setting.Key] = setting.Value
+	}
+	for _, setting := range next.settings {
+		if current[setting.Key] == setting.Value {
+			continue
+		}
+		if liveKeys[setting.Key] {
+			changed = append(changed, setting.Key)
+		} else {
+			needRestart = append(needRestart, setting.Key)
+		}
+	}
+	if len(changed) == 0 {
+		return nil, needRestart
+	}
+
+	// Later reloads compare against what is applied: new live values, the
+	// original values of everything else
+	live := next.Live()
+	c.live.Store(&live)
+	settings := make([]Setting, len(c.settings))
+	copy(settings, c.settings)
+	for i := range settings {
+		if liveKeys[settings[i].Key] {
+			for _, setting := range next.settings {
+				if setting.Key == settings[i].Key {
+					settings[i] = setting
+				}
+			}
+		}
+	}
+	c.settings = settings
+
+	for _, fn := range c.listeners {
+		fn(live)
+	}
+	return changed, needRestart
+}
+
+// Print writes the configuration as YAML, in the config file layout, with
+// each value's source and secrets redacted
+func (c *Config) Print(w io.Writer) error {
+	section := ""
+	for _, setting := range c.Settings() {
+		prefix, name, _ := strings.Cut(setting.Key, ".")
+		if prefix != section {
+			if _, err := fmt.Fprintf(w, "%s:\n", prefix); err != nil {
+				return err
+			}
+			section = prefix
+		}
+
+		value := setting.Value
+		switch {
+		case setting.Secret && value != "":
+			value = `"<redacted>"`
+		case !plainScalar(value):
+			value = strconv.Quote(value)
+		}
+		if _, err := fmt.Fprintf(w, "  %s: %s # %s (%s)\n", name, value, setting.Source, setting.Env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// plainScalar reports whether value reads back the same unquoted in YAML:
+// numbers and booleans
+func plainScalar(value string) bool {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return true
+	}
+	_, err := strconv.ParseBool(value)
+	return err == nil && (value == "true" || value == "false")
+}
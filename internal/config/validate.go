@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bounds on the hold timers: shorter holds expire before a customer can
+// pay, longer ones keep seats off sale
+const (
+	minHoldTTL        = time.Minute
+	maxHoldTTL        = 2 * time.Hour
+	minPaymentHoldTTL = time.Minute
+	maxPaymentHoldTTL = time.Hour
+)
+
+// The secrets local setups default to. They are published in the
+// repository, so production refuses them.
+const (
+	DevWebhookSecret = "whsec_local_development"
+	DevAdminToken    = "admin_local_development"
+	DevGateToken     = "gate_local_development"
+)
+
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// ValidationError lists every problem found while loading the
+// configuration, so they can all be fixed in one go
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// validate checks the loaded values, naming each problem by its config
+// file key
+func (c *Config) validate() []string {
+	var problems []string
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, key+": "+fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(validPort(c.App.Port), "app.port", "must be a port number, got %q", c.App.Port)
+
+	check(c.Database.Host != "", "database.host", "must not be empty")
+	check(validPort(c.Database.Port), "database.port", "must be a port number, got %q", c.Database.Port)
+	check(c.Database.User != "", "database.user", "must not be empty")
+	check(c.Database.Name != "", "database.name", "must not be empty")
+	check(c.Database.MigrationsPath != "", "database.migrations_path", "must not be empty")
+	check(c.Database.MigrateLockTimeout > 0, "database.migrate_lock_timeout_seconds", "must be greater than 0")
+
+	check(len(c.Elasticsearch.Addresses) > 0, "elasticsearch.addresses", "must list at least one address")
+	for _, address := range c.Elasticsearch.Addresses {
+		check(validURL(address), "elasticsearch.addresses", "%q is not an http(s) URL", address)
+	}
+	check(c.Elasticsearch.BreakerFailures > 0, "elasticsearch.breaker_failures", "must be greater than 0, got %d", c.Elasticsearch.BreakerFailures)
+	check(c.Elasticsearch.BreakerCooldown > 0, "elasticsearch.breaker_cooldown_seconds", "must be greater than 0")
+
+	check(c.Hold.TTL >= minHoldTTL && c.Hold.TTL <= maxHoldTTL,
+		"hold.ttl_minutes", "must be between %s and %s, got %s", minHoldTTL, maxHoldTTL, c.Hold.TTL)
+	check(c.Hold.PaymentTTL >= minPaymentHoldTTL && c.Hold.PaymentTTL <= maxPaymentHoldTTL,
+		"hold.payment_ttl_minutes", "must be between %s and %s, got %s", minPaymentHoldTTL, maxPaymentHoldTTL, c.Hold.PaymentTTL)
+
+	check(c.RateLimit.PerMinute > 0, "rate_limit.per_minute", "must be greater than 0, got %d", c.RateLimit.PerMinute)
+
+	check(validLogLevel(c.Log.Level), "log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	check(c.Log.Format == "json" || c.Log.Format == "console", "log.format", "must be json or console, got %q", c.Log.Format)
+
+	check(c.Payment.Timeout > 0, "payment.timeout_seconds", "must be greater than 0")
+	check(c.App.Env != "production" || c.Payment.Provider != "fake",
+		"payment.provider", "must be a real payment provider in production, the fake gateway approves any card")
+	check(c.Payment.WebhookSecret != "", "payment.webhook_secret", "must not be empty")
+	check(c.App.Env != "production" || c.Payment.WebhookSecret != DevWebhookSecret,
+		"payment.webhook_secret", "must be set to a private secret in production")
+	check(c.Payment.WebhookTolerance > 0, "payment.webhook_tolerance_seconds", "must be greater than 0")
+
+	check(currencyCode.MatchString(c.Currency.Base), "currency.base", "must be a 3-letter currency code, got %q", c.Currency.Base)
+
+	check(c.CheckIn.Closes >= 0, "check_in.closes_minutes", "must not be negative")
+	check(c.CheckIn.Opens > c.CheckIn.Closes, "check_in.opens_hours", "check-in must open before it closes")
+
+	check(c.Auth.AdminToken != "", "auth.admin_token", "must not be empty")
+	check(c.Auth.GateToken != "", "auth.gate_token", "must not be empty")
+	check(c.App.Env != "production" || c.Auth.AdminToken != DevAdminToken,
+		"auth.admin_token", "must be set to a private token in production")
+	check(c.App.Env != "production" || c.Auth.GateToken != DevGateToken,
+		"auth.gate_token", "must be set to a private token in production")
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		check(validURL(c.Tracing.Endpoint), "tracing.otlp_endpoint", "%q is not an http(s) URL", c.Tracing.Endpoint)
+	default:
+		check(false, "tracing.exporter", "must be none, otlp or stdout, got %q", c.Tracing.Exporter)
+	}
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "must not be empty")
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "tracing.sample_ratio", "must be between 0 and 1, got %g", c.Tracing.SampleRatio)
+
+	return problems
+}
+
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+func validURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func validLogLevel(level string) bool {
+	switch level {
+	case "debug", "info", "warn", "error":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+// Package currency converts amounts between ISO 4217 currencies. Amounts are
+// always integers in the currency's minor unit (cents for USD, yen for JPY).
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	// ErrInvalidCode is returned for anything that is not a three-letter code
+	ErrInvalidCode = errors.New("invalid currency code")
+	// ErrInvalidRate is returned for a rate that is not a positive decimal
+	ErrInvalidRate = errors.New("invalid exchange rate")
+	// ErrInvalidAmount is returned for an amount that is not a positive
+	// decimal or has more decimals than its currency's minor unit
+	ErrInvalidAmount = errors.New("invalid amount")
+)
+
+// minorUnits lists the currencies whose minor unit is not a hundredth
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0,
+	"XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// Normalize upper-cases code and checks that it looks like an ISO 4217 code
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCode, code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCode, code)
+		}
+	}
+	return code, nil
+}
+
+// MinorUnits returns the number of decimals used by code
+func MinorUnits(code string) int {
+	if n, ok := minorUnits[code]; ok {
+		return n
+	}
+	return 2
+}
+
+// ParseRate parses a decimal exchange rate such as "5.1234"
+func ParseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || rate.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, s)
+	}
+	return rate, nil
+}
+
+// ParseAmount parses a decimal amount such as "399.99" into code's minor
+// unit. Amounts finer than the minor unit are refused rather than rounded.
+func ParseAmount(s, code string) (int64, error) {
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || value.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	value.Mul(value, pow10(MinorUnits(code)))
+	if !value.IsInt() || !value.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q has more than %d decimals for %s", ErrInvalidAmount, s, MinorUnits(code), code)
+	}
+	return value.Num().Int64(), nil
+}
+
+// Convert converts amount, in from's minor unit, to to's minor unit at rate
+// (units of to per unit of from). The result is rounded half away from zero
+// to to's precision, so a JPY price never carries fractional yen.
+func Convert(amount int64, from, to string, rate *big.Rat) int64 {
+	value := new(big.Rat).SetInt64(amount)
+	value.Mul(value, rate)
+	value.Mul(value, pow10(MinorUnits(to)))
+	value.Quo(value, pow10(MinorUnits(from)))
+	return roundHalfAway(value)
+}
+
+func pow10(n int) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+func roundHalfAway(value *big.Rat) int64 {
+	num := new(big.Int).Abs(value.Num())
+	den := value.Denom()
+
+	// floor(|v| + 1/2) = floor((2|num| + den) / 2den)
+	num.Mul(num, big.NewInt(2))
+	num.Add(num, den)
+	result := num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+
+	if value.Sign() < 0 {
+		result.Neg(result)
+	}
+	return result.Int64()
+}
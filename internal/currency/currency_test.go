@@ -0,0 +1,87 @@
+package currency
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		from   string
+		to     string
+		rate   string
+		want   int64
+	}{
+		{"same precision", 29900, "USD", "EUR", "0.92", 27508},
+		{"zero decimal target", 29900, "USD", "JPY", "149.537", 44712},
+		{"three decimal target", 29900, "USD", "BHD", "0.376", 112424},
+		{"rounds half up", 1, "USD", "EUR", "0.5", 1},
+		{"rounds down below half", 1, "USD", "EUR", "0.49", 0},
+		{"zero decimal source", 44712, "JPY", "USD", "0.0066872", 29900},
+		{"negative rounds away from zero", -1, "USD", "EUR", "0.5", -1},
+		{"identity", 29900, "USD", "USD", "1", 29900},
+	}
+
+	for _, tt := range tests {
+		rate, err := ParseRate(tt.rate)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got := Convert(tt.amount, tt.from, tt.to, rate); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	code, err := Normalize(" eur ")
+	if err != nil || code != "EUR" {
+		t.Fatalf("got (%q, %v), want EUR", code, err)
+	}
+
+	for _, code := range []string{"", "EU", "EURO", "E1R"} {
+		if _, err := Normalize(code); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("%q: expected ErrInvalidCode, got %v", code, err)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	for _, rate := range []string{"", "abc", "0", "-1.5"} {
+		if _, err := ParseRate(rate); !errors.Is(err, ErrInvalidRate) {
+			t.Errorf("%q: expected ErrInvalidRate, got %v", rate, err)
+		}
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		amount string
+		code   string
+		want   int64
+	}{
+		{"399.99", "USD", 39999},
+		{"1500", "USD", 150000},
+		{"0.5", "EUR", 50},
+		{"45000", "JPY", 45000},
+		{"12.345", "BHD", 12345},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.amount, tt.code)
+		if err != nil || got != tt.want {
+			t.Errorf("%s %s: got (%d, %v), want %d", tt.amount, tt.code, got, err, tt.want)
+		}
+	}
+
+	invalid := []struct{ amount, code string }{
+		{"", "USD"}, {"abc", "USD"}, {"0", "USD"}, {"-1", "USD"},
+		{"399.999", "USD"}, {"1.5", "JPY"},
+	}
+	for _, tt := range invalid {
+		if _, err := ParseAmount(tt.amount, tt.code); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("%q %s: expected ErrInvalidAmount, got %v", tt.amount, tt.code, err)
+		}
+	}
+}
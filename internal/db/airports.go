@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+)
+
+// Placeholder implementations for sql/queries/airports.sql - these will be generated by sqlc
+
+type UpsertAirportParams struct {
+	IataCode   string
+	Name       string
+	City       string
+	Country    string
+	Timezone   string
+	Latitude   float64
+	Longitude  float64
+	Popularity int32
+}
+
+func (q *Queries) GetAirport(ctx context.Context, iataCode string) (Airport, error) {
+	query := `SELECT iata_code, name, city, country, timezone, latitude, longitude, popularity, created_at, updated_at
+	FROM airports WHERE iata_code = ?`
+
+	var a Airport
+	err := q.db.QueryRowContext(ctx, query, iataCode).Scan(
+		&a.IataCode, &a.Name, &a.City, &a.Country, &a.Timezone,
+		&a.Latitude, &a.Longitude, &a.Popularity, &a.CreatedAt, &a.UpdatedAt,
+	)
+
+	return a, err
+}
+
+func (q *Queries) ListAirports(ctx context.Context) ([]Airport, error) {
+	query := `SELECT iata_code, name, city, country, timezone, latitude, longitude, popularity, created_at, updated_at
+	FROM airports ORDER BY iata_code`
+
+	rows, err := q.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	airports := []Airport{}
+	for rows.Next() {
+		var a Airport
+		if err := rows.Scan(
+			&a.IataCode, &a.Name, &a.City, &a.Country, &a.Timezone,
+			&a.Latitude, &a.Longitude, &a.Popularity, &a.CreatedAt, &a.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		airports = append(airports, a)
+	}
+
+	return airports, rows.Err()
+}
+
+func (q *Queries) UpsertAirport(ctx context.Context, arg UpsertAirportParams) error {
+	query := `INSERT INTO airports (iata_code, name, city, country, timezone, latitude, longitude, popularity)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		name = VALUES(name), city = VALUES(city), country = VALUES(country), timezone = VALUES(timezone),
+		latitude = VALUES(latitude), longitude = VALUES(longitude), popularity = VALUES(popularity),
+		updated_at = CURRENT_TIMESTAMP`
+
+	_, err := q.db.ExecContext(ctx, query,
+		arg.IataCode, arg.Name, arg.City, arg.Country, arg.Timezone,
+		arg.Latitude, arg.Longitude, arg.Popularity)
+	return err
+}
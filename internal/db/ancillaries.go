@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/ancillaries.sql - these will be generated by sqlc
+
+type Ancillary struct {
+	ID                int64          `json:"id"`
+	Code              string         `json:"code"`
+	Category          string         `json:"category"`
+	Name              string         `json:"name"`
+	PriceAmount       int64          `json:"price_amount"`
+	FlightID          sql.NullInt64  `json:"flight_id"`
+	Origin            sql.NullString `json:"origin"`
+	Destination       sql.NullString `json:"destination"`
+	CapacityPerFlight sql.NullInt32  `json:"capacity_per_flight"`
+	MaxPerTicket      int32          `json:"max_per_ticket"`
+	Active            bool           `json:"active"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+type ListFlightAncillariesParams struct {
+	FlightID    int64
+	Origin      string
+	Destination string
+}
+
+type ListFlightAncillariesRow struct {
+	Ancillary
+	Sold int32 `json:"sold"`
+}
+
+type AncillaryInventoryParams struct {
+	FlightID    int64
+	AncillaryID int64
+}
+
+type ReserveAncillaryUnitsParams struct {
+	Quantity    int32
+	FlightID    int64
+	AncillaryID int64
+}
+
+type ReleaseTicketAncillaryUnitsParams struct {
+	TicketID int64
+	FlightID int64
+}
+
+// ListFlightAncillaries returns the active ancillaries offered on a flight
+// with the units each has sold on it
+func (q *Queries) ListFlightAncillaries(ctx context.Context, arg ListFlightAncillariesParams) ([]ListFlightAncillariesRow, error) {
+	query := `SELECT a.id, a.code, a.category, a.name, a.price_amount, a.flight_id, a.origin, a.destination,
+		a.capacity_per_flight, a.max_per_ticket, a.active, a.created_at, a.updated_at, COALESCE(i.sold, 0)
+	FROM ancillaries a
+	LEFT JOIN ancillary_inventory i ON i.ancillary_id = a.id AND i.flight_id = ?
+	WHERE a.active = TRUE
+	  AND (a.flight_id IS NULL OR a.flight_id = ?)
+	  AND (a.origin IS NULL OR a.origin = ?)
+	  AND (a.destination IS NULL OR a.destination = ?)
+	ORDER BY a.category, a.id`
+
+	rows, err := q.db.QueryContext(ctx, query, arg.FlightID, arg.FlightID, arg.Origin, arg.Destination)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []ListFlightAncillariesRow
+	for rows.Next() {
+		var i ListFlightAncillariesRow
+		if err := rows.Scan(&i.ID, &i.Code, &i.Category, &i.Name, &i.PriceAmount, &i.FlightID, &i.Origin,
+			&i.Destination, &i.CapacityPerFlight, &i.MaxPerTicket, &i.Active, &i.CreatedAt, &i.UpdatedAt,
+			&i.Sold); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
+
+func (q *Queries) EnsureAncillaryInventory(ctx context.Context, arg AncillaryInventoryParams) error {
+	query := `INSERT INTO ancillary_inventory (flight_id, ancillary_id, sold)
+	VALUES (?, ?, 0)
+	ON DUPLICATE KEY UPDATE flight_id = flight_id`
+	_, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.AncillaryID)
+	return err
+}
+
+// ReserveAncillaryUnits sells Quantity units if that keeps the flight within
+// the ancillary's capacity; no row is affected otherwise
+func (q *Queries) ReserveAncillaryUnits(ctx context.Context, arg ReserveAncillaryUnitsParams) (int64, error) {
+	query := `UPDATE ancillary_inventory i
+	JOIN ancillaries a ON a.id = i.ancillary_id
+	SET i.sold = i.sold + ?
+	WHERE i.flight_id = ? AND i.ancillary_id = ?
+	  AND i.sold + ? <= a.capacity_per_flight`
+
+	result, err := q.db.ExecContext(ctx, query, arg.Quantity, arg.FlightID, arg.AncillaryID, arg.Quantity)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ReleaseTicketAncillaryUnits returns the units of every ancillary on a
+// ticket to the flight's inventory
+func (q *Queries) ReleaseTicketAncillaryUnits(ctx context.Context, arg ReleaseTicketAncillaryUnitsParams) error {
+	query := `UPDATE ancillary_inventory i
+	JOIN (
+		SELECT a.id AS ancillary_id, COUNT(*) AS units
+		FROM ticket_line_items li
+		JOIN ancillaries a ON a.code = li.code
+		WHERE li.ticket_id = ? AND li.component = 'ancillary'
+		GROUP BY a.id
+	) s ON s.ancillary_id = i.ancillary_id
+	SET i.sold = GREATEST(i.sold - s.units, 0)
+	WHERE i.flight_id = ?`
+	_, err := q.db.ExecContext(ctx, query, arg.TicketID, arg.FlightID)
+	return err
+}
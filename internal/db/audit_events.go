@@ -0,0 +1,101 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/audit_events.sql - these will be generated by sqlc
+
+type AuditEvent struct {
+	ID          int64           `json:"id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Actor       string          `json:"actor"`
+	Action      string          `json:"action"`
+	FlightID    sql.NullInt64   `json:"flight_id"`
+	SeatNo      sql.NullString  `json:"seat_no"`
+	PnrCode     sql.NullString  `json:"pnr_code"`
+	BeforeState json.RawMessage `json:"before_state"`
+	AfterState  json.RawMessage `json:"after_state"`
+	RequestID   sql.NullString  `json:"request_id"`
+	IpAddress   sql.NullString  `json:"ip_address"`
+}
+
+type CreateAuditEventParams struct {
+	Actor       string
+	Action      string
+	FlightID    sql.NullInt64
+	SeatNo      sql.NullString
+	PnrCode     sql.NullString
+	BeforeState json.RawMessage
+	AfterState  json.RawMessage
+	RequestID   sql.NullString
+	IpAddress   sql.NullString
+}
+
+// ListAuditEventsParams filters on every set field
+type ListAuditEventsParams struct {
+	PnrCode  string
+	Actor    string
+	FlightID int64
+	From     sql.NullTime
+	To       sql.NullTime
+	Limit    int32
+}
+
+func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error {
+	query := `INSERT INTO audit_events (actor, action, flight_id, seat_no, pnr_code, before_state, after_state, request_id, ip_address)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := q.db.ExecContext(ctx, query, arg.Actor, arg.Action, arg.FlightID, arg.SeatNo, arg.PnrCode,
+		nullJSON(arg.BeforeState), nullJSON(arg.AfterState), arg.RequestID, arg.IpAddress)
+	return err
+}
+
+func (q *Queries) ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]AuditEvent, error) {
+	query := `SELECT id, occurred_at, actor, action, flight_id, seat_no, pnr_code, before_state, after_state, request_id, ip_address
+	FROM audit_events
+	WHERE (? = '' OR pnr_code = ?)
+	AND (? = '' OR actor = ?)
+	AND (? = 0 OR flight_id = ?)
+	AND (? IS NULL OR occurred_at >= ?)
+	AND (? IS NULL OR occurred_at < ?)
+	ORDER BY occurred_at DESC, id DESC
+	LIMIT ?`
+
+	rows, err := q.db.QueryContext(ctx, query,
+		arg.PnrCode, arg.PnrCode,
+		arg.Actor, arg.Actor,
+		arg.FlightID, arg.FlightID,
+		arg.From, arg.From,
+		arg.To, arg.To,
+		arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []AuditEvent
+	for rows.Next() {
+		var e AuditEvent
+		var before, after []byte
+		if err := rows.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.Action, &e.FlightID, &e.SeatNo, &e.PnrCode,
+			&before, &after, &e.RequestID, &e.IpAddress); err != nil {
+			return nil, err
+		}
+		e.BeforeState = before
+		e.AfterState = after
+		items = append(items, e)
+	}
+	return items, rows.Err()
+}
+
+// nullJSON stores an empty document as SQL NULL
+func nullJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 {
+		return nil
+	}
+	return []byte(data)
+}
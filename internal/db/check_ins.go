@@ -0,0 +1,183 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/check_ins.sql - these will be generated by sqlc
+
+type CheckIn struct {
+	ID               int64      `json:"id"`
+	TicketID         int64      `json:"ticket_id"`
+	FlightID         int64      `json:"flight_id"`
+	SeatNo           string     `json:"seat_no"`
+	BoardingSequence int32      `json:"boarding_sequence"`
+	FirstName        string     `json:"first_name"`
+	LastName         string     `json:"last_name"`
+	DateOfBirth      time.Time  `json:"date_of_birth"`
+	Nationality      string     `json:"nationality"`
+	DocumentType     string     `json:"document_type"`
+	DocumentNumber   string     `json:"document_number"`
+	DocumentCountry  string     `json:"document_country"`
+	DocumentExpiry   time.Time  `json:"document_expiry"`
+	CheckedInAt      time.Time  `json:"checked_in_at"`
+	BoardedAt        *time.Time `json:"boarded_at"`
+}
+
+type CreateCheckInParams struct {
+	TicketID         int64
+	FlightID         int64
+	SeatNo           string
+	BoardingSequence int32
+	FirstName        string
+	LastName         string
+	DateOfBirth      time.Time
+	Nationality      string
+	DocumentType     string
+	DocumentNumber   string
+	DocumentCountry  string
+	DocumentExpiry   time.Time
+}
+
+type MarkCheckInBoardedParams struct {
+	BoardedAt time.Time
+	TicketID  int64
+}
+
+type GetFreeCabinSeatParams struct {
+	FlightID int64
+	Class    string
+}
+
+type ReclaimSeatLockParams struct {
+	HolderID string
+	FlightID int64
+	SeatNo   string
+}
+
+type UpdateTicketSeatParams struct {
+	SeatNo string
+	ID     int64
+}
+
+func (q *Queries) CreateCheckIn(ctx context.Context, arg CreateCheckInParams) (int64, error) {
+	query := `INSERT INTO check_ins (
+		ticket_id, flight_id, seat_no, boarding_sequence, first_name, last_name, date_of_birth,
+		nationality, document_type, document_number, document_country, document_expiry
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := q.db.ExecContext(ctx, query, arg.TicketID, arg.FlightID, arg.SeatNo, arg.BoardingSequence,
+		arg.FirstName, arg.LastName, arg.DateOfBirth, arg.Nationality, arg.DocumentType, arg.DocumentNumber,
+		arg.DocumentCountry, arg.DocumentExpiry)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (q *Queries) GetCheckInByTicket(ctx context.Context, ticketID int64) (CheckIn, error) {
+	query := `SELECT id, ticket_id, flight_id, seat_no, boarding_sequence, first_name, last_name, date_of_birth,
+		nationality, document_type, document_number, document_country, document_expiry, checked_in_at, boarded_at
+	FROM check_ins WHERE ticket_id = ?`
+
+	var c CheckIn
+	err := q.db.QueryRowContext(ctx, query, ticketID).Scan(&c.ID, &c.TicketID, &c.FlightID, &c.SeatNo,
+		&c.BoardingSequence, &c.FirstName, &c.LastName, &c.DateOfBirth, &c.Nationality, &c.DocumentType,
+		&c.DocumentNumber, &c.DocumentCountry, &c.DocumentExpiry, &c.CheckedInAt, &c.BoardedAt)
+	return c, err
+}
+
+func (q *Queries) ListFlightCheckIns(ctx context.Context, flightID int64) ([]CheckIn, error) {
+	query := `SELECT id, ticket_id, flight_id, seat_no, boarding_sequence, first_name, last_name, date_of_birth,
+		nationality, document_type, document_number, document_country, document_expiry, checked_in_at, boarded_at
+	FROM check_ins WHERE flight_id = ? ORDER BY boarding_sequence`
+
+	rows, err := q.db.QueryContext(ctx, query, flightID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []CheckIn
+	for rows.Next() {
+		var c CheckIn
+		if err := rows.Scan(&c.ID, &c.TicketID, &c.FlightID, &c.SeatNo, &c.BoardingSequence, &c.FirstName,
+			&c.LastName, &c.DateOfBirth, &c.Nationality, &c.DocumentType, &c.DocumentNumber, &c.DocumentCountry,
+			&c.DocumentExpiry, &c.CheckedInAt, &c.BoardedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, c)
+	}
+	return items, rows.Err()
+}
+
+// MarkCheckInBoarded records the boarding of a checked-in ticket; no row is
+// affected if it has already boarded
+func (q *Queries) MarkCheckInBoarded(ctx context.Context, arg MarkCheckInBoardedParams) (int64, error) {
+	query := `UPDATE check_ins SET boarded_at = ? WHERE ticket_id = ? AND boarded_at IS NULL`
+
+	result, err := q.db.ExecContext(ctx, query, arg.BoardedAt, arg.TicketID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// NextBoardingSequence returns the next check-in sequence number of a flight,
+// locking the flight row so concurrent check-ins are numbered one at a time
+func (q *Queries) NextBoardingSequence(ctx context.Context, flightID int64) (int32, error) {
+	query := `SELECT COALESCE(MAX(c.boarding_sequence), 0) + 1
+	FROM flights f
+	LEFT JOIN check_ins c ON c.flight_id = f.id
+	WHERE f.id = ?
+	FOR UPDATE`
+
+	var sequence int32
+	err := q.db.QueryRowContext(ctx, query, flightID).Scan(&sequence)
+	return sequence, err
+}
+
+// GetFreeCabinSeat returns the first seat of a cabin with neither a live lock
+// nor an active ticket
+func (q *Queries) GetFreeCabinSeat(ctx context.Context, arg GetFreeCabinSeatParams) (string, error) {
+	query := `SELECT s.seat_no
+	FROM seats s
+	LEFT JOIN seat_locks l ON l.flight_id = s.flight_id AND l.seat_no = s.seat_no
+		AND l.status IN ('active', 'payment_pending', 'confirmed')
+	LEFT JOIN tickets t ON t.flight_id = s.flight_id AND t.active_seat_no = s.seat_no
+	WHERE s.flight_id = ? AND s.class = ? AND l.seat_no IS NULL AND t.id IS NULL
+	ORDER BY s.id
+	LIMIT 1`
+
+	var seatNo string
+	err := q.db.QueryRowContext(ctx, query, arg.FlightID, arg.Class).Scan(&seatNo)
+	return seatNo, err
+}
+
+func (q *Queries) CreateConfirmedSeatLock(ctx context.Context, arg ConfirmSeatLockParams) error {
+	query := `INSERT INTO seat_locks (flight_id, seat_no, holder_id, status, expires_at)
+	VALUES (?, ?, ?, 'confirmed', NULL)`
+	_, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID)
+	return err
+}
+
+// ReclaimSeatLock confirms a seat whose last hold expired or was released
+func (q *Queries) ReclaimSeatLock(ctx context.Context, arg ReclaimSeatLockParams) (int64, error) {
+	query := `UPDATE seat_locks
+	SET holder_id = ?, status = 'confirmed', expires_at = NULL, hold_expires_at = NULL, payment_pending_at = NULL,
+	    created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE flight_id = ? AND seat_no = ? AND status IN ('expired', 'released')`
+
+	result, err := q.db.ExecContext(ctx, query, arg.HolderID, arg.FlightID, arg.SeatNo)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (q *Queries) UpdateTicketSeat(ctx context.Context, arg UpdateTicketSeatParams) error {
+	query := `UPDATE tickets SET seat_no = ? WHERE id = ?`
+	_, err := q.db.ExecContext(ctx, query, arg.SeatNo, arg.ID)
+	return err
+}
@@ -1,19 +1,24 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/mysql"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"go.uber.org/zap"
 
 	"airline-booking/internal/config"
 )
 
+// mysqlNoSuchTable is MySQL's ER_NO_SUCH_TABLE error number
+const mysqlNoSuchTable = 1146
+
 type Database struct {
 	DB      *sql.DB
 	Queries *Queries
@@ -52,27 +57,49 @@ func (d *Database) Close() error {
 	return nil
 }
 
-func (d *Database) RunMigrations(migrationsPath string) error {
-	driver, err := mysql.WithInstance(d.DB, &mysql.Config{})
+// MigrationVersion returns the schema version recorded by golang-migrate
+// and whether a migration failed part way. A database that was never
+// migrated is at version 0.
+func (d *Database) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	var (
+		version uint
+		dirty   bool
+	)
+	err := d.DB.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlNoSuchTable {
+		return 0, false, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
+		return 0, false, fmt.Errorf("failed to read migration version: %w", err)
 	}
+	return version, dirty, nil
+}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"mysql",
-		driver,
-	)
+// LatestMigration returns the highest version among the migrations in dir
+func LatestMigration(dir string) (uint, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
 	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return 0, err
 	}
-
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	var latest uint
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("bad migration file name %s", file)
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
 	}
-
-	d.logger.Info("Database migrations completed successfully")
-	return nil
+	if latest == 0 {
+		return 0, fmt.Errorf("no migrations found in %s", dir)
+	}
+	return latest, nil
 }
 
 func (d *Database) BeginTx() (*sql.Tx, error) {
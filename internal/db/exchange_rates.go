@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/exchange_rates.sql - these will be generated by sqlc
+
+type ExchangeRate struct {
+	ID            int64     `json:"id"`
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Source        string    `json:"source"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type UpsertExchangeRateParams struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          string
+	EffectiveFrom time.Time
+	Source        string
+}
+
+type GetEffectiveExchangeRateParams struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	At            time.Time
+}
+
+const exchangeRateColumns = `id, base_currency, quote_currency, rate, effective_from, source, created_at`
+
+// UpsertExchangeRate stores a rate, replacing one already scheduled for the
+// same pair and effective time
+func (q *Queries) UpsertExchangeRate(ctx context.Context, arg UpsertExchangeRateParams) error {
+	query := `INSERT INTO exchange_rates (base_currency, quote_currency, rate, effective_from, source)
+	VALUES (?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE rate = VALUES(rate), source = VALUES(source)`
+
+	_, err := q.db.ExecContext(ctx, query,
+		arg.BaseCurrency, arg.QuoteCurrency, arg.Rate, arg.EffectiveFrom, arg.Source)
+	return err
+}
+
+// GetEffectiveExchangeRate returns the latest rate for the pair that took
+// effect at or before At
+func (q *Queries) GetEffectiveExchangeRate(ctx context.Context, arg GetEffectiveExchangeRateParams) (ExchangeRate, error) {
+	query := `SELECT ` + exchangeRateColumns + `
+	FROM exchange_rates
+	WHERE base_currency = ? AND quote_currency = ? AND effective_from <= ?
+	ORDER BY effective_from DESC
+	LIMIT 1`
+
+	row := q.db.QueryRowContext(ctx, query, arg.BaseCurrency, arg.QuoteCurrency, arg.At)
+	return scanExchangeRate(row)
+}
+
+func (q *Queries) ListExchangeRates(ctx context.Context, baseCurrency string) ([]ExchangeRate, error) {
+	query := `SELECT ` + exchangeRateColumns + `
+	FROM exchange_rates
+	WHERE base_currency = ?
+	ORDER BY quote_currency, effective_from DESC`
+
+	rows, err := q.db.QueryContext(ctx, query, baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []ExchangeRate
+	for rows.Next() {
+		rate, err := scanExchangeRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, rate)
+	}
+	return items, rows.Err()
+}
+
+func scanExchangeRate(row interface{ Scan(...any) error }) (ExchangeRate, error) {
+	var r ExchangeRate
+	err := row.Scan(&r.ID, &r.BaseCurrency, &r.QuoteCurrency, &r.Rate, &r.EffectiveFrom, &r.Source, &r.CreatedAt)
+	return r, err
+}
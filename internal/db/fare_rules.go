@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/fare_rules.sql - these will be generated by sqlc
+
+type FareRule struct {
+	ID                 int64          `json:"id"`
+	Code               string         `json:"code"`
+	Component          string         `json:"component"`
+	Description        string         `json:"description"`
+	OriginCountry      sql.NullString `json:"origin_country"`
+	DestinationCountry sql.NullString `json:"destination_country"`
+	CabinClass         sql.NullString `json:"cabin_class"`
+	FixedAmount        int64          `json:"fixed_amount"`
+	PercentBps         int32          `json:"percent_bps"`
+	Active             bool           `json:"active"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+}
+
+// ListActiveFareRules returns the active rules that can match a route: those
+// for either country or for any country
+func (q *Queries) ListActiveFareRules(ctx context.Context, originCountry, destinationCountry string) ([]FareRule, error) {
+	query := `SELECT id, code, component, description, origin_country, destination_country, cabin_class,
+	       fixed_amount, percent_bps, active, created_at, updated_at
+	FROM fare_rules
+	WHERE active = TRUE
+	  AND (origin_country IS NULL OR origin_country = ?)
+	  AND (destination_country IS NULL OR destination_country = ?)
+	ORDER BY id`
+
+	rows, err := q.db.QueryContext(ctx, query, originCountry, destinationCountry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []FareRule
+	for rows.Next() {
+		var r FareRule
+		if err := rows.Scan(&r.ID, &r.Code, &r.Component, &r.Description, &r.OriginCountry, &r.DestinationCountry,
+			&r.CabinClass, &r.FixedAmount, &r.PercentBps, &r.Active, &r.CreatedAt, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, r)
+	}
+	return items, rows.Err()
+}
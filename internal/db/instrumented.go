@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"airline-booking/internal/metrics"
+	"airline-booking/internal/tracing"
+)
+
+// instrumentedDBTX times and traces every query run through Queries,
+// labelled with the name of the Queries method that ran it
+type instrumentedDBTX struct {
+	db DBTX
+}
+
+func instrument(db DBTX) DBTX {
+	if _, ok := db.(instrumentedDBTX); ok {
+		return db
+	}
+	return instrumentedDBTX{db: db}
+}
+
+func (i instrumentedDBTX) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, call := startQuery(ctx, queryName(), query)
+	result, err := i.db.ExecContext(ctx, query, args...)
+	call.end(err)
+	return result, err
+}
+
+func (i instrumentedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return i.db.PrepareContext(ctx, query)
+}
+
+func (i instrumentedDBTX) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, call := startQuery(ctx, queryName(), query)
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	call.end(err)
+	return rows, err
+}
+
+func (i instrumentedDBTX) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, call := startQuery(ctx, queryName(), query)
+	row := i.db.QueryRowContext(ctx, query, args...)
+	call.end(row.Err())
+	return row
+}
+
+// queryCall is a query in flight
+type queryCall struct {
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+// startQuery starts the span of a query as a child of the span in ctx
+func startQuery(ctx context.Context, name, query string) (context.Context, queryCall) {
+	ctx, span := tracing.Start(ctx, "mysql "+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "mysql"),
+			attribute.String("db.operation", name),
+			attribute.String("db.statement", query),
+		))
+	return ctx, queryCall{name: name, start: time.Now(), span: span}
+}
+
+// end records the query's latency and ends its span. sql.ErrNoRows is a
+// normal outcome, not a failed query.
+func (c queryCall) end(err error) {
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	metrics.DBQueryDuration.WithLabelValues(c.name, metrics.Result(err)).Observe(metrics.Since(c.start))
+	tracing.End(c.span, err)
+}
+
+// queryName returns the name of the Queries method that called into
+// instrumentedDBTX, e.g. "GetFlight"
+func queryName() string {
+	// Skip runtime.Callers, queryName and the DBTX method
+	pc := make([]uintptr, 1)
+	if runtime.Callers(3, pc) == 0 {
+		return "unknown"
+	}
+	frame, _ := runtime.CallersFrames(pc).Next()
+	name := frame.Function
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// failingDBTX fails every statement without a database
+type failingDBTX struct {
+	DBTX
+}
+
+func (failingDBTX) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, sql.ErrConnDone
+}
+
+func TestQueryLatencyIsLabelledWithQueryName(t *testing.T) {
+	before := querySamples(t, "DeleteHoldPromotions", "error")
+
+	q := New(failingDBTX{})
+	err := q.DeleteHoldPromotions(context.Background(), GetSeatLockParams{FlightID: 1, SeatNo: "1A"})
+	require.Error(t, err)
+
+	assert.Equal(t, before+1, querySamples(t, "DeleteHoldPromotions", "error"))
+}
+
+// querySamples returns how many latencies were observed for a query and
+// result
+func querySamples(t *testing.T, query, result string) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "airline_mysql_query_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["query"] == query && labels["result"] == result {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestQueriesAreTracedAsChildSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	err := New(failingDBTX{}).DeleteHoldPromotions(ctx, GetSeatLockParams{FlightID: 1, SeatNo: "1A"})
+	parent.End()
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query := spans[0]
+	assert.Equal(t, "mysql DeleteHoldPromotions", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, codes.Error, query.Status().Code)
+}
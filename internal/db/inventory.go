@@ -0,0 +1,217 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/inventory.sql - these will be generated by sqlc
+
+type IncrementInventoryCapacityParams struct {
+	FlightID   int64
+	CabinClass string
+	Capacity   int32
+}
+
+type CabinInventoryParams struct {
+	FlightID   int64
+	CabinClass string
+}
+
+type SetOverbookingLimitParams struct {
+	OverbookingLimit int32
+	FlightID         int64
+	CabinClass       string
+}
+
+type ListFlightsAtRiskRow struct {
+	FlightInventory
+	Origin        string
+	Destination   string
+	Airline       string
+	DepartureTime time.Time
+}
+
+type AdjustSeatInventoryParams struct {
+	HeldDelta int32
+	SoldDelta int32
+	FlightID  int64
+	SeatNo    string
+}
+
+const flightInventoryColumns = `flight_id, cabin_class, capacity, held, sold, blocked, overbooking_limit, oversold, created_at, updated_at`
+
+func (q *Queries) IncrementInventoryCapacity(ctx context.Context, arg IncrementInventoryCapacityParams) error {
+	query := `INSERT INTO flight_inventory (flight_id, cabin_class, capacity)
+	VALUES (?, ?, ?)
+	ON DUPLICATE KEY UPDATE capacity = capacity + VALUES(capacity)`
+
+	_, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.CabinClass, arg.Capacity)
+	return err
+}
+
+// AdjustSeatInventory applies held/sold deltas to the cabin the seat belongs to
+func (q *Queries) AdjustSeatInventory(ctx context.Context, arg AdjustSeatInventoryParams) (int64, error) {
+	query := `UPDATE flight_inventory fi
+	JOIN seats s ON s.flight_id = fi.flight_id AND s.class = fi.cabin_class
+	SET fi.held = fi.held + ?, fi.sold = fi.sold + ?
+	WHERE s.flight_id = ? AND s.seat_no = ?`
+
+	result, err := q.db.ExecContext(ctx, query, arg.HeldDelta, arg.SoldDelta, arg.FlightID, arg.SeatNo)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ReleaseExpiredInventory decrements held for every active or
+// payment_pending lock expiring before cutoff. It must run before those locks
+// are moved to a terminal state.
+func (q *Queries) ReleaseExpiredInventory(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `UPDATE flight_inventory fi
+	JOIN (
+		SELECT s.flight_id, s.class, COUNT(*) AS expired
+		FROM seat_locks l
+		JOIN seats s ON s.flight_id = l.flight_id AND s.seat_no = l.seat_no
+		WHERE l.status IN ('active', 'payment_pending') AND l.expires_at < ?
+		GROUP BY s.flight_id, s.class
+	) x ON x.flight_id = fi.flight_id AND x.class = fi.cabin_class
+	SET fi.held = fi.held - x.expired`
+
+	result, err := q.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (q *Queries) SetOverbookingLimit(ctx context.Context, arg SetOverbookingLimitParams) (int64, error) {
+	query := `UPDATE flight_inventory SET overbooking_limit = ?
+	WHERE flight_id = ? AND cabin_class = ?`
+
+	result, err := q.db.ExecContext(ctx, query, arg.OverbookingLimit, arg.FlightID, arg.CabinClass)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ReserveOversoldSeat counts one more seatless ticket in a cabin, only while
+// holds and sales stay within capacity plus the overbooking limit
+func (q *Queries) ReserveOversoldSeat(ctx context.Context, arg CabinInventoryParams) (int64, error) {
+	query := `UPDATE flight_inventory SET oversold = oversold + 1
+	WHERE flight_id = ? AND cabin_class = ?
+	AND held + sold + blocked + oversold < capacity + overbooking_limit`
+
+	result, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.CabinClass)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (q *Queries) ReleaseOversoldSeat(ctx context.Context, arg CabinInventoryParams) (int64, error) {
+	query := `UPDATE flight_inventory SET oversold = oversold - 1
+	WHERE flight_id = ? AND cabin_class = ? AND oversold > 0`
+
+	result, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.CabinClass)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// ListFlightsAtRisk returns cabins departing after since whose tickets and
+// active holds exceed the seats that can be boarded
+func (q *Queries) ListFlightsAtRisk(ctx context.Context, since time.Time) ([]ListFlightsAtRiskRow, error) {
+	query := `SELECT fi.flight_id, fi.cabin_class, fi.capacity, fi.held, fi.sold, fi.blocked,
+	fi.overbooking_limit, fi.oversold, fi.created_at, fi.updated_at,
+	f.origin, f.destination, f.airline, f.departure_time
+	FROM flight_inventory fi
+	JOIN flights f ON f.id = fi.flight_id
+	WHERE f.departure_time >= ?
+	AND fi.oversold > 0
+	AND fi.held + fi.sold + fi.blocked + fi.oversold > fi.capacity
+	ORDER BY f.departure_time, fi.flight_id, fi.cabin_class`
+
+	rows, err := q.db.QueryContext(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []ListFlightsAtRiskRow{}
+	for rows.Next() {
+		var i ListFlightsAtRiskRow
+		if err := rows.Scan(&i.FlightID, &i.CabinClass, &i.Capacity, &i.Held, &i.Sold, &i.Blocked,
+			&i.OverbookingLimit, &i.Oversold, &i.CreatedAt, &i.UpdatedAt,
+			&i.Origin, &i.Destination, &i.Airline, &i.DepartureTime); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+func (q *Queries) GetFlightInventory(ctx context.Context, flightID int64) ([]FlightInventory, error) {
+	query := `SELECT ` + flightInventoryColumns + `
+	FROM flight_inventory WHERE flight_id = ? ORDER BY cabin_class`
+
+	rows, err := q.db.QueryContext(ctx, query, flightID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanFlightInventory(rows)
+}
+
+const listFlightInventory = `SELECT ` + flightInventoryColumns + `
+FROM flight_inventory
+WHERE flight_id IN (/*SLICE:flight_ids*/?)
+ORDER BY flight_id, cabin_class`
+
+// ListFlightInventory returns the cabin counters of all given flights in a
+// single round trip
+func (q *Queries) ListFlightInventory(ctx context.Context, flightIDs []int64) ([]FlightInventory, error) {
+	if len(flightIDs) == 0 {
+		return []FlightInventory{}, nil
+	}
+
+	args := make([]interface{}, len(flightIDs))
+	for i, id := range flightIDs {
+		args[i] = id
+	}
+	placeholders := strings.Repeat(",?", len(flightIDs))[1:]
+	query := strings.Replace(listFlightInventory, "/*SLICE:flight_ids*/?", placeholders, 1)
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanFlightInventory(rows)
+}
+
+func scanFlightInventory(rows *sql.Rows) ([]FlightInventory, error) {
+	items := []FlightInventory{}
+	for rows.Next() {
+		var i FlightInventory
+		if err := rows.Scan(&i.FlightID, &i.CabinClass, &i.Capacity, &i.Held, &i.Sold, &i.Blocked,
+			&i.OverbookingLimit, &i.Oversold, &i.CreatedAt, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,179 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+)
+
+// migrationLock names the MySQL advisory lock held while migrating, so
+// pods starting together migrate one at a time
+const migrationLock = "airline_booking_migrations"
+
+// ErrMigrationLockTimeout is returned when another process kept the
+// migration lock for the whole timeout
+var ErrMigrationLockTimeout = errors.New("timed out waiting for the migration lock")
+
+// Migrator applies the migrations in a directory over its own connection,
+// opened with multiStatements since migration files hold several
+// statements. The advisory lock and the migrations share that connection,
+// as MySQL locks belong to the session that took them.
+type Migrator struct {
+	db      *sql.DB
+	conn    *sql.Conn
+	migrate *migrate.Migrate
+	logger  *zap.Logger
+}
+
+func NewMigrator(ctx context.Context, cfg *config.DatabaseConfig, migrationsPath string, logger *zap.Logger) (*Migrator, error) {
+	db, err := sql.Open("mysql", cfg.DSN()+"&multiStatements=true")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	driver, err := mysql.WithConnection(ctx, conn, &mysql.Config{})
+	if err != nil {
+		conn.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+migrationsPath, "mysql", driver)
+	if err != nil {
+		driver.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+	m.Log = migrateLogger{logger: logger}
+
+	return &Migrator{
+		db:      db,
+		conn:    conn,
+		migrate: m,
+		logger:  logger,
+	}, nil
+}
+
+// Close releases the connection, and with it the lock if still held
+func (m *Migrator) Close() error {
+	sourceErr, driverErr := m.migrate.Close()
+	dbErr := m.db.Close()
+	return errors.Join(sourceErr, driverErr, dbErr)
+}
+
+// Lock waits up to timeout for the migration lock
+func (m *Migrator) Lock(ctx context.Context, timeout time.Duration) error {
+	var acquired sql.NullInt64
+	err := m.conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLock, int(timeout.Seconds())).Scan(&acquired)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return ErrMigrationLockTimeout
+	}
+	return nil
+}
+
+// Unlock releases the migration lock
+func (m *Migrator) Unlock(ctx context.Context) error {
+	if _, err := m.conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLock); err != nil {
+		return fmt.Errorf("failed to release migration lock: %w", err)
+	}
+	return nil
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.migrate.Up())
+}
+
+// Down rolls back the last n migrations
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("down needs a positive number of migrations, got %d", n)
+	}
+	return ignoreNoChange(m.migrate.Steps(-n))
+}
+
+// Goto migrates up or down to version
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+// Force records version as applied and clears the dirty flag, without
+// running anything, after a failed migration was fixed by hand
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// Version returns the applied version and whether it is dirty; 0 when no
+// migration was applied
+func (m *Migrator) Version() (uint, bool, error) {
+	version, dirty, err := m.migrate.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// RunMigrations applies the pending migrations in migrationsPath, waiting
+// up to lockTimeout for other processes migrating the same database
+func RunMigrations(ctx context.Context, cfg *config.DatabaseConfig, migrationsPath string, lockTimeout time.Duration, logger *zap.Logger) error {
+	m, err := NewMigrator(ctx, cfg, migrationsPath, logger)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Lock(ctx, lockTimeout); err != nil {
+		return err
+	}
+	defer m.Unlock(context.Background())
+
+	if err := m.Up(); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	version, _, err := m.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	logger.Info("Database migrations completed successfully", zap.Uint("version", version))
+	return nil
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
+
+// migrateLogger logs migrate's progress through zap
+type migrateLogger struct {
+	logger *zap.Logger
+}
+
+func (l migrateLogger) Printf(format string, v ...interface{}) {
+	l.logger.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l migrateLogger) Verbose() bool {
+	return false
+}
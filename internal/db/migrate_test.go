@@ -0,0 +1,45 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// migrations/ is the only source of the schema, so every version must be
+// present once, in sequence, and reversible
+func TestMigrationsAreSequentialAndReversible(t *testing.T) {
+	dir := filepath.Join("..", "..", "migrations")
+	latest, err := LatestMigration(dir)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	ups := map[uint]string{}
+	downs := map[uint]string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		require.NoError(t, err, name)
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			assert.NotContains(t, ups, uint(version), "duplicate up migration %s", name)
+			ups[uint(version)] = strings.TrimSuffix(name, ".up.sql")
+		case strings.HasSuffix(name, ".down.sql"):
+			downs[uint(version)] = strings.TrimSuffix(name, ".down.sql")
+		default:
+			t.Errorf("unexpected file in migrations: %s", name)
+		}
+	}
+
+	for version := uint(1); version <= latest; version++ {
+		assert.Contains(t, ups, version, "missing up migration %06d", version)
+		assert.Equal(t, ups[version], downs[version], "down migration of %06d", version)
+	}
+}
@@ -21,7 +21,7 @@ type DBTX interface {
 }
 
 func New(db DBTX) *Queries {
-	return &Queries{db: db}
+	return &Queries{db: instrument(db)}
 }
 
 type Queries struct {
@@ -30,7 +30,7 @@ type Queries struct {
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db: tx,
+		db: instrument(tx),
 	}
 }
 
@@ -41,13 +41,29 @@ type Flight struct {
 	Destination   string    `json:"destination"`
 	DepartureTime time.Time `json:"departure_time"`
 	ArrivalTime   time.Time `json:"arrival_time"`
+	DepartureTimeLocal *time.Time `json:"departure_time_local"`
+	ArrivalTimeLocal   *time.Time `json:"arrival_time_local"`
 	Airline       string    `json:"airline"`
 	Aircraft      string    `json:"aircraft"`
 	FareClass     string    `json:"fare_class"`
+	BasePrice     int64     `json:"base_price"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+type Airport struct {
+	IataCode   string    `json:"iata_code"`
+	Name       string    `json:"name"`
+	City       string    `json:"city"`
+	Country    string    `json:"country"`
+	Timezone   string    `json:"timezone"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	Popularity int32     `json:"popularity"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
 type Seat struct {
 	ID        int64     `json:"id"`
 	FlightID  int64     `json:"flight_id"`
@@ -62,22 +78,45 @@ type SeatLock struct {
 	FlightID  int64      `json:"flight_id"`
 	SeatNo    string     `json:"seat_no"`
 	HolderID  string     `json:"holder_id"`
+	Status    string     `json:"status"`
 	ExpiresAt *time.Time `json:"expires_at"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 type Ticket struct {
-	ID          int64     `json:"id"`
-	FlightID    int64     `json:"flight_id"`
-	SeatNo      string    `json:"seat_no"`
-	UserID      string    `json:"user_id"`
-	PriceAmount int64     `json:"price_amount"`
-	Currency    string    `json:"currency"`
-	IssuedAt    time.Time `json:"issued_at"`
-	PnrCode     string    `json:"pnr_code"`
-	PaymentRef  string    `json:"payment_ref"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                        int64          `json:"id"`
+	FlightID                  int64          `json:"flight_id"`
+	SeatNo                    sql.NullString `json:"seat_no"`
+	CabinClass                sql.NullString `json:"cabin_class"`
+	UserID                    string         `json:"user_id"`
+	PriceAmount               int64          `json:"price_amount"`
+	Currency                  string         `json:"currency"`
+	BasePriceAmount           int64          `json:"base_price_amount"`
+	BaseCurrency              string         `json:"base_currency"`
+	ExchangeRate              string         `json:"exchange_rate"`
+	ExchangeRateEffectiveFrom sql.NullTime   `json:"exchange_rate_effective_from"`
+	IssuedAt                  time.Time      `json:"issued_at"`
+	PnrCode                   string         `json:"pnr_code"`
+	PaymentRef                string         `json:"payment_ref"`
+	PaymentAuthorizationID    sql.NullString `json:"payment_authorization_id"`
+	PaymentStatus             string         `json:"payment_status"`
+	Status                    string         `json:"status"`
+	CancelledAt               *time.Time     `json:"cancelled_at"`
+	CreatedAt                 time.Time      `json:"created_at"`
+}
+
+type FlightInventory struct {
+	FlightID         int64     `json:"flight_id"`
+	CabinClass       string    `json:"cabin_class"`
+	Capacity         int32     `json:"capacity"`
+	Held             int32     `json:"held"`
+	Sold             int32     `json:"sold"`
+	Blocked          int32     `json:"blocked"`
+	OverbookingLimit int32     `json:"overbooking_limit"`
+	Oversold         int32     `json:"oversold"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 type IdempotencyKey struct {
@@ -98,9 +137,23 @@ type CreateFlightParams struct {
 	Destination   string
 	DepartureTime time.Time
 	ArrivalTime   time.Time
+	DepartureTimeLocal *time.Time
+	ArrivalTimeLocal   *time.Time
 	Airline       string
 	Aircraft      string
 	FareClass     string
+	BasePrice     int64
+}
+
+type SearchFlightsParams struct {
+	Origin        string
+	Destination   string
+	DepartureFrom time.Time
+	DepartureTo   time.Time
+	FareClass     string
+	Airline       string
+	Limit         int32
+	Offset        int32
 }
 
 type CreateSeatParams struct {
@@ -109,6 +162,11 @@ type CreateSeatParams struct {
 	Class    string
 }
 
+type GetSeatParams struct {
+	FlightID int64
+	SeatNo   string
+}
+
 type CreateSeatLockParams struct {
 	FlightID  int64
 	SeatNo    string
@@ -124,6 +182,20 @@ type UpdateSeatLockParams struct {
 	HolderID_2 string
 }
 
+type ReactivateSeatLockParams struct {
+	HolderID  string
+	ExpiresAt *time.Time
+	FlightID  int64
+	SeatNo    string
+}
+
+type MarkSeatLockPaymentPendingParams struct {
+	ExpiresAt *time.Time
+	FlightID  int64
+	SeatNo    string
+	HolderID  string
+}
+
 type ConfirmSeatLockParams struct {
 	FlightID int64
 	SeatNo   string
@@ -142,13 +214,31 @@ type GetSeatLockParams struct {
 }
 
 type CreateTicketParams struct {
-	FlightID    int64
-	SeatNo      string
-	UserID      string
-	PriceAmount int64
-	Currency    string
-	PnrCode     string
-	PaymentRef  string
+	FlightID                  int64
+	SeatNo                    sql.NullString
+	CabinClass                sql.NullString
+	UserID                    string
+	PriceAmount               int64
+	Currency                  string
+	BasePriceAmount           int64
+	BaseCurrency              string
+	ExchangeRate              string
+	ExchangeRateEffectiveFrom sql.NullTime
+	PnrCode                   string
+	PaymentRef                string
+	PaymentAuthorizationID    sql.NullString
+	PaymentStatus             string
+}
+
+type UpdateTicketPaymentStatusParams struct {
+	PaymentStatus string
+	ID            int64
+}
+
+type UpdateTicketPaymentStateParams struct {
+	PaymentStatus string
+	Status        string
+	ID            int64
 }
 
 type GetTicketByPNRParams struct {
@@ -174,21 +264,23 @@ type GetIdempotencyKeyParams struct {
 
 // Placeholder method implementations - these will be generated by sqlc
 func (q *Queries) GetFlight(ctx context.Context, id int64) (Flight, error) {
-	query := `SELECT id, origin, destination, departure_time, arrival_time, airline, aircraft, fare_class, created_at, updated_at
+	query := `SELECT id, origin, destination, departure_time, arrival_time, departure_time_local, arrival_time_local,
+	airline, aircraft, fare_class, base_price, created_at, updated_at
 	FROM flights WHERE id = ?`
 	
 	var f Flight
 	err := q.db.QueryRowContext(ctx, query, id).Scan(
 		&f.ID, &f.Origin, &f.Destination, &f.DepartureTime, &f.ArrivalTime,
-		&f.Airline, &f.Aircraft, &f.FareClass, &f.CreatedAt, &f.UpdatedAt,
+		&f.DepartureTimeLocal, &f.ArrivalTimeLocal, &f.Airline, &f.Aircraft, &f.FareClass, &f.BasePrice, &f.CreatedAt, &f.UpdatedAt,
 	)
 	
 	return f, err
 }
 
 func (q *Queries) CreateFlight(ctx context.Context, arg CreateFlightParams) (int64, error) {
-	query := `INSERT INTO flights (origin, destination, departure_time, arrival_time, airline, aircraft, fare_class)
-	VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO flights (origin, destination, departure_time, arrival_time, departure_time_local, arrival_time_local,
+	airline, aircraft, fare_class, base_price)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	// Log dos parâmetros para debug
 	log.Printf("DEBUG CreateFlight - Origin: %s, Destination: %s, Airline: %s", 
@@ -198,7 +290,8 @@ func (q *Queries) CreateFlight(ctx context.Context, arg CreateFlightParams) (int
 	
 	result, err := q.db.ExecContext(ctx, query, 
 		arg.Origin, arg.Destination, arg.DepartureTime, arg.ArrivalTime,
-		arg.Airline, arg.Aircraft, arg.FareClass)
+		arg.DepartureTimeLocal, arg.ArrivalTimeLocal,
+		arg.Airline, arg.Aircraft, arg.FareClass, arg.BasePrice)
 	if err != nil {
 		log.Printf("DEBUG CreateFlight - Error executing query: %v", err)
 		return 0, fmt.Errorf("failed to execute insert: %w", err)
@@ -214,6 +307,57 @@ func (q *Queries) CreateFlight(ctx context.Context, arg CreateFlightParams) (int
 	return id, nil
 }
 
+// searchFlightsWhere filters on the idx_flights_route_date prefix
+// (origin, destination, departure_time) before the optional filters
+const searchFlightsWhere = `WHERE origin = ? AND destination = ?
+	AND departure_time >= ? AND departure_time < ?
+	AND (? = '' OR fare_class = ?)
+	AND (? = '' OR airline = ?)`
+
+func searchFlightsArgs(arg SearchFlightsParams) []interface{} {
+	return []interface{}{
+		arg.Origin, arg.Destination, arg.DepartureFrom, arg.DepartureTo,
+		arg.FareClass, arg.FareClass, arg.Airline, arg.Airline,
+	}
+}
+
+// SearchFlights is the MySQL fallback for the Elasticsearch flight search
+func (q *Queries) SearchFlights(ctx context.Context, arg SearchFlightsParams) ([]Flight, error) {
+	query := `SELECT id, origin, destination, departure_time, arrival_time, departure_time_local, arrival_time_local,
+	airline, aircraft, fare_class, base_price, created_at, updated_at
+	FROM flights ` + searchFlightsWhere + `
+	ORDER BY departure_time, id
+	LIMIT ? OFFSET ?`
+
+	rows, err := q.db.QueryContext(ctx, query, append(searchFlightsArgs(arg), arg.Limit, arg.Offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flights := []Flight{}
+	for rows.Next() {
+		var f Flight
+		if err := rows.Scan(
+			&f.ID, &f.Origin, &f.Destination, &f.DepartureTime, &f.ArrivalTime,
+			&f.DepartureTimeLocal, &f.ArrivalTimeLocal, &f.Airline, &f.Aircraft, &f.FareClass, &f.BasePrice, &f.CreatedAt, &f.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		flights = append(flights, f)
+	}
+
+	return flights, rows.Err()
+}
+
+func (q *Queries) CountSearchFlights(ctx context.Context, arg SearchFlightsParams) (int64, error) {
+	query := `SELECT COUNT(*) FROM flights ` + searchFlightsWhere
+
+	var count int64
+	err := q.db.QueryRowContext(ctx, query, searchFlightsArgs(arg)...).Scan(&count)
+	return count, err
+}
+
 func (q *Queries) CreateSeat(ctx context.Context, arg CreateSeatParams) (int64, error) {
 	query := `INSERT INTO seats (flight_id, seat_no, class) VALUES (?, ?, ?)`
 	
@@ -225,6 +369,17 @@ func (q *Queries) CreateSeat(ctx context.Context, arg CreateSeatParams) (int64,
 	return result.LastInsertId()
 }
 
+func (q *Queries) GetSeat(ctx context.Context, arg GetSeatParams) (Seat, error) {
+	query := `SELECT id, flight_id, seat_no, class, created_at, updated_at 
+	FROM seats WHERE flight_id = ? AND seat_no = ?`
+	
+	var s Seat
+	err := q.db.QueryRowContext(ctx, query, arg.FlightID, arg.SeatNo).Scan(
+		&s.ID, &s.FlightID, &s.SeatNo, &s.Class, &s.CreatedAt, &s.UpdatedAt)
+	
+	return s, err
+}
+
 func (q *Queries) ListSeats(ctx context.Context, flightID int64) ([]Seat, error) {
 	query := `SELECT id, flight_id, seat_no, class, created_at, updated_at 
 	FROM seats WHERE flight_id = ? ORDER BY seat_no`
@@ -249,16 +404,53 @@ func (q *Queries) ListSeats(ctx context.Context, flightID int64) ([]Seat, error)
 }
 
 func (q *Queries) CreateSeatLock(ctx context.Context, arg CreateSeatLockParams) error {
-	query := `INSERT INTO seat_locks (flight_id, seat_no, holder_id, expires_at) 
-	VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO seat_locks (flight_id, seat_no, holder_id, status, expires_at) 
+	VALUES (?, ?, ?, 'active', ?)`
 	
 	_, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID, arg.ExpiresAt)
 	return err
 }
 
+// ReactivateSeatLock starts a new hold on a seat whose last lock ended
+// (expired or released)
+func (q *Queries) ReactivateSeatLock(ctx context.Context, arg ReactivateSeatLockParams) (int64, error) {
+	query := `UPDATE seat_locks 
+	SET holder_id = ?, status = 'active', expires_at = ?, hold_expires_at = NULL, payment_pending_at = NULL,
+	    created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE flight_id = ? AND seat_no = ? AND status IN ('expired', 'released')`
+	
+	result, err := q.db.ExecContext(ctx, query, arg.HolderID, arg.ExpiresAt, arg.FlightID, arg.SeatNo)
+	if err != nil {
+		return 0, err
+	}
+	
+	return result.RowsAffected()
+}
+
 func (q *Queries) UpdateSeatLock(ctx context.Context, arg UpdateSeatLockParams) (int64, error) {
-	query := `UPDATE seat_locks SET expires_at = ? 
-	WHERE flight_id = ? AND seat_no = ? AND holder_id = ? AND expires_at > NOW()`
+	// Takes over an active hold the cleanup job has not expired yet, or
+	// extends the caller's own. Holds waiting on a payment are never touched.
+	query := `UPDATE seat_locks SET holder_id = ?, expires_at = ?, updated_at = CURRENT_TIMESTAMP
+	WHERE flight_id = ? AND seat_no = ? AND status = 'active'
+	AND (expires_at < NOW() OR holder_id = ?)`
+	
+	result, err := q.db.ExecContext(ctx, query, arg.HolderID, arg.ExpiresAt, arg.FlightID, arg.SeatNo, arg.HolderID_2)
+	if err != nil {
+		return 0, err
+	}
+	
+	return result.RowsAffected()
+}
+
+// MarkSeatLockPaymentPending extends a live hold to ExpiresAt while the
+// payment is processed. Re-entering payment_pending, e.g. on a retry after a
+// 3-D Secure challenge, keeps the original hold expiry.
+func (q *Queries) MarkSeatLockPaymentPending(ctx context.Context, arg MarkSeatLockPaymentPendingParams) (int64, error) {
+	query := `UPDATE seat_locks 
+	SET hold_expires_at = IF(status = 'active', expires_at, hold_expires_at),
+	    status = 'payment_pending', expires_at = ?, payment_pending_at = NOW(), updated_at = CURRENT_TIMESTAMP
+	WHERE flight_id = ? AND seat_no = ? AND holder_id = ?
+	AND status IN ('active', 'payment_pending') AND expires_at > NOW()`
 	
 	result, err := q.db.ExecContext(ctx, query, arg.ExpiresAt, arg.FlightID, arg.SeatNo, arg.HolderID)
 	if err != nil {
@@ -268,10 +460,27 @@ func (q *Queries) UpdateSeatLock(ctx context.Context, arg UpdateSeatLockParams)
 	return result.RowsAffected()
 }
 
+// RevertSeatLockToActive returns a payment_pending hold to active with its
+// original expiry, after a payment that failed without being charged
+func (q *Queries) RevertSeatLockToActive(ctx context.Context, arg ConfirmSeatLockParams) (int64, error) {
+	query := `UPDATE seat_locks 
+	SET status = 'active', expires_at = COALESCE(hold_expires_at, expires_at), hold_expires_at = NULL,
+	    payment_pending_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE flight_id = ? AND seat_no = ? AND holder_id = ? AND status = 'payment_pending'`
+	
+	result, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID)
+	if err != nil {
+		return 0, err
+	}
+	
+	return result.RowsAffected()
+}
+
 func (q *Queries) ConfirmSeatLock(ctx context.Context, arg ConfirmSeatLockParams) (int64, error) {
 	query := `UPDATE seat_locks 
-	          SET expires_at = '2038-01-01 00:00:00', updated_at = CURRENT_TIMESTAMP 
-	          WHERE flight_id = ? AND seat_no = ? AND holder_id = ? AND expires_at > NOW()`
+	          SET status = 'confirmed', expires_at = NULL, hold_expires_at = NULL, updated_at = CURRENT_TIMESTAMP 
+	          WHERE flight_id = ? AND seat_no = ? AND holder_id = ?
+	          AND status IN ('active', 'payment_pending') AND expires_at > NOW()`
 	
 	result, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID)
 	if err != nil {
@@ -286,46 +495,117 @@ func (q *Queries) ConfirmSeatLock(ctx context.Context, arg ConfirmSeatLockParams
 	return rowsAffected, nil
 }
 
-func (q *Queries) ReleaseSeatLock(ctx context.Context, arg ReleaseSeatLockParams) error {
-	query := `DELETE FROM seat_locks 
-	WHERE flight_id = ? AND seat_no = ? AND holder_id = ?`
+func (q *Queries) ReleaseSeatLock(ctx context.Context, arg ReleaseSeatLockParams) (int64, error) {
+	query := `UPDATE seat_locks 
+	SET status = 'released', expires_at = NULL, hold_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE flight_id = ? AND seat_no = ? AND holder_id = ? AND status = 'active'`
 	
-	_, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID)
-	return err
+	result, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID)
+	if err != nil {
+		return 0, err
+	}
+	
+	return result.RowsAffected()
+}
+
+func (q *Queries) DeleteSeatLock(ctx context.Context, arg GetSeatLockParams) (int64, error) {
+	query := `DELETE FROM seat_locks WHERE flight_id = ? AND seat_no = ?`
+	
+	result, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo)
+	if err != nil {
+		return 0, err
+	}
+	
+	return result.RowsAffected()
 }
 
 func (q *Queries) GetSeatLock(ctx context.Context, arg GetSeatLockParams) (SeatLock, error) {
-	query := `SELECT id, flight_id, seat_no, holder_id, expires_at, created_at, updated_at 
-	FROM seat_locks WHERE flight_id = ? AND seat_no = ? AND expires_at > NOW()`
+	query := `SELECT id, flight_id, seat_no, holder_id, status, expires_at, created_at, updated_at 
+	FROM seat_locks WHERE flight_id = ? AND seat_no = ?`
 	
 	var lock SeatLock
 	err := q.db.QueryRowContext(ctx, query, arg.FlightID, arg.SeatNo).Scan(
-		&lock.ID, &lock.FlightID, &lock.SeatNo, &lock.HolderID, 
+		&lock.ID, &lock.FlightID, &lock.SeatNo, &lock.HolderID, &lock.Status,
 		&lock.ExpiresAt, &lock.CreatedAt, &lock.UpdatedAt,
 	)
 	
 	return lock, err
 }
 
-func (q *Queries) CleanupExpiredLocks(ctx context.Context) error {
-	query := `DELETE FROM seat_locks WHERE expires_at <= NOW()`
+// ListExpiredSeatLocks returns the active and payment_pending locks whose
+// expiry is before cutoff
+func (q *Queries) ListExpiredSeatLocks(ctx context.Context, cutoff time.Time) ([]SeatLock, error) {
+	query := `SELECT id, flight_id, seat_no, holder_id, status, expires_at, created_at, updated_at
+	FROM seat_locks WHERE status IN ('active', 'payment_pending') AND expires_at < ? FOR UPDATE`
 	
-	_, err := q.db.ExecContext(ctx, query)
-	return err
+	rows, err := q.db.QueryContext(ctx, query, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	locks := []SeatLock{}
+	for rows.Next() {
+		var lock SeatLock
+		if err := rows.Scan(&lock.ID, &lock.FlightID, &lock.SeatNo, &lock.HolderID, &lock.Status,
+			&lock.ExpiresAt, &lock.CreatedAt, &lock.UpdatedAt); err != nil {
+			return nil, err
+		}
+		locks = append(locks, lock)
+	}
+	
+	return locks, rows.Err()
+}
+
+// CleanupExpiredLocks ends the holds expiring before cutoff: active holds
+// become expired, payment_pending holds whose payment never resolved are
+// rolled back to released
+func (q *Queries) CleanupExpiredLocks(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `UPDATE seat_locks 
+	SET status = IF(status = 'payment_pending', 'released', 'expired'),
+	    hold_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE status IN ('active', 'payment_pending') AND expires_at < ?`
+	
+	result, err := q.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	
+	return result.RowsAffected()
 }
 
 func (q *Queries) ListFlightSeatLocks(ctx context.Context, flightID int64) ([]SeatLock, error) {
-	// This will be implemented by sqlc
-	return []SeatLock{}, nil
+	query := `SELECT id, flight_id, seat_no, holder_id, status, expires_at, created_at, updated_at
+	FROM seat_locks WHERE flight_id = ?`
+	
+	rows, err := q.db.QueryContext(ctx, query, flightID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	locks := []SeatLock{}
+	for rows.Next() {
+		var lock SeatLock
+		if err := rows.Scan(&lock.ID, &lock.FlightID, &lock.SeatNo, &lock.HolderID, &lock.Status,
+			&lock.ExpiresAt, &lock.CreatedAt, &lock.UpdatedAt); err != nil {
+			return nil, err
+		}
+		locks = append(locks, lock)
+	}
+	
+	return locks, rows.Err()
 }
 
 func (q *Queries) CreateTicket(ctx context.Context, arg CreateTicketParams) (int64, error) {
-	query := `INSERT INTO tickets (flight_id, seat_no, user_id, price_amount, currency, pnr_code, payment_ref) 
-	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO tickets (flight_id, seat_no, cabin_class, user_id, price_amount, currency,
+	                               base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                               pnr_code, payment_ref, payment_authorization_id, payment_status) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	result, err := q.db.ExecContext(ctx, query, 
-		arg.FlightID, arg.SeatNo, arg.UserID, arg.PriceAmount, 
-		arg.Currency, arg.PnrCode, arg.PaymentRef)
+		arg.FlightID, arg.SeatNo, arg.CabinClass, arg.UserID, arg.PriceAmount, 
+		arg.Currency, arg.BasePriceAmount, arg.BaseCurrency, arg.ExchangeRate, arg.ExchangeRateEffectiveFrom, arg.PnrCode, arg.PaymentRef, arg.PaymentAuthorizationID, arg.PaymentStatus)
 	if err != nil {
 		return 0, err
 	}
@@ -339,15 +619,19 @@ func (q *Queries) CreateTicket(ctx context.Context, arg CreateTicketParams) (int
 }
 
 func (q *Queries) GetTicket(ctx context.Context, id int64) (Ticket, error) {
-	query := `SELECT id, flight_id, seat_no, user_id, price_amount, currency, 
-	                 pnr_code, payment_ref, created_at, updated_at 
+	query := `SELECT id, flight_id, seat_no, cabin_class, user_id, price_amount, currency, 
+	                 base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                 pnr_code, payment_ref, payment_authorization_id, payment_status, status, cancelled_at,
+	                 created_at, updated_at 
 	          FROM tickets WHERE id = ?`
 	
 	var t Ticket
 	var updatedAt time.Time
 	err := q.db.QueryRowContext(ctx, query, id).Scan(
-		&t.ID, &t.FlightID, &t.SeatNo, &t.UserID, &t.PriceAmount, &t.Currency,
-		&t.PnrCode, &t.PaymentRef, &t.CreatedAt, &updatedAt)
+		&t.ID, &t.FlightID, &t.SeatNo, &t.CabinClass, &t.UserID, &t.PriceAmount, &t.Currency,
+		&t.BasePriceAmount, &t.BaseCurrency, &t.ExchangeRate, &t.ExchangeRateEffectiveFrom,
+		&t.PnrCode, &t.PaymentRef, &t.PaymentAuthorizationID, &t.PaymentStatus, &t.Status, &t.CancelledAt,
+		&t.CreatedAt, &updatedAt)
 	
 	// Set IssuedAt to CreatedAt since we don't have a separate issued_at column
 	t.IssuedAt = t.CreatedAt
@@ -363,21 +647,110 @@ func (q *Queries) GetTicket(ctx context.Context, id int64) (Ticket, error) {
 }
 
 func (q *Queries) GetTicketByPNR(ctx context.Context, pnrCode string) (Ticket, error) {
-	// This will be implemented by sqlc
-	return Ticket{}, nil
+	query := `SELECT id, flight_id, seat_no, cabin_class, user_id, price_amount, currency, 
+	                 base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                 pnr_code, payment_ref, payment_authorization_id, payment_status, status, cancelled_at,
+	                 created_at, updated_at 
+	          FROM tickets WHERE pnr_code = ?`
+	
+	var t Ticket
+	var updatedAt time.Time
+	err := q.db.QueryRowContext(ctx, query, pnrCode).Scan(
+		&t.ID, &t.FlightID, &t.SeatNo, &t.CabinClass, &t.UserID, &t.PriceAmount, &t.Currency,
+		&t.BasePriceAmount, &t.BaseCurrency, &t.ExchangeRate, &t.ExchangeRateEffectiveFrom,
+		&t.PnrCode, &t.PaymentRef, &t.PaymentAuthorizationID, &t.PaymentStatus, &t.Status, &t.CancelledAt,
+		&t.CreatedAt, &updatedAt)
+	if err != nil {
+		return Ticket{}, err
+	}
+	
+	t.IssuedAt = t.CreatedAt
+	return t, nil
+}
+
+func (q *Queries) GetTicketByPNRForUpdate(ctx context.Context, pnrCode string) (Ticket, error) {
+	query := `SELECT id, flight_id, seat_no, cabin_class, user_id, price_amount, currency, 
+	                 base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                 pnr_code, payment_ref, payment_authorization_id, payment_status, status, cancelled_at,
+	                 created_at, updated_at 
+	          FROM tickets WHERE pnr_code = ? FOR UPDATE`
+	
+	var t Ticket
+	var updatedAt time.Time
+	err := q.db.QueryRowContext(ctx, query, pnrCode).Scan(
+		&t.ID, &t.FlightID, &t.SeatNo, &t.CabinClass, &t.UserID, &t.PriceAmount, &t.Currency,
+		&t.BasePriceAmount, &t.BaseCurrency, &t.ExchangeRate, &t.ExchangeRateEffectiveFrom,
+		&t.PnrCode, &t.PaymentRef, &t.PaymentAuthorizationID, &t.PaymentStatus, &t.Status, &t.CancelledAt,
+		&t.CreatedAt, &updatedAt)
+	if err != nil {
+		return Ticket{}, err
+	}
+	
+	t.IssuedAt = t.CreatedAt
+	return t, nil
+}
+
+func (q *Queries) CancelTicket(ctx context.Context, id int64) (int64, error) {
+	query := `UPDATE tickets SET status = 'cancelled', cancelled_at = NOW()
+	          WHERE id = ? AND status <> 'cancelled'`
+	
+	result, err := q.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return 0, err
+	}
+	
+	return result.RowsAffected()
+}
+
+func (q *Queries) UpdateTicketPaymentStatus(ctx context.Context, arg UpdateTicketPaymentStatusParams) error {
+	query := `UPDATE tickets SET payment_status = ? WHERE id = ?`
+	_, err := q.db.ExecContext(ctx, query, arg.PaymentStatus, arg.ID)
+	return err
+}
+
+func (q *Queries) GetTicketByPaymentAuthorizationForUpdate(ctx context.Context, authorizationID string) (Ticket, error) {
+	query := `SELECT id, flight_id, seat_no, cabin_class, user_id, price_amount, currency, 
+	                 base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                 pnr_code, payment_ref, payment_authorization_id, payment_status, status, cancelled_at,
+	                 created_at, updated_at 
+	          FROM tickets WHERE payment_authorization_id = ? FOR UPDATE`
+	
+	var t Ticket
+	var updatedAt time.Time
+	err := q.db.QueryRowContext(ctx, query, authorizationID).Scan(
+		&t.ID, &t.FlightID, &t.SeatNo, &t.CabinClass, &t.UserID, &t.PriceAmount, &t.Currency,
+		&t.BasePriceAmount, &t.BaseCurrency, &t.ExchangeRate, &t.ExchangeRateEffectiveFrom,
+		&t.PnrCode, &t.PaymentRef, &t.PaymentAuthorizationID, &t.PaymentStatus, &t.Status, &t.CancelledAt,
+		&t.CreatedAt, &updatedAt)
+	if err != nil {
+		return Ticket{}, err
+	}
+	
+	t.IssuedAt = t.CreatedAt
+	return t, nil
+}
+
+func (q *Queries) UpdateTicketPaymentState(ctx context.Context, arg UpdateTicketPaymentStateParams) error {
+	query := `UPDATE tickets SET payment_status = ?, status = ? WHERE id = ?`
+	_, err := q.db.ExecContext(ctx, query, arg.PaymentStatus, arg.Status, arg.ID)
+	return err
 }
 
 func (q *Queries) GetTicketByFlightSeat(ctx context.Context, arg GetTicketByFlightSeatParams) (Ticket, error) {
-	query := `SELECT id, flight_id, seat_no, user_id, price_amount, currency, 
-	                 pnr_code, payment_ref, created_at, updated_at 
+	query := `SELECT id, flight_id, seat_no, cabin_class, user_id, price_amount, currency, 
+	                 base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                 pnr_code, payment_ref, payment_authorization_id, payment_status, status, cancelled_at,
+	                 created_at, updated_at 
 	          FROM tickets 
-	          WHERE flight_id = ? AND seat_no = ?`
+	          WHERE flight_id = ? AND seat_no = ? AND status <> 'cancelled'`
 	
 	var t Ticket
 	var updatedAt time.Time
 	err := q.db.QueryRowContext(ctx, query, arg.FlightID, arg.SeatNo).Scan(
-		&t.ID, &t.FlightID, &t.SeatNo, &t.UserID, &t.PriceAmount, &t.Currency,
-		&t.PnrCode, &t.PaymentRef, &t.CreatedAt, &updatedAt)
+		&t.ID, &t.FlightID, &t.SeatNo, &t.CabinClass, &t.UserID, &t.PriceAmount, &t.Currency,
+		&t.BasePriceAmount, &t.BaseCurrency, &t.ExchangeRate, &t.ExchangeRateEffectiveFrom,
+		&t.PnrCode, &t.PaymentRef, &t.PaymentAuthorizationID, &t.PaymentStatus, &t.Status, &t.CancelledAt,
+		&t.CreatedAt, &updatedAt)
 	
 	// Set IssuedAt to CreatedAt since we don't have a separate issued_at column
 	t.IssuedAt = t.CreatedAt
@@ -399,8 +772,88 @@ func (q *Queries) ListUserTickets(ctx context.Context, userID string) ([]Ticket,
 }
 
 func (q *Queries) ListFlightTickets(ctx context.Context, flightID int64) ([]Ticket, error) {
-	// This will be implemented by sqlc
-	return []Ticket{}, nil
+	query := `SELECT id, flight_id, seat_no, cabin_class, user_id, price_amount, currency, 
+	                 base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                 pnr_code, payment_ref, payment_authorization_id, payment_status, status, cancelled_at,
+	                 created_at, updated_at 
+	          FROM tickets WHERE flight_id = ? ORDER BY seat_no`
+	
+	rows, err := q.db.QueryContext(ctx, query, flightID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	tickets := []Ticket{}
+	for rows.Next() {
+		var t Ticket
+		var updatedAt time.Time
+		if err := rows.Scan(&t.ID, &t.FlightID, &t.SeatNo, &t.CabinClass, &t.UserID, &t.PriceAmount, &t.Currency,
+			&t.BasePriceAmount, &t.BaseCurrency, &t.ExchangeRate, &t.ExchangeRateEffectiveFrom,
+		&t.PnrCode, &t.PaymentRef, &t.PaymentAuthorizationID, &t.PaymentStatus, &t.Status, &t.CancelledAt,
+		&t.CreatedAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		t.IssuedAt = t.CreatedAt
+		tickets = append(tickets, t)
+	}
+	
+	return tickets, rows.Err()
+}
+
+type ListDeniedBoardingCandidatesParams struct {
+	FlightID   int64
+	CabinClass string
+}
+
+// ListDeniedBoardingCandidates orders a flight's active tickets by who should
+// be asked to volunteer first: lowest fare, then most recently booked
+func (q *Queries) ListDeniedBoardingCandidates(ctx context.Context, arg ListDeniedBoardingCandidatesParams) ([]Ticket, error) {
+	query := `SELECT id, flight_id, seat_no, cabin_class, user_id, price_amount, currency, 
+	                 base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+	                 pnr_code, payment_ref, payment_authorization_id, payment_status, status, cancelled_at,
+	                 created_at, updated_at 
+	          FROM tickets
+	          WHERE flight_id = ? AND status <> 'cancelled'
+	          AND (? = '' OR cabin_class = ?)
+	          ORDER BY price_amount ASC, created_at DESC, id DESC`
+	
+	rows, err := q.db.QueryContext(ctx, query, arg.FlightID, arg.CabinClass, arg.CabinClass)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	tickets := []Ticket{}
+	for rows.Next() {
+		var t Ticket
+		var updatedAt time.Time
+		if err := rows.Scan(&t.ID, &t.FlightID, &t.SeatNo, &t.CabinClass, &t.UserID, &t.PriceAmount, &t.Currency,
+			&t.BasePriceAmount, &t.BaseCurrency, &t.ExchangeRate, &t.ExchangeRateEffectiveFrom,
+		&t.PnrCode, &t.PaymentRef, &t.PaymentAuthorizationID, &t.PaymentStatus, &t.Status, &t.CancelledAt,
+		&t.CreatedAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		t.IssuedAt = t.CreatedAt
+		tickets = append(tickets, t)
+	}
+	
+	return tickets, rows.Err()
+}
+
+type AddTicketAmountsParams struct {
+	PriceAmount     int64
+	BasePriceAmount int64
+	ID              int64
+}
+
+// AddTicketAmounts adds to a ticket's totals when items are bought after it
+// was issued
+func (q *Queries) AddTicketAmounts(ctx context.Context, arg AddTicketAmountsParams) error {
+	query := `UPDATE tickets SET price_amount = price_amount + ?, base_price_amount = base_price_amount + ?
+	          WHERE id = ?`
+	_, err := q.db.ExecContext(ctx, query, arg.PriceAmount, arg.BasePriceAmount, arg.ID)
+	return err
 }
 
 func (q *Queries) CreateIdempotencyKey(ctx context.Context, arg CreateIdempotencyKeyParams) error {
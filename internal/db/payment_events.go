@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/payment_events.sql - these will be generated by sqlc
+
+type PaymentWebhookEvent struct {
+	ID              int64           `json:"id"`
+	Provider        string          `json:"provider"`
+	EventID         string          `json:"event_id"`
+	EventType       string          `json:"event_type"`
+	AuthorizationID sql.NullString  `json:"authorization_id"`
+	Payload         json.RawMessage `json:"payload"`
+	ReceivedAt      time.Time       `json:"received_at"`
+	ProcessedAt     sql.NullTime    `json:"processed_at"`
+	ProcessingError sql.NullString  `json:"processing_error"`
+}
+
+type CreatePaymentWebhookEventParams struct {
+	Provider        string
+	EventID         string
+	EventType       string
+	AuthorizationID sql.NullString
+	Payload         json.RawMessage
+}
+
+type GetPaymentWebhookEventParams struct {
+	Provider string
+	EventID  string
+}
+
+type MarkPaymentWebhookEventProcessedParams struct {
+	ProcessingError sql.NullString
+	ID              int64
+}
+
+const paymentWebhookEventColumns = `id, provider, event_id, event_type, authorization_id, payload, received_at, processed_at, processing_error`
+
+// CreatePaymentWebhookEvent ignores events already stored for the provider;
+// the returned ID is 0 for duplicates
+func (q *Queries) CreatePaymentWebhookEvent(ctx context.Context, arg CreatePaymentWebhookEventParams) (int64, error) {
+	query := `INSERT IGNORE INTO payment_webhook_events (provider, event_id, event_type, authorization_id, payload)
+	VALUES (?, ?, ?, ?, ?)`
+
+	result, err := q.db.ExecContext(ctx, query, arg.Provider, arg.EventID, arg.EventType, arg.AuthorizationID, []byte(arg.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil || rowsAffected == 0 {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (q *Queries) GetPaymentWebhookEvent(ctx context.Context, arg GetPaymentWebhookEventParams) (PaymentWebhookEvent, error) {
+	query := `SELECT ` + paymentWebhookEventColumns + `
+	FROM payment_webhook_events WHERE provider = ? AND event_id = ?`
+
+	row := q.db.QueryRowContext(ctx, query, arg.Provider, arg.EventID)
+	return scanPaymentWebhookEvent(row)
+}
+
+func (q *Queries) GetPaymentWebhookEventForUpdate(ctx context.Context, id int64) (PaymentWebhookEvent, error) {
+	query := `SELECT ` + paymentWebhookEventColumns + `
+	FROM payment_webhook_events WHERE id = ? FOR UPDATE`
+
+	row := q.db.QueryRowContext(ctx, query, id)
+	return scanPaymentWebhookEvent(row)
+}
+
+func (q *Queries) ListUnprocessedPaymentWebhookEvents(ctx context.Context, provider string) ([]PaymentWebhookEvent, error) {
+	query := `SELECT ` + paymentWebhookEventColumns + `
+	FROM payment_webhook_events
+	WHERE provider = ? AND processed_at IS NULL
+	ORDER BY received_at, id`
+
+	rows, err := q.db.QueryContext(ctx, query, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []PaymentWebhookEvent
+	for rows.Next() {
+		event, err := scanPaymentWebhookEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, event)
+	}
+	return items, rows.Err()
+}
+
+// MarkPaymentWebhookEventProcessed sets processed_at, or records the error and
+// leaves the event pending when ProcessingError is set
+func (q *Queries) MarkPaymentWebhookEventProcessed(ctx context.Context, arg MarkPaymentWebhookEventProcessedParams) error {
+	query := `UPDATE payment_webhook_events
+	SET processed_at = IF(? IS NULL, NOW(), NULL), processing_error = ?
+	WHERE id = ?`
+
+	_, err := q.db.ExecContext(ctx, query, arg.ProcessingError, arg.ProcessingError, arg.ID)
+	return err
+}
+
+func scanPaymentWebhookEvent(row interface{ Scan(...any) error }) (PaymentWebhookEvent, error) {
+	var e PaymentWebhookEvent
+	var payload []byte
+	err := row.Scan(&e.ID, &e.Provider, &e.EventID, &e.EventType, &e.AuthorizationID, &payload,
+		&e.ReceivedAt, &e.ProcessedAt, &e.ProcessingError)
+	e.Payload = payload
+	return e, err
+}
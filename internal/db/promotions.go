@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/promotions.sql - these will be generated by sqlc
+
+type Promotion struct {
+	ID                    int64          `json:"id"`
+	Code                  string         `json:"code"`
+	Description           string         `json:"description"`
+	DiscountType          string         `json:"discount_type"`
+	DiscountValue         int64          `json:"discount_value"`
+	MaxDiscountAmount     sql.NullInt64  `json:"max_discount_amount"`
+	Origin                sql.NullString `json:"origin"`
+	Destination           sql.NullString `json:"destination"`
+	Airline               sql.NullString `json:"airline"`
+	FareClass             sql.NullString `json:"fare_class"`
+	TravelFrom            sql.NullTime   `json:"travel_from"`
+	TravelUntil           sql.NullTime   `json:"travel_until"`
+	ValidFrom             time.Time      `json:"valid_from"`
+	ValidUntil            sql.NullTime   `json:"valid_until"`
+	MaxRedemptions        sql.NullInt32  `json:"max_redemptions"`
+	MaxRedemptionsPerUser sql.NullInt32  `json:"max_redemptions_per_user"`
+	RedemptionCount       int32          `json:"redemption_count"`
+	Stackable             bool           `json:"stackable"`
+	Active                bool           `json:"active"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+}
+
+type CreatePromotionParams struct {
+	Code                  string
+	Description           string
+	DiscountType          string
+	DiscountValue         int64
+	MaxDiscountAmount     sql.NullInt64
+	Origin                sql.NullString
+	Destination           sql.NullString
+	Airline               sql.NullString
+	FareClass             sql.NullString
+	TravelFrom            sql.NullTime
+	TravelUntil           sql.NullTime
+	ValidFrom             time.Time
+	ValidUntil            sql.NullTime
+	MaxRedemptions        sql.NullInt32
+	MaxRedemptionsPerUser sql.NullInt32
+	Stackable             bool
+}
+
+type CountUserPromotionRedemptionsParams struct {
+	PromotionID int64
+	UserID      string
+}
+
+type CreatePromotionRedemptionParams struct {
+	PromotionID    int64
+	UserID         string
+	TicketID       int64
+	DiscountAmount int64
+}
+
+type HoldPromotionParams struct {
+	FlightID    int64
+	SeatNo      string
+	HolderID    string
+	PromotionID int64
+}
+
+type ListHoldPromotionCodesParams struct {
+	FlightID int64
+	SeatNo   string
+	HolderID string
+}
+
+const promotionColumns = `id, code, description, discount_type, discount_value, max_discount_amount,
+	origin, destination, airline, fare_class, travel_from, travel_until, valid_from, valid_until,
+	max_redemptions, max_redemptions_per_user, redemption_count, stackable, active, created_at, updated_at`
+
+func (q *Queries) CreatePromotion(ctx context.Context, arg CreatePromotionParams) (int64, error) {
+	query := `INSERT INTO promotions (code, description, discount_type, discount_value, max_discount_amount,
+		origin, destination, airline, fare_class, travel_from, travel_until, valid_from, valid_until,
+		max_redemptions, max_redemptions_per_user, stackable)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := q.db.ExecContext(ctx, query,
+		arg.Code, arg.Description, arg.DiscountType, arg.DiscountValue, arg.MaxDiscountAmount,
+		arg.Origin, arg.Destination, arg.Airline, arg.FareClass, arg.TravelFrom, arg.TravelUntil,
+		arg.ValidFrom, arg.ValidUntil, arg.MaxRedemptions, arg.MaxRedemptionsPerUser, arg.Stackable)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (q *Queries) GetPromotion(ctx context.Context, id int64) (Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = ?`
+	return scanPromotion(q.db.QueryRowContext(ctx, query, id))
+}
+
+func (q *Queries) GetPromotionByCode(ctx context.Context, code string) (Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE code = ?`
+	return scanPromotion(q.db.QueryRowContext(ctx, query, code))
+}
+
+// GetPromotionForUpdate locks the promotion so redemptions of the same code
+// serialize
+func (q *Queries) GetPromotionForUpdate(ctx context.Context, id int64) (Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = ? FOR UPDATE`
+	return scanPromotion(q.db.QueryRowContext(ctx, query, id))
+}
+
+func (q *Queries) ListPromotions(ctx context.Context) ([]Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions ORDER BY created_at DESC, id DESC`
+
+	rows, err := q.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Promotion
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, promotion)
+	}
+	return items, rows.Err()
+}
+
+func (q *Queries) CountUserPromotionRedemptions(ctx context.Context, arg CountUserPromotionRedemptionsParams) (int64, error) {
+	query := `SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = ? AND user_id = ?`
+
+	var count int64
+	err := q.db.QueryRowContext(ctx, query, arg.PromotionID, arg.UserID).Scan(&count)
+	return count, err
+}
+
+// CountUserPromotionRedemptionsForUpdate is a locking read, so it sees
+// redemptions committed after the transaction's snapshot was taken
+func (q *Queries) CountUserPromotionRedemptionsForUpdate(ctx context.Context, arg CountUserPromotionRedemptionsParams) (int64, error) {
+	query := `SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = ? AND user_id = ? FOR UPDATE`
+
+	var count int64
+	err := q.db.QueryRowContext(ctx, query, arg.PromotionID, arg.UserID).Scan(&count)
+	return count, err
+}
+
+func (q *Queries) CreatePromotionRedemption(ctx context.Context, arg CreatePromotionRedemptionParams) error {
+	query := `INSERT INTO promotion_redemptions (promotion_id, user_id, ticket_id, discount_amount)
+	VALUES (?, ?, ?, ?)`
+
+	_, err := q.db.ExecContext(ctx, query, arg.PromotionID, arg.UserID, arg.TicketID, arg.DiscountAmount)
+	return err
+}
+
+func (q *Queries) IncrementPromotionRedemptions(ctx context.Context, id int64) error {
+	query := `UPDATE promotions SET redemption_count = redemption_count + 1 WHERE id = ?`
+	_, err := q.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (q *Queries) DeleteHoldPromotions(ctx context.Context, arg GetSeatLockParams) error {
+	query := `DELETE FROM hold_promotions WHERE flight_id = ? AND seat_no = ?`
+	_, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo)
+	return err
+}
+
+func (q *Queries) CreateHoldPromotion(ctx context.Context, arg HoldPromotionParams) error {
+	query := `INSERT INTO hold_promotions (flight_id, seat_no, holder_id, promotion_id) VALUES (?, ?, ?, ?)`
+	_, err := q.db.ExecContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID, arg.PromotionID)
+	return err
+}
+
+// ListHoldPromotionCodes returns the codes applied to the holder's hold in
+// the order they were applied
+func (q *Queries) ListHoldPromotionCodes(ctx context.Context, arg ListHoldPromotionCodesParams) ([]string, error) {
+	query := `SELECT p.code
+	FROM hold_promotions hp
+	JOIN promotions p ON p.id = hp.promotion_id
+	WHERE hp.flight_id = ? AND hp.seat_no = ? AND hp.holder_id = ?
+	ORDER BY hp.created_at, p.id`
+
+	rows, err := q.db.QueryContext(ctx, query, arg.FlightID, arg.SeatNo, arg.HolderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []string
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, rows.Err()
+}
+
+func scanPromotion(row interface{ Scan(...any) error }) (Promotion, error) {
+	var p Promotion
+	err := row.Scan(&p.ID, &p.Code, &p.Description, &p.DiscountType, &p.DiscountValue, &p.MaxDiscountAmount,
+		&p.Origin, &p.Destination, &p.Airline, &p.FareClass, &p.TravelFrom, &p.TravelUntil, &p.ValidFrom, &p.ValidUntil,
+		&p.MaxRedemptions, &p.MaxRedemptionsPerUser, &p.RedemptionCount, &p.Stackable, &p.Active, &p.CreatedAt, &p.UpdatedAt)
+	return p, err
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"time"
+)
+
+// Placeholder implementations for sql/queries/ticket_line_items.sql - these will be generated by sqlc
+
+type TicketLineItem struct {
+	ID          int64     `json:"id"`
+	TicketID    int64     `json:"ticket_id"`
+	Component   string    `json:"component"`
+	Code        string    `json:"code"`
+	Description string    `json:"description"`
+	Amount      int64     `json:"amount"`
+	BaseAmount  int64     `json:"base_amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateTicketLineItemParams struct {
+	TicketID    int64
+	Component   string
+	Code        string
+	Description string
+	Amount      int64
+	BaseAmount  int64
+}
+
+func (q *Queries) CreateTicketLineItem(ctx context.Context, arg CreateTicketLineItemParams) error {
+	query := `INSERT INTO ticket_line_items (ticket_id, component, code, description, amount, base_amount)
+	VALUES (?, ?, ?, ?, ?, ?)`
+
+	_, err := q.db.ExecContext(ctx, query,
+		arg.TicketID, arg.Component, arg.Code, arg.Description, arg.Amount, arg.BaseAmount)
+	return err
+}
+
+func (q *Queries) ListTicketLineItems(ctx context.Context, ticketID int64) ([]TicketLineItem, error) {
+	query := `SELECT id, ticket_id, component, code, description, amount, base_amount, created_at
+	FROM ticket_line_items
+	WHERE ticket_id = ?
+	ORDER BY id`
+
+	rows, err := q.db.QueryContext(ctx, query, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TicketLineItem
+	for rows.Next() {
+		var i TicketLineItem
+		if err := rows.Scan(&i.ID, &i.TicketID, &i.Component, &i.Code, &i.Description,
+			&i.Amount, &i.BaseAmount, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	return items, rows.Err()
+}
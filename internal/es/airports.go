@@ -0,0 +1,208 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/tracing"
+)
+
+const AirportsIndex = "airports"
+
+type AirportDocument struct {
+	IATACode   string  `json:"iata_code"`
+	Name       string  `json:"name"`
+	City       string  `json:"city"`
+	Country    string  `json:"country"`
+	Timezone   string  `json:"timezone"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Popularity int     `json:"popularity"`
+}
+
+// airportsMapping indexes code, name and city with an edge n-gram analyzer so
+// that prefixes such as "sao" or "gr" match while typing. Search uses the plain
+// analyzer to avoid n-gramming the query itself.
+const airportsMapping = `{
+	"settings": {
+		"analysis": {
+			"filter": {
+				"autocomplete_filter": {
+					"type": "edge_ngram",
+					"min_gram": 1,
+					"max_gram": 20
+				}
+			},
+			"analyzer": {
+				"autocomplete": {
+					"type": "custom",
+					"tokenizer": "standard",
+					"filter": ["lowercase", "asciifolding", "autocomplete_filter"]
+				},
+				"autocomplete_search": {
+					"type": "custom",
+					"tokenizer": "standard",
+					"filter": ["lowercase", "asciifolding"]
+				}
+			}
+		}
+	},
+	"mappings": {
+		"properties": {
+			"iata_code": {
+				"type": "keyword",
+				"fields": {
+					"prefix": {"type": "text", "analyzer": "autocomplete", "search_analyzer": "autocomplete_search"}
+				}
+			},
+			"name": {"type": "text", "analyzer": "autocomplete", "search_analyzer": "autocomplete_search"},
+			"city": {"type": "text", "analyzer": "autocomplete", "search_analyzer": "autocomplete_search"},
+			"country": {"type": "keyword"},
+			"timezone": {"type": "keyword"},
+			"latitude": {"type": "double"},
+			"longitude": {"type": "double"},
+			"popularity": {"type": "integer"}
+		}
+	}
+}`
+
+func (c *Client) BulkIndexAirports(ctx context.Context, airports []AirportDocument) (err error) {
+	ctx, span := startSpan(ctx, "BulkIndexAirports")
+	defer func() { tracing.End(span, err) }()
+
+	if len(airports) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, airport := range airports {
+		meta := map[string]interface{}{
+			"index": map[string]interface{}{
+				"_index": AirportsIndex,
+				"_id":    airport.IATACode,
+			},
+		}
+
+		metaBytes, err := json.Marshal(meta)
+		if err != nil {
+			return fmt.Errorf("failed to marshal meta: %w", err)
+		}
+
+		docBytes, err := json.Marshal(airport)
+		if err != nil {
+			return fmt.Errorf("failed to marshal airport: %w", err)
+		}
+
+		buf.Write(metaBytes)
+		buf.WriteByte('\n')
+		buf.Write(docBytes)
+		buf.WriteByte('\n')
+	}
+
+	req := esapi.BulkRequest{
+		Body:    &buf,
+		Refresh: "true",
+	}
+
+	res, err := req.Do(ctx, c.es)
+	if err != nil {
+		return fmt.Errorf("failed to bulk index airports: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to bulk index airports: %s", res.String())
+	}
+
+	c.logger.Info("Bulk indexed airports successfully", zap.Int("count", len(airports)))
+	return nil
+}
+
+// SuggestAirports returns airports whose code, name or city starts with the
+// typed text. An exact IATA code match always ranks first, the rest are
+// ordered by popularity.
+func (c *Client) SuggestAirports(ctx context.Context, text string, size int) (_ []AirportDocument, err error) {
+	ctx, span := startSpan(ctx, "SuggestAirports")
+	defer func() { tracing.End(span, err) }()
+
+	text = strings.TrimSpace(text)
+
+	searchBody := map[string]interface{}{
+		"size": size,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				// Filters don't contribute to the score, so the only scoring
+				// clause is the exact code match below
+				"filter": []map[string]interface{}{
+					{
+						"multi_match": map[string]interface{}{
+							"query":    text,
+							"type":     "cross_fields",
+							"operator": "and",
+							"fields":   []string{"iata_code.prefix", "name", "city"},
+						},
+					},
+				},
+				"should": []map[string]interface{}{
+					{
+						"constant_score": map[string]interface{}{
+							"filter": map[string]interface{}{
+								"term": map[string]interface{}{"iata_code": strings.ToUpper(text)},
+							},
+							"boost": 1,
+						},
+					},
+				},
+			},
+		},
+		"sort": []interface{}{
+			"_score",
+			map[string]interface{}{"popularity": map[string]interface{}{"order": "desc"}},
+			map[string]interface{}{"iata_code": map[string]interface{}{"order": "asc"}},
+		},
+	}
+
+	body, err := json.Marshal(searchBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal suggest query: %w", err)
+	}
+
+	searchReq := esapi.SearchRequest{
+		Index: []string{AirportsIndex},
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := searchReq.Do(ctx, c.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest airports: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("suggest error: %s", res.String())
+	}
+
+	var searchRes struct {
+		Hits struct {
+			Hits []struct {
+				Source AirportDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&searchRes); err != nil {
+		return nil, fmt.Errorf("failed to decode suggest response: %w", err)
+	}
+
+	airports := make([]AirportDocument, len(searchRes.Hits.Hits))
+	for i, hit := range searchRes.Hits.Hits {
+		airports[i] = hit.Source
+	}
+
+	return airports, nil
+}
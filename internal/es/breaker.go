@@ -0,0 +1,127 @@
+package es
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/metrics"
+)
+
+// ErrCircuitOpen is returned without contacting Elasticsearch while the
+// circuit breaker is open
+var ErrCircuitOpen = errors.New("elasticsearch circuit breaker is open")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	// breakerHalfOpen lets a single probe request through after the
+	// cooldown; its result closes or reopens the breaker
+	breakerHalfOpen
+)
+
+// breaker counts consecutive failed requests and, once failures is
+// reached, fails requests fast for cooldown so a down cluster doesn't add
+// its timeouts to every request that touches search
+type breaker struct {
+	failures int
+	cooldown time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu          sync.Mutex
+	state       breakerState
+	consecutive int
+	openedAt    time.Time
+}
+
+func newBreaker(failures int, cooldown time.Duration, logger *zap.Logger) *breaker {
+	if failures <= 0 {
+		failures = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &breaker{
+		failures: failures,
+		cooldown: cooldown,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// allow reports whether a request may be sent
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// the probe is still in flight
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// record updates the breaker with the outcome of an allowed request
+func (b *breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if b.state != breakerClosed {
+			b.logger.Info("Elasticsearch is reachable again, closing circuit breaker")
+			metrics.ESCircuitOpen.Set(0)
+		}
+		b.state = breakerClosed
+		b.consecutive = 0
+		return
+	}
+
+	b.consecutive++
+	if b.state == breakerHalfOpen || b.consecutive >= b.failures {
+		if b.state == breakerClosed {
+			b.logger.Warn("Opening Elasticsearch circuit breaker",
+				zap.Int("consecutive_failures", b.consecutive),
+				zap.Duration("cooldown", b.cooldown))
+			metrics.ESCircuitOpen.Set(1)
+		}
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// open reports whether requests are currently failing fast
+func (b *breaker) open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state != breakerClosed
+}
+
+// breakerTransport sends requests through the breaker. Connection errors
+// and 5xx responses count as failures; 4xx responses are the caller's
+// problem and don't.
+type breakerTransport struct {
+	next    http.RoundTripper
+	breaker *breaker
+}
+
+func (t breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		return nil, err
+	}
+	res, err := t.next.RoundTrip(req)
+	t.breaker.record(err != nil || res.StatusCode >= 500)
+	return res, err
+}
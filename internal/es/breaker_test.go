@@ -0,0 +1,72 @@
+package es
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubTransport answers with status, or fails when status is 0
+type stubTransport struct {
+	status int
+	calls  int
+}
+
+func (t *stubTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	t.calls++
+	if t.status == 0 {
+		return nil, errors.New("connection refused")
+	}
+	return &http.Response{StatusCode: t.status, Body: http.NoBody}, nil
+}
+
+func TestBreakerTransport(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	b := newBreaker(3, 30*time.Second, zap.NewNop())
+	b.now = func() time.Time { return now }
+	stub := &stubTransport{}
+	transport := breakerTransport{next: stub, breaker: b}
+	send := func() error {
+		_, err := transport.RoundTrip(httptest.NewRequest("GET", "/flights/_search", nil))
+		return err
+	}
+
+	// client errors don't count towards opening
+	stub.status = http.StatusBadRequest
+	for i := 0; i < 5; i++ {
+		require.NoError(t, send())
+	}
+	assert.False(t, b.open())
+
+	// consecutive failures do, and then requests fail fast
+	stub.status = 0
+	for i := 0; i < 2; i++ {
+		assert.Error(t, send())
+	}
+	stub.status = http.StatusServiceUnavailable
+	require.NoError(t, send())
+	assert.True(t, b.open())
+
+	calls := stub.calls
+	assert.ErrorIs(t, send(), ErrCircuitOpen)
+	assert.Equal(t, calls, stub.calls)
+
+	// after the cooldown one probe goes through; its failure reopens
+	now = now.Add(31 * time.Second)
+	require.NoError(t, send())
+	assert.Equal(t, calls+1, stub.calls)
+	assert.ErrorIs(t, send(), ErrCircuitOpen)
+
+	// and a successful probe closes it
+	now = now.Add(31 * time.Second)
+	stub.status = http.StatusOK
+	require.NoError(t, send())
+	assert.False(t, b.open())
+	require.NoError(t, send())
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -15,11 +16,13 @@ import (
 
 	"airline-booking/internal/config"
 	"airline-booking/internal/models"
+	"airline-booking/internal/tracing"
 )
 
 type Client struct {
-	es     *elasticsearch.Client
-	logger *zap.Logger
+	es      *elasticsearch.Client
+	breaker *breaker
+	logger  *zap.Logger
 }
 
 type FlightDocument struct {
@@ -28,10 +31,14 @@ type FlightDocument struct {
 	Destination   string    `json:"destination"`
 	DepartureTime time.Time `json:"departure_time"`
 	ArrivalTime   time.Time `json:"arrival_time"`
+	DepartureTimeLocal string `json:"departure_time_local,omitempty"` // RFC3339 with origin offset
+	ArrivalTimeLocal   string `json:"arrival_time_local,omitempty"`   // RFC3339 with destination offset
+	OriginTimezone      string `json:"origin_timezone,omitempty"`
+	DestinationTimezone string `json:"destination_timezone,omitempty"`
 	Airline       string    `json:"airline"`
 	Aircraft      string    `json:"aircraft"`
 	FareClass     string    `json:"fare_class"`
-	BasePrice     float64   `json:"base_price"`
+	BasePrice     int64     `json:"base_price"` // minor units of the base currency
 }
 
 type HoldDocument struct {
@@ -74,9 +81,36 @@ const FlightsIndex = "flights"
 const HoldsIndex = "holds"
 const TicketsIndex = "tickets"
 
+// maxConnectBackoff caps the wait between connection attempts at startup
+const maxConnectBackoff = 30 * time.Second
+
+// NewClient creates a client and checks that Elasticsearch answers
 func NewClient(cfg *config.ElasticsearchConfig, logger *zap.Logger) (*Client, error) {
+	client, err := NewLazyClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Ping(context.Background()); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Connected to Elasticsearch successfully")
+
+	return client, nil
+}
+
+// NewLazyClient creates a client without contacting Elasticsearch, so the
+// API can start while the cluster is down. Requests go through a circuit
+// breaker that fails them fast after repeated failures.
+func NewLazyClient(cfg *config.ElasticsearchConfig, logger *zap.Logger) (*Client, error) {
+	breaker := newBreaker(cfg.BreakerFailures, cfg.BreakerCooldown, logger)
 	esCfg := elasticsearch.Config{
 		Addresses: cfg.Addresses,
+		Transport: breakerTransport{
+			next:    instrumentedTransport{next: http.DefaultTransport},
+			breaker: breaker,
+		},
 	}
 
 	if cfg.Username != "" && cfg.Password != "" {
@@ -89,26 +123,66 @@ func NewClient(cfg *config.ElasticsearchConfig, logger *zap.Logger) (*Client, er
 		return nil, fmt.Errorf("failed to create elasticsearch client: %w", err)
 	}
 
-	// Test connection
-	res, err := es.Info()
+	return &Client{
+		es:      es,
+		breaker: breaker,
+		logger:  logger,
+	}, nil
+}
+
+// Ping checks that Elasticsearch answers
+func (c *Client) Ping(ctx context.Context) error {
+	res, err := c.es.Info(c.es.Info.WithContext(ctx))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get elasticsearch info: %w", err)
+		return fmt.Errorf("failed to get elasticsearch info: %w", err)
 	}
 	defer res.Body.Close()
 
 	if res.IsError() {
-		return nil, fmt.Errorf("elasticsearch error: %s", res.String())
+		return fmt.Errorf("elasticsearch error: %s", res.String())
 	}
+	return nil
+}
 
-	logger.Info("Connected to Elasticsearch successfully")
+// CircuitOpen reports whether requests are failing fast because
+// Elasticsearch kept failing
+func (c *Client) CircuitOpen() bool {
+	return c.breaker.open()
+}
 
-	return &Client{
-		es:     es,
-		logger: logger,
-	}, nil
+// ConnectInBackground pings Elasticsearch until it answers, backing off
+// between attempts, then runs setup, e.g. index creation. It returns at
+// once; the retries stop when ctx is done.
+func (c *Client) ConnectInBackground(ctx context.Context, setup func(context.Context) error) {
+	go func() {
+		backoff := time.Second
+		for {
+			err := c.Ping(ctx)
+			if err == nil {
+				err = setup(ctx)
+			}
+			if err == nil {
+				c.logger.Info("Connected to Elasticsearch successfully")
+				return
+			}
+
+			c.logger.Warn("Elasticsearch is unavailable, retrying; search is served from MySQL meanwhile",
+				zap.Duration("retry_in", backoff),
+				zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, maxConnectBackoff)
+		}
+	}()
 }
 
-func (c *Client) CreateIndex(ctx context.Context) error {
+func (c *Client) CreateIndex(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "CreateIndex")
+	defer func() { tracing.End(span, err) }()
+
 	// Create flights index
 	flightsMapping := `{
 		"mappings": {
@@ -118,6 +192,10 @@ func (c *Client) CreateIndex(ctx context.Context) error {
 				"destination": {"type": "keyword"},
 				"departure_time": {"type": "date"},
 				"arrival_time": {"type": "date"},
+				"departure_time_local": {"type": "date", "format": "strict_date_optional_time"},
+				"arrival_time_local": {"type": "date", "format": "strict_date_optional_time"},
+				"origin_timezone": {"type": "keyword"},
+				"destination_timezone": {"type": "keyword"},
 				"airline": {"type": "keyword"},
 				"aircraft": {"type": "keyword"},
 				"fare_class": {"type": "keyword"},
@@ -173,6 +251,11 @@ func (c *Client) CreateIndex(ctx context.Context) error {
 		return err
 	}
 
+	// Create airports index used for autocomplete
+	if err := c.createSingleIndex(ctx, AirportsIndex, airportsMapping); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -196,7 +279,10 @@ func (c *Client) createSingleIndex(ctx context.Context, indexName, mapping strin
 	return nil
 }
 
-func (c *Client) IndexFlight(ctx context.Context, flight FlightDocument) error {
+func (c *Client) IndexFlight(ctx context.Context, flight FlightDocument) (err error) {
+	ctx, span := startSpan(ctx, "IndexFlight")
+	defer func() { tracing.End(span, err) }()
+
 	body, err := json.Marshal(flight)
 	if err != nil {
 		return fmt.Errorf("failed to marshal flight: %w", err)
@@ -222,7 +308,10 @@ func (c *Client) IndexFlight(ctx context.Context, flight FlightDocument) error {
 	return nil
 }
 
-func (c *Client) BulkIndexFlights(ctx context.Context, flights []FlightDocument) error {
+func (c *Client) BulkIndexFlights(ctx context.Context, flights []FlightDocument) (err error) {
+	ctx, span := startSpan(ctx, "BulkIndexFlights")
+	defer func() { tracing.End(span, err) }()
+
 	if len(flights) == 0 {
 		return nil
 	}
@@ -271,8 +360,12 @@ func (c *Client) BulkIndexFlights(ctx context.Context, flights []FlightDocument)
 	return nil
 }
 
-func (c *Client) SearchFlights(ctx context.Context, req models.FlightSearchRequest) (*models.FlightSearchResponse, error) {
-	query := c.buildSearchQuery(req)
+// SearchFlights searches flights departing within the given UTC window
+func (c *Client) SearchFlights(ctx context.Context, req models.FlightSearchRequest, window models.TimeRange) (_ *models.FlightSearchResponse, err error) {
+	ctx, span := startSpan(ctx, "SearchFlights")
+	defer func() { tracing.End(span, err) }()
+
+	query := c.buildSearchQuery(req, window)
 	
 	from := (req.Page - 1) * req.Size
 	
@@ -318,6 +411,8 @@ func (c *Client) SearchFlights(ctx context.Context, req models.FlightSearchReque
 			Destination:   hit.Source.Destination,
 			DepartureTime: hit.Source.DepartureTime,
 			ArrivalTime:   hit.Source.ArrivalTime,
+			DepartureTimeLocal: hit.Source.DepartureTimeLocal,
+			ArrivalTimeLocal:   hit.Source.ArrivalTimeLocal,
 			Airline:       hit.Source.Airline,
 			Aircraft:      hit.Source.Aircraft,
 			FareClass:     hit.Source.FareClass,
@@ -333,22 +428,19 @@ func (c *Client) SearchFlights(ctx context.Context, req models.FlightSearchReque
 	}, nil
 }
 
-func (c *Client) buildSearchQuery(req models.FlightSearchRequest) map[string]interface{} {
+func (c *Client) buildSearchQuery(req models.FlightSearchRequest, window models.TimeRange) map[string]interface{} {
 	must := []map[string]interface{}{
 		{"term": map[string]interface{}{"origin": req.Origin}},
 		{"term": map[string]interface{}{"destination": req.Destination}},
 	}
 
-	// Date range query
-	if req.Date != "" {
-		startDate := req.Date + "T00:00:00Z"
-		endDate := req.Date + "T23:59:59Z"
-		
+	// Departure window, already resolved to UTC from the origin's local day
+	if !window.From.IsZero() && !window.To.IsZero() {
 		must = append(must, map[string]interface{}{
 			"range": map[string]interface{}{
 				"departure_time": map[string]interface{}{
-					"gte": startDate,
-					"lte": endDate,
+					"gte": window.From.UTC().Format(time.RFC3339),
+					"lt":  window.To.UTC().Format(time.RFC3339),
 				},
 			},
 		})
@@ -375,7 +467,10 @@ func (c *Client) buildSearchQuery(req models.FlightSearchRequest) map[string]int
 }
 
 // Hold-related methods
-func (c *Client) IndexHold(ctx context.Context, hold HoldDocument) error {
+func (c *Client) IndexHold(ctx context.Context, hold HoldDocument) (err error) {
+	ctx, span := startSpan(ctx, "IndexHold")
+	defer func() { tracing.End(span, err) }()
+
 	body, err := json.Marshal(hold)
 	if err != nil {
 		return fmt.Errorf("failed to marshal hold: %w", err)
@@ -402,7 +497,10 @@ func (c *Client) IndexHold(ctx context.Context, hold HoldDocument) error {
 	return nil
 }
 
-func (c *Client) UpdateHoldStatus(ctx context.Context, holdID int64, status string) error {
+func (c *Client) UpdateHoldStatus(ctx context.Context, holdID int64, status string) (err error) {
+	ctx, span := startSpan(ctx, "UpdateHoldStatus")
+	defer func() { tracing.End(span, err) }()
+
 	updateBody := map[string]interface{}{
 		"doc": map[string]interface{}{
 			"status":     status,
@@ -436,7 +534,10 @@ func (c *Client) UpdateHoldStatus(ctx context.Context, holdID int64, status stri
 	return nil
 }
 
-func (c *Client) DeleteHold(ctx context.Context, holdID int64) error {
+func (c *Client) DeleteHold(ctx context.Context, holdID int64) (err error) {
+	ctx, span := startSpan(ctx, "DeleteHold")
+	defer func() { tracing.End(span, err) }()
+
 	req := esapi.DeleteRequest{
 		Index:      HoldsIndex,
 		DocumentID: strconv.FormatInt(holdID, 10),
@@ -458,7 +559,10 @@ func (c *Client) DeleteHold(ctx context.Context, holdID int64) error {
 }
 
 // Ticket-related methods
-func (c *Client) IndexTicket(ctx context.Context, ticket TicketDocument) error {
+func (c *Client) IndexTicket(ctx context.Context, ticket TicketDocument) (err error) {
+	ctx, span := startSpan(ctx, "IndexTicket")
+	defer func() { tracing.End(span, err) }()
+
 	body, err := json.Marshal(ticket)
 	if err != nil {
 		return fmt.Errorf("failed to marshal ticket: %w", err)
@@ -485,7 +589,10 @@ func (c *Client) IndexTicket(ctx context.Context, ticket TicketDocument) error {
 	return nil
 }
 
-func (c *Client) UpdateTicketStatus(ctx context.Context, ticketID int64, status string) error {
+func (c *Client) UpdateTicketStatus(ctx context.Context, ticketID int64, status string) (err error) {
+	ctx, span := startSpan(ctx, "UpdateTicketStatus")
+	defer func() { tracing.End(span, err) }()
+
 	updateBody := map[string]interface{}{
 		"doc": map[string]interface{}{
 			"status": status,
@@ -518,8 +625,35 @@ func (c *Client) UpdateTicketStatus(ctx context.Context, ticketID int64, status
 	return nil
 }
 
+// ClusterHealth returns the cluster health status: green, yellow or red
+func (c *Client) ClusterHealth(ctx context.Context) (_ string, err error) {
+	ctx, span := startSpan(ctx, "ClusterHealth")
+	defer func() { tracing.End(span, err) }()
+
+	res, err := esapi.ClusterHealthRequest{}.Do(ctx, c.es)
+	if err != nil {
+		return "", fmt.Errorf("failed to get cluster health: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return "", fmt.Errorf("cluster health error: %s", res.String())
+	}
+
+	var health struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&health); err != nil {
+		return "", fmt.Errorf("failed to decode cluster health: %w", err)
+	}
+	return health.Status, nil
+}
+
 // Utility method to check document count
-func (c *Client) GetDocumentCount(ctx context.Context, index string) (int64, error) {
+func (c *Client) GetDocumentCount(ctx context.Context, index string) (_ int64, err error) {
+	ctx, span := startSpan(ctx, "GetDocumentCount")
+	defer func() { tracing.End(span, err) }()
+
 	req := esapi.CountRequest{
 		Index: []string{index},
 	}
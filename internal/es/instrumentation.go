@@ -0,0 +1,63 @@
+package es
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"airline-booking/internal/metrics"
+	"airline-booking/internal/tracing"
+)
+
+// instrumentedTransport times every Elasticsearch request, labelled with
+// the index and the API called. Client methods are traced by startSpan.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+
+	result := metrics.Result(err)
+	if err == nil && res.StatusCode >= 500 {
+		result = metrics.ResultError
+	}
+	index, operation := requestLabels(req)
+	metrics.ESRequestDuration.WithLabelValues(index, operation, result).Observe(metrics.Since(start))
+	return res, err
+}
+
+// requestLabels derives low-cardinality labels from a request path: the
+// index is the first segment not starting with an underscore and the
+// operation the first one that does, e.g. "/flights/_search" gives
+// ("flights", "_search") and "/holds/_doc/42" gives ("holds", "_doc").
+// Paths without an API segment are named by method, e.g. "HEAD /flights".
+func requestLabels(req *http.Request) (index, operation string) {
+	for _, segment := range strings.Split(strings.Trim(req.URL.Path, "/"), "/") {
+		switch {
+		case segment == "":
+		case strings.HasPrefix(segment, "_"):
+			if operation == "" {
+				operation = segment
+			}
+		case index == "" && operation == "":
+			index = segment
+		}
+	}
+	if operation == "" {
+		operation = req.Method
+	}
+	return index, operation
+}
+
+// startSpan starts the span of a Client method as a child of the span in ctx
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "elasticsearch "+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "elasticsearch")))
+}
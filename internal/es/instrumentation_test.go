@@ -0,0 +1,32 @@
+package es
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLabels(t *testing.T) {
+	tests := []struct {
+		method    string
+		path      string
+		index     string
+		operation string
+	}{
+		{"POST", "/flights/_search", "flights", "_search"},
+		{"PUT", "/holds/_doc/42", "holds", "_doc"},
+		{"POST", "/tickets/_update/7", "tickets", "_update"},
+		{"POST", "/_bulk", "", "_bulk"},
+		{"HEAD", "/flights", "flights", "HEAD"},
+		{"GET", "/", "", "GET"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			index, operation := requestLabels(httptest.NewRequest(tt.method, tt.path, nil))
+			assert.Equal(t, tt.index, index)
+			assert.Equal(t, tt.operation, operation)
+		})
+	}
+}
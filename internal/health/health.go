@@ -0,0 +1,209 @@
+// Package health checks the service's dependencies for the liveness and
+// readiness probes.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/es"
+	"airline-booking/internal/jobs"
+	"airline-booking/internal/models"
+)
+
+// checkTimeout bounds each dependency check, so a hung dependency can't
+// hold up the probe
+const checkTimeout = 2 * time.Second
+
+// schedulerStallAfter is how long the expired holds cleanup, scheduled
+// every minute, may go without running before the scheduler counts as down
+const schedulerStallAfter = 3 * time.Minute
+
+// Scheduler reports the state of the background job scheduler
+type Scheduler interface {
+	State() jobs.State
+}
+
+// check is one dependency check. Required checks make the service
+// unavailable when they fail; optional ones only degrade it.
+type check struct {
+	name     string
+	required bool
+	run      func(ctx context.Context) (details interface{}, err error)
+}
+
+type Checker struct {
+	checks  []check
+	started time.Time
+	logger  *zap.Logger
+}
+
+// NewChecker checks MySQL, Elasticsearch, the schema version against the
+// newest migration in migrationsDir and the cleanup job scheduler.
+// Elasticsearch only powers search indexing, so the service stays ready,
+// degraded, while it is down.
+func NewChecker(database *db.Database, esClient *es.Client, scheduler Scheduler, migrationsDir string, logger *zap.Logger) *Checker {
+	expected, err := db.LatestMigration(migrationsDir)
+	if err != nil {
+		logger.Warn("Readiness will not compare the schema version", zap.Error(err))
+	}
+
+	started := time.Now()
+	return &Checker{
+		checks: []check{
+			{name: "mysql", required: true, run: func(ctx context.Context) (interface{}, error) {
+				return nil, database.DB.PingContext(ctx)
+			}},
+			{name: "migrations", required: true, run: func(ctx context.Context) (interface{}, error) {
+				version, dirty, err := database.MigrationVersion(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return checkMigrations(version, dirty, expected)
+			}},
+			{name: "elasticsearch", required: false, run: func(ctx context.Context) (interface{}, error) {
+				status, err := esClient.ClusterHealth(ctx)
+				if err != nil {
+					return nil, err
+				}
+				details := map[string]string{"cluster_status": status}
+				if status == "red" {
+					return details, fmt.Errorf("cluster status is red")
+				}
+				return details, nil
+			}},
+			{name: "scheduler", required: true, run: func(ctx context.Context) (interface{}, error) {
+				return checkScheduler(scheduler.State(), started, time.Now())
+			}},
+		},
+		started: started,
+		logger:  logger,
+	}
+}
+
+// Live reports that the process is up and serving; it checks nothing else
+func (h *Checker) Live() *models.HealthResponse {
+	return &models.HealthResponse{
+		Status:    models.HealthStatusOK,
+		CheckedAt: time.Now().UTC(),
+		Uptime:    time.Since(h.started).Round(time.Second).String(),
+	}
+}
+
+// Ready runs every dependency check concurrently and reports each one's
+// status and latency
+func (h *Checker) Ready(ctx context.Context) *models.HealthResponse {
+	results := make(map[string]models.DependencyCheck, len(h.checks))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func(c check) {
+			defer wg.Done()
+			result := runCheck(ctx, c)
+			if result.Status == models.DependencyDown {
+				h.logger.Warn("Readiness check failed", zap.String("dependency", c.name), zap.String("error", result.Error))
+			}
+			mu.Lock()
+			results[c.name] = result
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	return &models.HealthResponse{
+		Status:    overallStatus(results),
+		Checks:    results,
+		CheckedAt: time.Now().UTC(),
+		Uptime:    time.Since(h.started).Round(time.Second).String(),
+	}
+}
+
+func runCheck(ctx context.Context, c check) models.DependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := c.run(ctx)
+	result := models.DependencyCheck{
+		Status:    models.DependencyUp,
+		Required:  c.required,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   details,
+	}
+	if err != nil {
+		result.Status = models.DependencyDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// overallStatus is unavailable when a required dependency is down and
+// degraded when only optional ones are
+func overallStatus(results map[string]models.DependencyCheck) models.HealthStatus {
+	status := models.HealthStatusOK
+	for _, result := range results {
+		if result.Status == models.DependencyUp {
+			continue
+		}
+		if result.Required {
+			return models.HealthStatusUnavailable
+		}
+		status = models.HealthStatusDegraded
+	}
+	return status
+}
+
+// checkMigrations fails when the last migration failed part way or the
+// schema is older than the code expects. A newer schema is fine: it means
+// a newer release migrated the database during a rollout. expected is 0
+// when the migrations directory could not be read.
+func checkMigrations(version uint, dirty bool, expected uint) (interface{}, error) {
+	details := map[string]interface{}{"version": version, "dirty": dirty}
+	if expected > 0 {
+		details["expected"] = expected
+	}
+	if dirty {
+		return details, fmt.Errorf("migration %d failed part way and needs fixing", version)
+	}
+	if version < expected {
+		return details, fmt.Errorf("schema version %d is behind %d", version, expected)
+	}
+	return details, nil
+}
+
+// checkScheduler fails when the scheduler is stopped or the expired holds
+// cleanup has not run for schedulerStallAfter. A failed last run is reported
+// but not counted: it fails because of a dependency, which has its own check.
+func checkScheduler(state jobs.State, started, now time.Time) (interface{}, error) {
+	details := map[string]interface{}{"running": state.Running}
+	if !state.LastRun.IsZero() {
+		details["last_run"] = state.LastRun.UTC()
+		details["last_duration_ms"] = state.LastDuration.Milliseconds()
+	}
+	if !state.NextRun.IsZero() {
+		details["next_run"] = state.NextRun.UTC()
+	}
+	if state.LastError != nil {
+		details["last_error"] = state.LastError.Error()
+	}
+
+	if !state.Running {
+		return details, fmt.Errorf("scheduler is not running")
+	}
+	lastRun := state.LastRun
+	if lastRun.IsZero() {
+		lastRun = started
+	}
+	if now.Sub(lastRun) > schedulerStallAfter {
+		return details, fmt.Errorf("expired holds cleanup has not run since %s", lastRun.UTC().Format(time.RFC3339))
+	}
+	return details, nil
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/jobs"
+	"airline-booking/internal/models"
+)
+
+func TestOverallStatus(t *testing.T) {
+	up := func(required bool) models.DependencyCheck {
+		return models.DependencyCheck{Status: models.DependencyUp, Required: required}
+	}
+	down := func(required bool) models.DependencyCheck {
+		return models.DependencyCheck{Status: models.DependencyDown, Required: required}
+	}
+
+	assert.Equal(t, models.HealthStatusOK, overallStatus(map[string]models.DependencyCheck{
+		"mysql": up(true), "elasticsearch": up(false),
+	}))
+	assert.Equal(t, models.HealthStatusDegraded, overallStatus(map[string]models.DependencyCheck{
+		"mysql": up(true), "elasticsearch": down(false),
+	}))
+	assert.Equal(t, models.HealthStatusUnavailable, overallStatus(map[string]models.DependencyCheck{
+		"mysql": down(true), "elasticsearch": down(false),
+	}))
+}
+
+func TestCheckMigrations(t *testing.T) {
+	_, err := checkMigrations(20, false, 20)
+	assert.NoError(t, err)
+
+	// a newer release may have migrated ahead during a rollout
+	_, err = checkMigrations(21, false, 20)
+	assert.NoError(t, err)
+
+	_, err = checkMigrations(19, false, 20)
+	assert.Error(t, err)
+
+	_, err = checkMigrations(20, true, 20)
+	assert.Error(t, err)
+
+	// without the migrations directory only dirty is checked
+	_, err = checkMigrations(3, false, 0)
+	assert.NoError(t, err)
+}
+
+func TestCheckScheduler(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-time.Hour)
+
+	_, err := checkScheduler(jobs.State{Running: true, LastRun: now.Add(-time.Minute)}, started, now)
+	assert.NoError(t, err)
+
+	// a failed run is reported but left to the dependency's own check
+	details, err := checkScheduler(jobs.State{Running: true, LastRun: now.Add(-time.Minute), LastError: errors.New("db down")}, started, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "db down", details.(map[string]interface{})["last_error"])
+
+	_, err = checkScheduler(jobs.State{Running: false, LastRun: now.Add(-time.Minute)}, started, now)
+	assert.Error(t, err)
+
+	_, err = checkScheduler(jobs.State{Running: true, LastRun: now.Add(-10 * time.Minute)}, started, now)
+	assert.Error(t, err)
+
+	// before the first run the grace period counts from startup
+	_, err = checkScheduler(jobs.State{Running: true}, now.Add(-time.Minute), now)
+	assert.NoError(t, err)
+	_, err = checkScheduler(jobs.State{Running: true}, started, now)
+	assert.Error(t, err)
+}
+
+func TestLatestMigrationMatchesRepo(t *testing.T) {
+	latest, err := db.LatestMigration("../../migrations")
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, latest, uint(20))
+
+	_, err = db.LatestMigration(t.TempDir())
+	assert.Error(t, err)
+}
@@ -2,11 +2,13 @@ package jobs
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 
+	"airline-booking/internal/metrics"
 	"airline-booking/internal/service"
 )
 
@@ -14,6 +16,24 @@ type CleanupJob struct {
 	bookingService *service.BookingService
 	logger         *zap.Logger
 	cron           *cron.Cron
+	
+	mu           sync.Mutex
+	running      bool
+	holdsEntry   cron.EntryID
+	lastRun      time.Time
+	lastDuration time.Duration
+	lastErr      error
+}
+
+// State is a snapshot of the scheduler for health checks
+type State struct {
+	Running bool
+	// LastRun is when the expired holds cleanup last finished, zero before
+	// its first run
+	LastRun      time.Time
+	LastDuration time.Duration
+	LastError    error
+	NextRun      time.Time
 }
 
 func NewCleanupJob(bookingService *service.BookingService, logger *zap.Logger) *CleanupJob {
@@ -31,7 +51,7 @@ func NewCleanupJob(bookingService *service.BookingService, logger *zap.Logger) *
 // Start begins the cleanup job that runs every minute
 func (j *CleanupJob) Start() error {
 	// Run every minute to cleanup expired holds
-	_, err := j.cron.AddFunc("0 * * * * *", j.cleanupExpiredHolds)
+	holdsEntry, err := j.cron.AddFunc("0 * * * * *", j.cleanupExpiredHolds)
 	if err != nil {
 		return err
 	}
@@ -43,6 +63,10 @@ func (j *CleanupJob) Start() error {
 	}
 	
 	j.cron.Start()
+	j.mu.Lock()
+	j.running = true
+	j.holdsEntry = holdsEntry
+	j.mu.Unlock()
 	j.logger.Info("Cleanup job started")
 	
 	return nil
@@ -52,10 +76,31 @@ func (j *CleanupJob) Start() error {
 func (j *CleanupJob) Stop() {
 	if j.cron != nil {
 		j.cron.Stop()
+		j.mu.Lock()
+		j.running = false
+		j.mu.Unlock()
 		j.logger.Info("Cleanup job stopped")
 	}
 }
 
+// State reports whether the scheduler is running and how the expired holds
+// cleanup last went
+func (j *CleanupJob) State() State {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	
+	state := State{
+		Running:      j.running,
+		LastRun:      j.lastRun,
+		LastDuration: j.lastDuration,
+		LastError:    j.lastErr,
+	}
+	if j.running {
+		state.NextRun = j.cron.Entry(j.holdsEntry).Next
+	}
+	return state
+}
+
 func (j *CleanupJob) cleanupExpiredHolds() {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -63,6 +108,13 @@ func (j *CleanupJob) cleanupExpiredHolds() {
 	start := time.Now()
 	err := j.bookingService.CleanupExpiredHolds(ctx)
 	duration := time.Since(start)
+	metrics.JobDuration.WithLabelValues("cleanup_expired_holds", metrics.Result(err)).Observe(duration.Seconds())
+	
+	j.mu.Lock()
+	j.lastRun = time.Now()
+	j.lastDuration = duration
+	j.lastErr = err
+	j.mu.Unlock()
 	
 	if err != nil {
 		j.logger.Error("Failed to cleanup expired holds",
@@ -79,6 +131,7 @@ func (j *CleanupJob) cleanupIdempotencyKeys() {
 	// TODO: Implement cleanup of old idempotency keys
 	// For now, we'll just log
 	duration := time.Since(start)
+	metrics.JobDuration.WithLabelValues("cleanup_idempotency_keys", metrics.ResultSuccess).Observe(duration.Seconds())
 	
 	j.logger.Debug("Cleaned up old idempotency keys",
 		zap.Duration("duration", duration))
@@ -0,0 +1,129 @@
+// Package metrics defines the Prometheus metrics exposed on /metrics: HTTP
+// traffic, booking activity, MySQL and Elasticsearch call latencies and
+// cleanup job runs. Collectors are registered with the default registry.
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "airline"
+
+// Result label values for calls and job runs
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	// HTTPRequests counts handled requests by route template, method and
+	// status code
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by route, method and status code.",
+	}, []string{"route", "method", "status"})
+	// HTTPRequestDuration observes request latencies by route template,
+	// method and status code
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency, by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	// HoldsCreated counts seat holds placed or extended
+	HoldsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "holds_created_total",
+		Help:      "Seat holds created or extended.",
+	})
+	// HoldConflicts counts hold attempts on a seat that is sold or held by
+	// someone else
+	HoldConflicts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "hold_conflicts_total",
+		Help:      "Hold attempts rejected because the seat is sold or held by another user.",
+	})
+	// HoldsExpired counts holds released by the cleanup job
+	HoldsExpired = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "holds_expired_total",
+		Help:      "Expired seat holds released by the cleanup job.",
+	})
+	// TicketsIssued counts tickets sold, by kind: seat or seatless
+	TicketsIssued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tickets_issued_total",
+		Help:      "Tickets issued, by kind (seat or seatless).",
+	}, []string{"kind"})
+	// Revenue sums the amounts charged for tickets and ancillaries, in minor
+	// units of the currency charged
+	Revenue = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "revenue_minor_units_total",
+		Help:      "Amount charged for tickets and ancillaries, in minor units, by currency.",
+	}, []string{"currency"})
+
+	// DBQueryDuration observes MySQL call latencies by query name
+	DBQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "mysql_query_duration_seconds",
+		Help:      "MySQL query latency, by query and result.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"query", "result"})
+	// ESRequestDuration observes Elasticsearch call latencies by index and
+	// operation
+	ESRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "elasticsearch_request_duration_seconds",
+		Help:      "Elasticsearch request latency, by index, operation and result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"index", "operation", "result"})
+	// ESCircuitOpen is 1 while the Elasticsearch circuit breaker fails
+	// requests fast
+	ESCircuitOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "elasticsearch_circuit_open",
+		Help:      "Whether the Elasticsearch circuit breaker is open (1) or closed (0).",
+	})
+	// SearchFallbacks counts flight searches served from MySQL because
+	// Elasticsearch failed
+	SearchFallbacks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "search_fallbacks_total",
+		Help:      "Flight searches served from MySQL because Elasticsearch was unavailable.",
+	})
+
+	// JobDuration observes cleanup job run times by job name
+	JobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Scheduled job run time, by job and result.",
+		Buckets:   []float64{.01, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"job", "result"})
+)
+
+// Result returns the result label for err
+func Result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// Since returns the seconds elapsed since start, for Observe
+func Since(start time.Time) float64 {
+	return time.Since(start).Seconds()
+}
+
+// RegisterDBStats exports the connection pool statistics of db, labelled
+// with the database name
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
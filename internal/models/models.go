@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -11,13 +12,30 @@ type Flight struct {
 	Destination   string    `json:"destination" db:"destination"`
 	DepartureTime time.Time `json:"departure_time" db:"departure_time"`
 	ArrivalTime   time.Time `json:"arrival_time" db:"arrival_time"`
+	DepartureTimeLocal *time.Time `json:"departure_time_local,omitempty" db:"departure_time_local"` // wall clock at origin
+	ArrivalTimeLocal   *time.Time `json:"arrival_time_local,omitempty" db:"arrival_time_local"`     // wall clock at destination
 	Airline       string    `json:"airline" db:"airline"`
 	Aircraft      string    `json:"aircraft" db:"aircraft"`
 	FareClass     string    `json:"fare_class" db:"fare_class"`
+	BasePrice     int64     `json:"base_price" db:"base_price"` // minor units of the base currency
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Airport represents an entry in the airports reference table
+type Airport struct {
+	IATACode   string    `json:"iata_code" db:"iata_code"`
+	Name       string    `json:"name" db:"name"`
+	City       string    `json:"city" db:"city"`
+	Country    string    `json:"country" db:"country"`   // ISO 3166-1 alpha-2
+	Timezone   string    `json:"timezone" db:"timezone"` // IANA time zone name
+	Latitude   float64   `json:"latitude" db:"latitude"`
+	Longitude  float64   `json:"longitude" db:"longitude"`
+	Popularity int       `json:"popularity" db:"popularity"` // annual passengers, thousands
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
 // Seat represents a seat in a flight
 type Seat struct {
 	ID        int64     `json:"id" db:"id"`
@@ -34,23 +52,256 @@ type SeatLock struct {
 	FlightID  int64      `json:"flight_id" db:"flight_id"`
 	SeatNo    string     `json:"seat_no" db:"seat_no"`
 	HolderID  string     `json:"holder_id" db:"holder_id"`
+	Status    HoldStatus `json:"status" db:"status"`
 	ExpiresAt *time.Time `json:"expires_at" db:"expires_at"`
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
 }
 
+// HoldStatus is the state of a seat lock: active -> payment_pending ->
+// confirmed, or expired/released when the hold ends without a ticket
+type HoldStatus string
+
+const (
+	HoldStatusActive         HoldStatus = "active"
+	HoldStatusPaymentPending HoldStatus = "payment_pending"
+	HoldStatusConfirmed      HoldStatus = "confirmed"
+	HoldStatusExpired        HoldStatus = "expired"
+	HoldStatusReleased       HoldStatus = "released"
+)
+
+// Live reports whether the lock still blocks the seat as a hold at now
+func (l SeatLock) Live(now time.Time) bool {
+	if l.Status != HoldStatusActive && l.Status != HoldStatusPaymentPending {
+		return false
+	}
+	return l.ExpiresAt != nil && l.ExpiresAt.After(now)
+}
+
 // Ticket represents a confirmed ticket
 type Ticket struct {
-	ID          int64     `json:"id" db:"id"`
-	FlightID    int64     `json:"flight_id" db:"flight_id"`
-	SeatNo      string    `json:"seat_no" db:"seat_no"`
-	UserID      string    `json:"user_id" db:"user_id"`
-	PriceAmount int64     `json:"price_amount" db:"price_amount"` // in cents
-	Currency    string    `json:"currency" db:"currency"`
-	IssuedAt    time.Time `json:"issued_at" db:"issued_at"`
-	PNRCode     string    `json:"pnr_code" db:"pnr_code"`
-	PaymentRef  string    `json:"payment_ref" db:"payment_ref"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID                        int64          `json:"id" db:"id"`
+	FlightID                  int64          `json:"flight_id" db:"flight_id"`
+	SeatNo                    string         `json:"seat_no" db:"seat_no"` // empty for seatless (overbooked) tickets until check-in
+	CabinClass                string         `json:"cabin_class" db:"cabin_class"`
+	UserID                    string         `json:"user_id" db:"user_id"`
+	PriceAmount               int64          `json:"price_amount" db:"price_amount"` // minor units of Currency
+	Currency                  string         `json:"currency" db:"currency"`
+	BasePriceAmount           int64          `json:"base_price_amount" db:"base_price_amount"` // total before conversion
+	BaseCurrency              string         `json:"base_currency" db:"base_currency"`
+	ExchangeRate              string         `json:"exchange_rate" db:"exchange_rate"` // Currency per BaseCurrency at issue
+	ExchangeRateEffectiveFrom *time.Time     `json:"exchange_rate_effective_from,omitempty" db:"exchange_rate_effective_from"`
+	IssuedAt                  time.Time      `json:"issued_at" db:"issued_at"`
+	PNRCode                   string         `json:"pnr_code" db:"pnr_code"`
+	PaymentRef                string         `json:"payment_ref" db:"payment_ref"`
+	PaymentAuthorizationID    string         `json:"payment_authorization_id,omitempty" db:"payment_authorization_id"`
+	PaymentStatus             string         `json:"payment_status" db:"payment_status"`
+	Status                    TicketStatus   `json:"status" db:"status"`
+	CancelledAt               *time.Time     `json:"cancelled_at,omitempty" db:"cancelled_at"`
+	CreatedAt                 time.Time      `json:"created_at" db:"created_at"`
+	LineItems                 []FareLineItem `json:"line_items,omitempty" db:"-"` // fare breakdown summing to PriceAmount
+}
+
+// TicketStatus represents the lifecycle state of a ticket
+type TicketStatus string
+
+const (
+	TicketStatusConfirmed TicketStatus = "confirmed"
+	TicketStatusCancelled TicketStatus = "cancelled"
+	// TicketStatusSuspended blocks a ticket whose payment is disputed
+	TicketStatusSuspended TicketStatus = "suspended"
+)
+
+// FareComponent classifies a line of a ticket's fare breakdown
+type FareComponent string
+
+const (
+	FareComponentBaseFare      FareComponent = "base_fare"
+	FareComponentAirportTax    FareComponent = "airport_tax"
+	FareComponentFuelSurcharge FareComponent = "fuel_surcharge"
+	FareComponentSeatFee       FareComponent = "seat_fee"
+	FareComponentServiceFee    FareComponent = "service_fee"
+	// FareComponentDiscount lines carry a negative amount
+	FareComponentDiscount FareComponent = "discount"
+	// FareComponentAncillary lines are one unit each of an extra, coded by
+	// the ancillary's code
+	FareComponentAncillary FareComponent = "ancillary"
+)
+
+// FareRule adds a tax or fee to tickets on matching routes. Empty
+// OriginCountry, DestinationCountry and CabinClass match anything.
+type FareRule struct {
+	ID                 int64         `json:"id" db:"id"`
+	Code               string        `json:"code" db:"code"`
+	Component          FareComponent `json:"component" db:"component"`
+	Description        string        `json:"description" db:"description"`
+	OriginCountry      string        `json:"origin_country,omitempty" db:"origin_country"`
+	DestinationCountry string        `json:"destination_country,omitempty" db:"destination_country"`
+	CabinClass         string        `json:"cabin_class,omitempty" db:"cabin_class"`
+	FixedAmount        int64         `json:"fixed_amount" db:"fixed_amount"` // minor units of the base currency
+	PercentBps         int           `json:"percent_bps" db:"percent_bps"`   // basis points of the base fare
+}
+
+// FareLineItem is one line of a ticket's fare breakdown
+type FareLineItem struct {
+	Component   FareComponent `json:"component" db:"component"`
+	Code        string        `json:"code" db:"code"`
+	Description string        `json:"description" db:"description"`
+	Amount      int64         `json:"amount" db:"amount"`           // minor units of the ticket currency
+	BaseAmount  int64         `json:"base_amount" db:"base_amount"` // minor units of the base currency
+}
+
+// AncillaryCategory groups the extras sold with a ticket
+type AncillaryCategory string
+
+const (
+	AncillaryCategoryCheckedBag       AncillaryCategory = "checked_bag"
+	AncillaryCategoryExtraLegroom     AncillaryCategory = "extra_legroom"
+	AncillaryCategoryMeal             AncillaryCategory = "meal"
+	AncillaryCategoryPriorityBoarding AncillaryCategory = "priority_boarding"
+	AncillaryCategoryLoungeAccess     AncillaryCategory = "lounge_access"
+)
+
+// Ancillary is an extra offered on the flights it matches. Nil FlightID and
+// empty Origin and Destination match any flight; a nil CapacityPerFlight
+// means unlimited.
+type Ancillary struct {
+	ID                int64             `json:"id" db:"id"`
+	Code              string            `json:"code" db:"code"`
+	Category          AncillaryCategory `json:"category" db:"category"`
+	Name              string            `json:"name" db:"name"`
+	PriceAmount       int64             `json:"price_amount" db:"price_amount"` // minor units of the base currency
+	FlightID          *int64            `json:"flight_id,omitempty" db:"flight_id"`
+	Origin            string            `json:"origin,omitempty" db:"origin"`
+	Destination       string            `json:"destination,omitempty" db:"destination"`
+	CapacityPerFlight *int              `json:"capacity_per_flight,omitempty" db:"capacity_per_flight"`
+	MaxPerTicket      int               `json:"max_per_ticket" db:"max_per_ticket"`
+	Sold              int               `json:"sold" db:"sold"` // units sold on the flight the ancillary was listed for
+}
+
+// Remaining returns the units still for sale, or nil if unlimited
+func (a Ancillary) Remaining() *int {
+	if a.CapacityPerFlight == nil {
+		return nil
+	}
+	remaining := *a.CapacityPerFlight - a.Sold
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// DocumentType is the kind of travel document presented at check-in
+type DocumentType string
+
+const (
+	DocumentTypePassport   DocumentType = "passport"
+	DocumentTypeNationalID DocumentType = "national_id" // accepted on domestic flights only
+)
+
+// CheckIn records that a ticket's passenger checked in, with the travel
+// document they presented and their boarding sequence number on the flight
+type CheckIn struct {
+	ID               int64        `json:"id" db:"id"`
+	TicketID         int64        `json:"ticket_id" db:"ticket_id"`
+	FlightID         int64        `json:"flight_id" db:"flight_id"`
+	SeatNo           string       `json:"seat_no" db:"seat_no"`
+	BoardingSequence int          `json:"boarding_sequence" db:"boarding_sequence"`
+	FirstName        string       `json:"first_name" db:"first_name"`
+	LastName         string       `json:"last_name" db:"last_name"`
+	DateOfBirth      time.Time    `json:"date_of_birth" db:"date_of_birth"`
+	Nationality      string       `json:"nationality" db:"nationality"` // ISO 3166-1 alpha-2
+	DocumentType     DocumentType `json:"document_type" db:"document_type"`
+	DocumentNumber   string       `json:"document_number" db:"document_number"`
+	DocumentCountry  string       `json:"document_country" db:"document_country"` // issuing country, ISO 3166-1 alpha-2
+	DocumentExpiry   time.Time    `json:"document_expiry" db:"document_expiry"`
+	CheckedInAt      time.Time    `json:"checked_in_at" db:"checked_in_at"`
+	BoardedAt        *time.Time   `json:"boarded_at,omitempty" db:"boarded_at"` // set when the pass is scanned at the gate
+}
+
+// PassengerStatus is where a ticketed passenger stands on the flight manifest
+type PassengerStatus string
+
+const (
+	PassengerStatusBooked    PassengerStatus = "booked" // not checked in, check-in still open
+	PassengerStatusCheckedIn PassengerStatus = "checked_in"
+	PassengerStatusBoarded   PassengerStatus = "boarded"
+	// PassengerStatusNoShow is a passenger who did not check in before
+	// check-in closed or did not board before departure
+	PassengerStatusNoShow PassengerStatus = "no_show"
+)
+
+// DiscountType says how a promotion's DiscountValue is applied
+type DiscountType string
+
+const (
+	// DiscountTypePercent takes DiscountValue basis points off the base fare
+	DiscountTypePercent DiscountType = "percent"
+	// DiscountTypeFixed takes DiscountValue minor units of the base currency
+	// off the base fare
+	DiscountTypeFixed DiscountType = "fixed"
+)
+
+// Promotion is a promo code. Empty or nil restrictions and limits mean the
+// promotion is unrestricted in that respect.
+type Promotion struct {
+	ID                    int64        `json:"id" db:"id"`
+	Code                  string       `json:"code" db:"code"`
+	Description           string       `json:"description" db:"description"`
+	DiscountType          DiscountType `json:"discount_type" db:"discount_type"`
+	DiscountValue         int64        `json:"discount_value" db:"discount_value"`
+	MaxDiscountAmount     *int64       `json:"max_discount_amount,omitempty" db:"max_discount_amount"`
+	Origin                string       `json:"origin,omitempty" db:"origin"`
+	Destination           string       `json:"destination,omitempty" db:"destination"`
+	Airline               string       `json:"airline,omitempty" db:"airline"`
+	FareClass             string       `json:"fare_class,omitempty" db:"fare_class"`
+	TravelFrom            *time.Time   `json:"travel_from,omitempty" db:"travel_from"`   // earliest departure
+	TravelUntil           *time.Time   `json:"travel_until,omitempty" db:"travel_until"` // latest departure
+	ValidFrom             time.Time    `json:"valid_from" db:"valid_from"`
+	ValidUntil            *time.Time   `json:"valid_until,omitempty" db:"valid_until"`
+	MaxRedemptions        *int         `json:"max_redemptions,omitempty" db:"max_redemptions"`
+	MaxRedemptionsPerUser *int         `json:"max_redemptions_per_user,omitempty" db:"max_redemptions_per_user"`
+	RedemptionCount       int          `json:"redemption_count" db:"redemption_count"`
+	Stackable             bool         `json:"stackable" db:"stackable"` // may be combined with other stackable codes
+	Active                bool         `json:"active" db:"active"`
+	CreatedAt             time.Time    `json:"created_at" db:"created_at"`
+}
+
+// FlightInventory holds the seat counters of one cabin on a flight
+type FlightInventory struct {
+	FlightID         int64     `json:"flight_id" db:"flight_id"`
+	CabinClass       string    `json:"cabin_class" db:"cabin_class"`
+	Capacity         int       `json:"capacity" db:"capacity"`
+	Held             int       `json:"held" db:"held"`
+	Sold             int       `json:"sold" db:"sold"`
+	Blocked          int       `json:"blocked" db:"blocked"`
+	OverbookingLimit int       `json:"overbooking_limit" db:"overbooking_limit"` // seats sellable beyond capacity
+	Oversold         int       `json:"oversold" db:"oversold"`                   // seatless tickets issued
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Available returns the number of physical seats that can still be held
+func (i FlightInventory) Available() int {
+	if n := i.Capacity - i.Held - i.Sold - i.Blocked; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// Shortfall returns how many ticketed passengers, counting active holds as
+// future sales, would be left without a seat
+func (i FlightInventory) Shortfall() int {
+	if n := i.Held + i.Sold + i.Blocked + i.Oversold - i.Capacity; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// CabinAvailability is the per-class availability shown to customers
+type CabinAvailability struct {
+	CabinClass string `json:"cabin_class"`
+	Capacity   int    `json:"capacity"`
+	Available  int    `json:"available"`
 }
 
 // IdempotencyKey represents an idempotency key record
@@ -77,86 +328,464 @@ type SeatAvailability struct {
 	Class     string     `json:"class"`
 	Status    SeatStatus `json:"status"`
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
-	Price     int64      `json:"price"` // in cents
+	Price     int64      `json:"price"` // minor units of Currency
+	Currency  string     `json:"currency"`
 }
 
 // FlightSearchResult represents a flight search result from Elasticsearch
 type FlightSearchResult struct {
-	ID            int64               `json:"id"`
-	Origin        string              `json:"origin"`
-	Destination   string              `json:"destination"`
-	DepartureTime time.Time           `json:"departure_time"`
-	ArrivalTime   time.Time           `json:"arrival_time"`
-	Airline       string              `json:"airline"`
-	Aircraft      string              `json:"aircraft"`
-	FareClass     string              `json:"fare_class"`
-	AvailableSeats int               `json:"available_seats"`
-	BasePrice     float64             `json:"base_price"`
+	ID                 int64               `json:"id"`
+	Origin             string              `json:"origin"`
+	Destination        string              `json:"destination"`
+	DepartureTime      time.Time           `json:"departure_time"`
+	ArrivalTime        time.Time           `json:"arrival_time"`
+	DepartureTimeLocal string              `json:"departure_time_local,omitempty"` // RFC3339 with origin offset
+	ArrivalTimeLocal   string              `json:"arrival_time_local,omitempty"`   // RFC3339 with destination offset
+	Airline            string              `json:"airline"`
+	Aircraft           string              `json:"aircraft"`
+	FareClass          string              `json:"fare_class"`
+	Availability       []CabinAvailability `json:"availability"`
+	BasePrice          int64               `json:"base_price"` // minor units of the base currency, e.g. 39999 for 399.99
+	Price              int64               `json:"price"`      // BasePrice in Currency
+	Currency           string              `json:"currency"`
+}
+
+// AirportSuggestion is a single autocomplete entry
+type AirportSuggestion struct {
+	IATACode string `json:"iata_code"`
+	Name     string `json:"name"`
+	City     string `json:"city"`
+	Country  string `json:"country"`
+}
+
+type AirportSuggestResponse struct {
+	Query       string              `json:"query"`
+	Suggestions []AirportSuggestion `json:"suggestions"`
+}
+
+type AirportSuggestRequest struct {
+	Query string `form:"q" binding:"required"`
+	Size  int    `form:"size,default=10"`
 }
 
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
-	Code    string      `json:"code"`
-	Message string      `json:"message"`
-	Details interface{} `json:"details,omitempty"`
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"` // quote it when reporting the error
+	TraceID   string      `json:"trace_id,omitempty"`
+}
+
+// FieldError is one invalid field of a request, listed in ErrorResponse.Details
+// with code VALIDATION_FAILED
+type FieldError struct {
+	Field   string `json:"field"` // JSON or query name, e.g. "seat_config.economy_rows"
+	Rule    string `json:"rule"`  // binding rule that failed, e.g. "iata_airport"
+	Message string `json:"message"`
 }
 
 // Hold-related DTOs
+// PromoCodes are validated against the flight and redeemed when the hold is
+// confirmed
 type CreateHoldRequest struct {
-	FlightID int64  `json:"flight_id" binding:"required"`
-	SeatNo   string `json:"seat_no" binding:"required"`
+	FlightID   int64    `json:"flight_id" binding:"required"`
+	SeatNo     string   `json:"seat_no" binding:"required,seat_no"`
+	PromoCodes []string `json:"promo_codes,omitempty"`
 }
 
 type CreateHoldResponse struct {
-	FlightID  int64     `json:"flight_id"`
-	SeatNo    string    `json:"seat_no"`
-	HolderID  string    `json:"holder_id"`
-	ExpiresAt time.Time `json:"expires_at"`
+	FlightID   int64     `json:"flight_id"`
+	SeatNo     string    `json:"seat_no"`
+	HolderID   string    `json:"holder_id"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	PromoCodes []string  `json:"promo_codes,omitempty"`
 }
 
 // Ticket confirmation DTOs
+// PaymentRef is the payment method token passed to the payment gateway.
+// ChallengeID is sent when retrying after completing a 3-D Secure challenge.
+// Currency selects the currency the ticket is sold in; it defaults to the
+// base currency.
 type ConfirmTicketRequest struct {
-	FlightID   int64  `json:"flight_id" binding:"required"`
-	SeatNo     string `json:"seat_no" binding:"required"`
-	PaymentRef string `json:"payment_ref" binding:"required"`
+	FlightID    int64                `json:"flight_id" binding:"required"`
+	SeatNo      string               `json:"seat_no" binding:"required,seat_no"`
+	PaymentRef  string               `json:"payment_ref" binding:"required"`
+	ChallengeID string               `json:"challenge_id,omitempty"`
+	Currency    string               `json:"currency,omitempty"`
+	Ancillaries []AncillarySelection `json:"ancillaries,omitempty" binding:"dive"`
 }
 
 type ConfirmTicketResponse struct {
-	TicketID   int64  `json:"ticket_id"`
-	FlightID   int64  `json:"flight_id"`
-	SeatNo     string `json:"seat_no"`
-	PNRCode    string `json:"pnr_code"`
-	PaymentRef string `json:"payment_ref"`
+	TicketID               int64          `json:"ticket_id"`
+	FlightID               int64          `json:"flight_id"`
+	SeatNo                 string         `json:"seat_no"`
+	CabinClass             string         `json:"cabin_class"`
+	PNRCode                string         `json:"pnr_code"`
+	PaymentRef             string         `json:"payment_ref"`
+	PaymentAuthorizationID string         `json:"payment_authorization_id"`
+	PaymentStatus          string         `json:"payment_status"`
+	PriceAmount            int64          `json:"price_amount"`
+	Currency               string         `json:"currency"`
+	BasePriceAmount        int64          `json:"base_price_amount"`
+	BaseCurrency           string         `json:"base_currency"`
+	ExchangeRate           string         `json:"exchange_rate"`
+	LineItems              []FareLineItem `json:"line_items"`
+}
+
+// Seatless (overbooked) ticket DTOs
+type ConfirmSeatlessTicketRequest struct {
+	FlightID    int64                `json:"flight_id" binding:"required"`
+	CabinClass  string               `json:"cabin_class" binding:"required"`
+	PaymentRef  string               `json:"payment_ref" binding:"required"`
+	ChallengeID string               `json:"challenge_id,omitempty"`
+	Currency    string               `json:"currency,omitempty"`
+	PromoCodes  []string             `json:"promo_codes,omitempty"` // seatless tickets have no hold to apply codes to
+	Ancillaries []AncillarySelection `json:"ancillaries,omitempty" binding:"dive"`
+}
+
+// Ancillary DTOs
+type AncillarySelection struct {
+	Code     string `json:"code" binding:"required"`
+	Quantity int    `json:"quantity,omitempty" binding:"omitempty,min=1"` // defaults to 1
+}
+
+type PurchaseAncillariesRequest struct {
+	Ancillaries []AncillarySelection `json:"ancillaries" binding:"required,min=1,dive"`
+	PaymentRef  string               `json:"payment_ref" binding:"required"`
+	ChallengeID string               `json:"challenge_id,omitempty"`
+}
+
+// AncillaryOffer is an ancillary as listed for sale on a flight
+type AncillaryOffer struct {
+	Code         string            `json:"code"`
+	Category     AncillaryCategory `json:"category"`
+	Name         string            `json:"name"`
+	Price        int64             `json:"price"` // minor units of Currency, per unit
+	Currency     string            `json:"currency"`
+	MaxPerTicket int               `json:"max_per_ticket"`
+	Remaining    *int              `json:"remaining,omitempty"` // omitted when unlimited
+}
+
+type FlightAncillariesResponse struct {
+	FlightID    int64            `json:"flight_id"`
+	Ancillaries []AncillaryOffer `json:"ancillaries"`
+}
+
+// Check-in DTOs
+
+// CheckInRequest carries the passenger's travel document. Dates are
+// YYYY-MM-DD and countries ISO 3166-1 alpha-2 codes. SeatNo moves a seated
+// ticket to another free seat in its cabin; a seatless ticket is given the
+// seat it names, or the first free seat in its cabin if it names none.
+type CheckInRequest struct {
+	FirstName   string         `json:"first_name" binding:"required"`
+	LastName    string         `json:"last_name" binding:"required"`
+	DateOfBirth string         `json:"date_of_birth" binding:"required"`
+	Nationality string         `json:"nationality" binding:"required"`
+	Document    TravelDocument `json:"document" binding:"required"`
+	SeatNo      string         `json:"seat_no,omitempty" binding:"omitempty,seat_no"`
+}
+
+type TravelDocument struct {
+	Type           DocumentType `json:"type" binding:"required"`
+	Number         string       `json:"number" binding:"required"`
+	IssuingCountry string       `json:"issuing_country" binding:"required"`
+	ExpiryDate     string       `json:"expiry_date" binding:"required"`
+}
+
+// BoardingPass is a checked-in ticket's boarding pass. Times are wall clock
+// at the origin airport; BCBP is the IATA Resolution 792 barcode data.
+type BoardingPass struct {
+	PNRCode          string    `json:"pnr_code"`
+	FirstName        string    `json:"first_name"`
+	LastName         string    `json:"last_name"`
+	FlightID         int64     `json:"flight_id"`
+	Airline          string    `json:"airline"`
+	FlightNumber     string    `json:"flight_number"`
+	Origin           string    `json:"origin"`
+	Destination      string    `json:"destination"`
+	DepartureTime    time.Time `json:"departure_time"`
+	BoardingTime     time.Time `json:"boarding_time"`
+	CabinClass       string    `json:"cabin_class"`
+	SeatNo           string    `json:"seat_no"`
+	BoardingSequence int       `json:"boarding_sequence"`
+	BoardingGroup    string    `json:"boarding_group"`
+	BCBP             string    `json:"bcbp"`
+	CheckedInAt      time.Time `json:"checked_in_at"`
+}
+
+// Boarding DTOs
+type ScanBoardingPassRequest struct {
+	Barcode string `json:"barcode" binding:"required"` // BCBP data read from the boarding pass
+}
+
+type BoardingScanResponse struct {
+	TicketID         int64     `json:"ticket_id"`
+	PNRCode          string    `json:"pnr_code"`
+	FlightID         int64     `json:"flight_id"`
+	FirstName        string    `json:"first_name"`
+	LastName         string    `json:"last_name"`
+	CabinClass       string    `json:"cabin_class"`
+	SeatNo           string    `json:"seat_no"`
+	BoardingSequence int       `json:"boarding_sequence"`
+	BoardedAt        time.Time `json:"boarded_at"`
+}
+
+// ManifestPassenger is a confirmed ticket on a flight manifest. Passenger
+// names are only known once the ticket has checked in.
+type ManifestPassenger struct {
+	TicketID         int64           `json:"ticket_id"`
+	PNRCode          string          `json:"pnr_code"`
+	FirstName        string          `json:"first_name,omitempty"`
+	LastName         string          `json:"last_name,omitempty"`
+	CabinClass       string          `json:"cabin_class"`
+	SeatNo           string          `json:"seat_no,omitempty"`
+	BoardingSequence int             `json:"boarding_sequence,omitempty"`
+	Status           PassengerStatus `json:"status"`
+	CheckedInAt      *time.Time      `json:"checked_in_at,omitempty"`
+	BoardedAt        *time.Time      `json:"boarded_at,omitempty"`
+}
+
+type FlightManifest struct {
+	FlightID      int64                   `json:"flight_id"`
+	Origin        string                  `json:"origin"`
+	Destination   string                  `json:"destination"`
+	DepartureTime time.Time               `json:"departure_time"`
+	Totals        map[PassengerStatus]int `json:"totals"`
+	Passengers    []ManifestPassenger     `json:"passengers"`
+}
+
+// Promotion admin DTOs
+type CreatePromotionRequest struct {
+	Code                  string       `json:"code" binding:"required,max=40"`
+	Description           string       `json:"description" binding:"required,max=255"`
+	DiscountType          DiscountType `json:"discount_type" binding:"required,oneof=percent fixed"`
+	DiscountValue         int64        `json:"discount_value" binding:"required,min=1"`
+	MaxDiscountAmount     *int64       `json:"max_discount_amount,omitempty" binding:"omitempty,min=1"`
+	Origin                string       `json:"origin,omitempty" binding:"omitempty,len=3"`
+	Destination           string       `json:"destination,omitempty" binding:"omitempty,len=3"`
+	Airline               string       `json:"airline,omitempty"`
+	FareClass             string       `json:"fare_class,omitempty"`
+	TravelFrom            *time.Time   `json:"travel_from,omitempty"`
+	TravelUntil           *time.Time   `json:"travel_until,omitempty"`
+	ValidFrom             *time.Time   `json:"valid_from,omitempty"` // defaults to now
+	ValidUntil            *time.Time   `json:"valid_until,omitempty"`
+	MaxRedemptions        *int         `json:"max_redemptions,omitempty" binding:"omitempty,min=1"`
+	MaxRedemptionsPerUser *int         `json:"max_redemptions_per_user,omitempty" binding:"omitempty,min=1"`
+	Stackable             bool         `json:"stackable"`
+}
+
+type PromotionsResponse struct {
+	Promotions []Promotion `json:"promotions"`
+}
+
+// ExchangeRate is the rate, in units of QuoteCurrency per unit of
+// BaseCurrency, in effect from EffectiveFrom until the pair's next rate
+type ExchangeRate struct {
+	ID            int64     `json:"id,omitempty" db:"id"`
+	BaseCurrency  string    `json:"base_currency" db:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency" db:"quote_currency"`
+	Rate          string    `json:"rate" db:"rate"` // decimal string, kept exact
+	EffectiveFrom time.Time `json:"effective_from" db:"effective_from"`
+	Source        string    `json:"source,omitempty" db:"source"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// Exchange rate admin DTOs
+// EffectiveFrom defaults to now; a future time schedules the rate.
+type ExchangeRateInput struct {
+	QuoteCurrency string      `json:"quote_currency" binding:"required,len=3"`
+	Rate          json.Number `json:"rate" binding:"required" swaggertype:"number"`
+	EffectiveFrom *time.Time  `json:"effective_from,omitempty"`
+}
+
+type SetExchangeRatesRequest struct {
+	Rates []ExchangeRateInput `json:"rates" binding:"required,min=1,dive"`
+}
+
+type ExchangeRatesResponse struct {
+	BaseCurrency string         `json:"base_currency"`
+	Rates        []ExchangeRate `json:"rates"`
+}
+
+// PaymentWebhookEvent is a stored payment provider notification
+type PaymentWebhookEvent struct {
+	ID              int64      `json:"id" db:"id"`
+	Provider        string     `json:"provider" db:"provider"`
+	EventID         string     `json:"event_id" db:"event_id"`
+	EventType       string     `json:"event_type" db:"event_type"`
+	AuthorizationID string     `json:"authorization_id,omitempty" db:"authorization_id"`
+	Payload         []byte     `json:"-" db:"payload"`
+	ReceivedAt      time.Time  `json:"received_at" db:"received_at"`
+	ProcessedAt     *time.Time `json:"processed_at,omitempty" db:"processed_at"`
+	ProcessingError string     `json:"processing_error,omitempty" db:"processing_error"`
+}
+
+type PaymentWebhookResponse struct {
+	EventID   string `json:"event_id"`
+	Duplicate bool   `json:"duplicate"`
+	Processed bool   `json:"processed"`
+}
+
+// AuditAction names a booking state change recorded in the audit log
+type AuditAction string
+
+const (
+	AuditActionHoldCreated        AuditAction = "hold.created"
+	AuditActionHoldReleased       AuditAction = "hold.released"
+	AuditActionHoldPaymentPending AuditAction = "hold.payment_pending"
+	AuditActionHoldReverted       AuditAction = "hold.reverted"
+	AuditActionHoldExpired        AuditAction = "hold.expired"
+	AuditActionTicketIssued       AuditAction = "ticket.issued"
+	AuditActionTicketCancelled    AuditAction = "ticket.cancelled"
+	AuditActionAncillariesAdded   AuditAction = "ticket.ancillaries_added"
+	AuditActionSeatChanged        AuditAction = "ticket.seat_changed"
+	AuditActionPaymentUpdated     AuditAction = "ticket.payment_updated"
+	AuditActionTicketSuspended    AuditAction = "ticket.suspended"
+	AuditActionTicketReinstated   AuditAction = "ticket.reinstated"
+)
+
+// AuditActorSystem is the actor of changes made by background jobs
+const AuditActorSystem = "system"
+
+// AuditEvent is an append-only record of who changed a booking, how and
+// when. Before and After are JSON snapshots of the hold or ticket, null
+// when it did not exist.
+type AuditEvent struct {
+	ID         int64           `json:"id" db:"id"`
+	OccurredAt time.Time       `json:"occurred_at" db:"occurred_at"`
+	Actor      string          `json:"actor" db:"actor"`
+	Action     AuditAction     `json:"action" db:"action"`
+	FlightID   int64           `json:"flight_id,omitempty" db:"flight_id"`
+	SeatNo     string          `json:"seat_no,omitempty" db:"seat_no"`
+	PNRCode    string          `json:"pnr_code,omitempty" db:"pnr_code"`
+	Before     json.RawMessage `json:"before,omitempty" db:"before_state" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" db:"after_state" swaggertype:"object"`
+	RequestID  string          `json:"request_id,omitempty" db:"request_id"`
+	IPAddress  string          `json:"ip_address,omitempty" db:"ip_address"`
+}
+
+// AuditEventFilter selects audit events; zero fields match everything.
+// From is inclusive and To exclusive.
+type AuditEventFilter struct {
+	PNRCode  string    `form:"pnr"`
+	UserID   string    `form:"user"`
+	FlightID int64     `form:"flight_id"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit    int       `form:"limit,default=100" binding:"min=1,max=1000"`
+}
+
+// AuditEventsResponse lists audit events, newest first
+type AuditEventsResponse struct {
+	Events []AuditEvent `json:"events"`
+	Count  int          `json:"count"`
+}
+
+// Overbooking admin DTOs
+type SetOverbookingLimitRequest struct {
+	Limit *int `json:"limit" binding:"required,min=0"`
+}
+
+type FlightAtRisk struct {
+	FlightID         int64     `json:"flight_id"`
+	Origin           string    `json:"origin"`
+	Destination      string    `json:"destination"`
+	Airline          string    `json:"airline"`
+	DepartureTime    time.Time `json:"departure_time"`
+	CabinClass       string    `json:"cabin_class"`
+	Capacity         int       `json:"capacity"`
+	Held             int       `json:"held"`
+	Sold             int       `json:"sold"`
+	Blocked          int       `json:"blocked"`
+	Oversold         int       `json:"oversold"`
+	OverbookingLimit int       `json:"overbooking_limit"`
+	Shortfall        int       `json:"shortfall"`
+}
+
+type FlightsAtRiskResponse struct {
+	Flights []FlightAtRisk `json:"flights"`
+}
+
+type DeniedBoardingVolunteer struct {
+	Rank        int       `json:"rank"`
+	TicketID    int64     `json:"ticket_id"`
+	PNRCode     string    `json:"pnr_code"`
+	UserID      string    `json:"user_id"`
+	CabinClass  string    `json:"cabin_class"`
+	SeatNo      string    `json:"seat_no,omitempty"`
+	PriceAmount int64     `json:"price_amount"` // in cents
+	Currency    string    `json:"currency"`
+	BookedAt    time.Time `json:"booked_at"`
+}
+
+type DeniedBoardingListResponse struct {
+	FlightID   int64                     `json:"flight_id"`
+	CabinClass string                    `json:"cabin_class,omitempty"`
+	Shortfall  int                       `json:"shortfall"`
+	Volunteers []DeniedBoardingVolunteer `json:"volunteers"`
+}
+
+// Ticket cancellation DTOs
+type CancelTicketResponse struct {
+	TicketID      int64        `json:"ticket_id"`
+	PNRCode       string       `json:"pnr_code"`
+	Status        TicketStatus `json:"status"`
+	PaymentStatus string       `json:"payment_status"`
+	CancelledAt   time.Time    `json:"cancelled_at"`
+}
+
+// Flight availability DTOs
+type FlightAvailabilityResponse struct {
+	FlightID     int64               `json:"flight_id"`
+	Availability []CabinAvailability `json:"availability"`
 }
 
 // Flight search DTOs
 type FlightSearchRequest struct {
-	Origin      string `form:"origin" binding:"required"`
-	Destination string `form:"destination" binding:"required"`
-	Date        string `form:"date" binding:"required"` // YYYY-MM-DD format
-	FareClass   string `form:"fare_class"`
-	Airline     string `form:"airline"`
+	Origin      string `form:"origin" binding:"required,iata_airport"`
+	Destination string `form:"destination" binding:"required,iata_airport"`
+	Date        string `form:"date" binding:"required"` // YYYY-MM-DD format, local to the origin airport
+	FareClass   string `form:"fare_class" binding:"omitempty,fare_class"`
+	Airline     string `form:"airline" binding:"omitempty,iata_airline"`
+	Currency    string `form:"currency"` // prices are shown in the base currency when empty
 	Page        int    `form:"page,default=1"`
 	Size        int    `form:"size,default=10"`
 }
 
+// TimeRange is a half-open [From, To) interval in UTC
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
 type FlightSearchResponse struct {
 	Flights []FlightSearchResult `json:"flights"`
 	Total   int64                `json:"total"`
 	Page    int                  `json:"page"`
 	Size    int                  `json:"size"`
+	// Source is "elasticsearch", or "database" when Elasticsearch was
+	// unavailable and the search fell back to MySQL
+	Source string `json:"source"`
 }
 
+// Flight search sources
+const (
+	SearchSourceElasticsearch = "elasticsearch"
+	SearchSourceDatabase      = "database"
+)
+
 // Flight creation DTOs
 type CreateFlightRequest struct {
-	Origin        string  `json:"origin" binding:"required"`
-	Destination   string  `json:"destination" binding:"required"`
-	DepartureTime string  `json:"departure_time" binding:"required"` // RFC3339 format
-	ArrivalTime   string  `json:"arrival_time" binding:"required"`   // RFC3339 format
-	Airline       string  `json:"airline" binding:"required"`
+	Origin        string  `json:"origin" binding:"required,iata_airport"`
+	Destination   string  `json:"destination" binding:"required,iata_airport,nefield=Origin"`
+	DepartureTime string  `json:"departure_time" binding:"required"` // RFC3339, or local to origin when no offset is given
+	ArrivalTime   string  `json:"arrival_time" binding:"required"`   // RFC3339, or local to destination when no offset is given
+	Airline       string  `json:"airline" binding:"required,iata_airline"`
 	Aircraft      string  `json:"aircraft" binding:"required"`
-	FareClass     string  `json:"fare_class" binding:"required"`
-	BasePrice     float64 `json:"base_price" binding:"required"`
+	FareClass     string  `json:"fare_class" binding:"required,fare_class"`
+	BasePrice     float64 `json:"base_price" binding:"required,gt=0"` // decimal amount in the base currency, e.g. 399.99
 	SeatConfig    *SeatConfiguration `json:"seat_config,omitempty"` // Optional seat configuration
 }
 
@@ -173,10 +802,49 @@ type CreateFlightResponse struct {
 	Destination   string             `json:"destination"`
 	DepartureTime string             `json:"departure_time"`
 	ArrivalTime   string             `json:"arrival_time"`
+	DepartureTimeLocal string        `json:"departure_time_local"`
+	ArrivalTimeLocal   string        `json:"arrival_time_local"`
 	Airline       string             `json:"airline"`
 	Aircraft      string             `json:"aircraft"`
 	FareClass     string             `json:"fare_class"`
-	BasePrice     float64            `json:"base_price"`
+	BasePrice     float64            `json:"base_price"` // decimal amount in the base currency, as sent
 	SeatsCreated  int                `json:"seats_created"`
 	CreatedAt     string             `json:"created_at"`
 }
+
+// Health DTOs
+
+// HealthStatus is the overall state reported by the health endpoints
+type HealthStatus string
+
+const (
+	HealthStatusOK HealthStatus = "ok"
+	// HealthStatusDegraded serves traffic with an optional dependency down
+	HealthStatusDegraded HealthStatus = "degraded"
+	// HealthStatusUnavailable must not receive traffic
+	HealthStatusUnavailable HealthStatus = "unavailable"
+)
+
+// DependencyStatus is the state of one dependency in a readiness check
+type DependencyStatus string
+
+const (
+	DependencyUp   DependencyStatus = "up"
+	DependencyDown DependencyStatus = "down"
+)
+
+type HealthResponse struct {
+	Status    HealthStatus               `json:"status"`
+	Checks    map[string]DependencyCheck `json:"checks,omitempty"`
+	CheckedAt time.Time                  `json:"checked_at"`
+	Uptime    string                     `json:"uptime,omitempty"`
+}
+
+// DependencyCheck is the outcome of checking one dependency
+type DependencyCheck struct {
+	Status    DependencyStatus `json:"status"`
+	Required  bool             `json:"required"` // whether the pod is unready while it is down
+	LatencyMS float64          `json:"latency_ms"`
+	Error     string           `json:"error,omitempty"`
+	Details   interface{}      `json:"details,omitempty"`
+}
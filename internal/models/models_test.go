@@ -63,3 +63,56 @@ func TestConfirmTicketRequest(t *testing.T) {
 		t.Error("PaymentRef should not be empty")
 	}
 }
+
+func TestFlightInventoryAvailable(t *testing.T) {
+	inventory := FlightInventory{Capacity: 20, Held: 3, Sold: 12, Blocked: 2}
+	if got := inventory.Available(); got != 3 {
+		t.Errorf("expected 3 available seats, got %d", got)
+	}
+
+	oversold := FlightInventory{Capacity: 10, Held: 2, Sold: 10}
+	if got := oversold.Available(); got != 0 {
+		t.Errorf("expected no available seats when counters exceed capacity, got %d", got)
+	}
+}
+
+func TestFlightInventoryShortfall(t *testing.T) {
+	inventory := FlightInventory{Capacity: 10, Held: 1, Sold: 9, Oversold: 2, OverbookingLimit: 3}
+	if got := inventory.Shortfall(); got != 2 {
+		t.Errorf("expected a shortfall of 2, got %d", got)
+	}
+	if got := inventory.Available(); got != 0 {
+		t.Errorf("expected no physical seats available, got %d", got)
+	}
+
+	inventory.Sold = 5
+	if got := inventory.Shortfall(); got != 0 {
+		t.Errorf("expected no shortfall with free seats, got %d", got)
+	}
+}
+
+func TestSeatLockLive(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Minute)
+	past := now.Add(-time.Minute)
+
+	tests := []struct {
+		status    HoldStatus
+		expiresAt *time.Time
+		want      bool
+	}{
+		{HoldStatusActive, &future, true},
+		{HoldStatusActive, &past, false},
+		{HoldStatusPaymentPending, &future, true},
+		{HoldStatusPaymentPending, &past, false},
+		{HoldStatusConfirmed, nil, false},
+		{HoldStatusExpired, &future, false},
+		{HoldStatusReleased, nil, false},
+	}
+	for _, tt := range tests {
+		lock := SeatLock{Status: tt.status, ExpiresAt: tt.expiresAt}
+		if got := lock.Live(now); got != tt.want {
+			t.Errorf("%s expiring %v: expected live=%v, got %v", tt.status, tt.expiresAt, tt.want, got)
+		}
+	}
+}
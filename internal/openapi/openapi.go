@@ -0,0 +1,103 @@
+// Package openapi holds the API's OpenAPI 3 document, generated from the
+// handlers' swag annotations, and the Swagger UI page that renders it.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// spec is regenerated with `make openapi` whenever a route or its
+// annotations change
+//
+//go:embed openapi.json
+var spec []byte
+
+// Spec returns the OpenAPI 3 document as served at /openapi.json
+func Spec() []byte {
+	return spec
+}
+
+// Load parses and validates the OpenAPI 3 document
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("validating OpenAPI document: %w", err)
+	}
+	return doc, nil
+}
+
+// FromSwagger2 converts the Swagger 2.0 document swag generates into the
+// OpenAPI 3 document this package embeds
+func FromSwagger2(data []byte) ([]byte, error) {
+	var doc2 openapi2.T
+	if err := json.Unmarshal(data, &doc2); err != nil {
+		return nil, fmt.Errorf("parsing Swagger 2.0 document: %w", err)
+	}
+
+	doc3, err := openapi2conv.ToV3(&doc2)
+	if err != nil {
+		return nil, fmt.Errorf("converting to OpenAPI 3: %w", err)
+	}
+	binaryFileResponses(doc3)
+	if err := doc3.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("validating OpenAPI document: %w", err)
+	}
+
+	out, err := json.MarshalIndent(doc3, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// binaryFileResponses rewrites Swagger 2.0 "file" responses, such as the
+// passenger manifest download, as OpenAPI 3 binary strings. The converter
+// only does this for request bodies.
+func binaryFileResponses(doc *openapi3.T) {
+	for _, pathItem := range doc.Paths.Map() {
+		for _, operation := range pathItem.Operations() {
+			for _, response := range operation.Responses.Map() {
+				if response.Value == nil {
+					continue
+				}
+				for _, mediaType := range response.Value.Content {
+					if schema := mediaType.Schema; schema != nil && schema.Value != nil && schema.Value.Type == "file" {
+						schema.Value.Type = openapi3.TypeString
+						schema.Value.Format = "binary"
+					}
+				}
+			}
+		}
+	}
+}
+
+// UIPage is a Swagger UI page for the document at /openapi.json. The UI's
+// assets are loaded from a CDN, so the binary doesn't carry them.
+const UIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Airline Booking API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5.11.0/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
@@ -0,0 +1,277 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Behavior is how the fake gateway answers an authorization for a payment
+// method
+type Behavior string
+
+const (
+	BehaviorApprove           Behavior = "approve"
+	BehaviorDecline           Behavior = "decline"
+	BehaviorInsufficientFunds Behavior = "insufficient_funds"
+	BehaviorTimeout           Behavior = "timeout"
+	BehaviorChallenge         Behavior = "challenge"
+	// BehaviorCaptureFailure authorizes but times out on capture
+	BehaviorCaptureFailure Behavior = "capture_failure"
+)
+
+// DefaultFakeBehaviors maps the test payment method tokens understood by the
+// fake gateway. Any other token is approved.
+var DefaultFakeBehaviors = map[string]Behavior{
+	"tok_visa":               BehaviorApprove,
+	"tok_decline":            BehaviorDecline,
+	"tok_insufficient_funds": BehaviorInsufficientFunds,
+	"tok_timeout":            BehaviorTimeout,
+	"tok_3ds":                BehaviorChallenge,
+	"tok_capture_failure":    BehaviorCaptureFailure,
+}
+
+// FakeConfig configures the in-process fake gateway
+type FakeConfig struct {
+	// Behaviors overrides DefaultFakeBehaviors per payment method token
+	Behaviors map[string]Behavior
+	// Latency is added to every call
+	Latency time.Duration
+	// Timeout is how long a timing-out call blocks before returning
+	// ErrTimeout, unless the context ends first
+	Timeout time.Duration
+}
+
+// FakeGateway is an in-memory PaymentGateway for development and tests
+type FakeGateway struct {
+	config FakeConfig
+
+	mu             sync.Mutex
+	authorizations map[string]*fakeAuthorization
+	byReference    map[string]string
+	challenges     map[string]bool // issued challenge IDs
+}
+
+type fakeAuthorization struct {
+	Authorization
+	behavior Behavior
+}
+
+func NewFakeGateway(config FakeConfig) *FakeGateway {
+	behaviors := make(map[string]Behavior, len(DefaultFakeBehaviors)+len(config.Behaviors))
+	for token, behavior := range DefaultFakeBehaviors {
+		behaviors[token] = behavior
+	}
+	for token, behavior := range config.Behaviors {
+		behaviors[token] = behavior
+	}
+	config.Behaviors = behaviors
+	if config.Timeout == 0 {
+		config.Timeout = 5 * time.Second
+	}
+
+	return &FakeGateway{
+		config:         config,
+		authorizations: make(map[string]*fakeAuthorization),
+		byReference:    make(map[string]string),
+		challenges:     make(map[string]bool),
+	}
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	behavior, ok := g.config.Behaviors[req.PaymentMethod]
+	if !ok {
+		behavior = BehaviorApprove
+	}
+
+	switch behavior {
+	case BehaviorDecline:
+		return nil, &DeclineError{Code: "card_declined", Message: "The card was declined"}
+	case BehaviorInsufficientFunds:
+		return nil, &DeclineError{Code: "insufficient_funds", Message: "The card has insufficient funds"}
+	case BehaviorTimeout:
+		return nil, g.timeout(ctx)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Retries with the same reference return the original authorization
+	if id, ok := g.byReference[req.Reference]; ok && req.Reference != "" {
+		auth := g.authorizations[id].Authorization
+		return &auth, nil
+	}
+
+	if behavior == BehaviorChallenge && !g.challenges[req.ChallengeID] {
+		challengeID := "3ds_" + randomID()
+		g.challenges[challengeID] = true
+		return nil, &ChallengeError{
+			ChallengeID: challengeID,
+			RedirectURL: fmt.Sprintf("https://fake-gateway.local/3ds/%s", challengeID),
+		}
+	}
+
+	auth := &fakeAuthorization{
+		Authorization: Authorization{
+			ID:        "auth_" + randomID(),
+			Status:    StatusAuthorized,
+			Amount:    req.Amount,
+			Currency:  req.Currency,
+			Reference: req.Reference,
+			CreatedAt: time.Now().UTC(),
+		},
+		behavior: behavior,
+	}
+	g.authorizations[auth.ID] = auth
+	if req.Reference != "" {
+		g.byReference[req.Reference] = auth.ID
+	}
+
+	result := auth.Authorization
+	return &result, nil
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, authorizationID string, amount int64) (*Authorization, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	auth, ok := g.authorizations[authorizationID]
+	g.mu.Unlock()
+	if !ok {
+		return nil, ErrAuthorizationNotFound
+	}
+	if auth.behavior == BehaviorCaptureFailure {
+		return nil, g.timeout(ctx)
+	}
+
+	return g.transition(authorizationID, func(a *Authorization) error {
+		if a.Status == StatusCaptured && a.CapturedAmount == amount {
+			return nil
+		}
+		if a.Status != StatusAuthorized {
+			return fmt.Errorf("%w: cannot capture %s authorization", ErrInvalidState, a.Status)
+		}
+		if amount <= 0 || amount > a.Amount {
+			return fmt.Errorf("%w: capture amount %d exceeds authorized %d", ErrInvalidState, amount, a.Amount)
+		}
+		a.Status = StatusCaptured
+		a.CapturedAmount = amount
+		return nil
+	})
+}
+
+func (g *FakeGateway) Void(ctx context.Context, authorizationID string) (*Authorization, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return g.transition(authorizationID, func(a *Authorization) error {
+		switch a.Status {
+		case StatusVoided:
+			return nil
+		case StatusAuthorized:
+			a.Status = StatusVoided
+			return nil
+		}
+		return fmt.Errorf("%w: cannot void %s authorization", ErrInvalidState, a.Status)
+	})
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, authorizationID string, amount int64) (*Authorization, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	return g.transition(authorizationID, func(a *Authorization) error {
+		if a.Status != StatusCaptured && a.Status != StatusRefunded {
+			return fmt.Errorf("%w: cannot refund %s authorization", ErrInvalidState, a.Status)
+		}
+		if amount <= 0 || a.RefundedAmount+amount > a.CapturedAmount {
+			return fmt.Errorf("%w: refund of %d exceeds captured %d", ErrInvalidState, amount, a.CapturedAmount-a.RefundedAmount)
+		}
+		a.RefundedAmount += amount
+		if a.RefundedAmount == a.CapturedAmount {
+			a.Status = StatusRefunded
+		}
+		return nil
+	})
+}
+
+func (g *FakeGateway) Get(ctx context.Context, authorizationID string) (*Authorization, error) {
+	if err := g.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	auth, ok := g.Lookup(authorizationID)
+	if !ok {
+		return nil, ErrAuthorizationNotFound
+	}
+	return auth, nil
+}
+
+// Lookup returns the current state of an authorization, for tests
+func (g *FakeGateway) Lookup(authorizationID string) (*Authorization, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	auth, ok := g.authorizations[authorizationID]
+	if !ok {
+		return nil, false
+	}
+	result := auth.Authorization
+	return &result, true
+}
+
+func (g *FakeGateway) transition(authorizationID string, apply func(*Authorization) error) (*Authorization, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	auth, ok := g.authorizations[authorizationID]
+	if !ok {
+		return nil, ErrAuthorizationNotFound
+	}
+	if err := apply(&auth.Authorization); err != nil {
+		return nil, err
+	}
+
+	result := auth.Authorization
+	return &result, nil
+}
+
+func (g *FakeGateway) wait(ctx context.Context) error {
+	if g.config.Latency == 0 {
+		if ctx.Err() != nil {
+			return ErrTimeout
+		}
+		return nil
+	}
+	select {
+	case <-time.After(g.config.Latency):
+		return nil
+	case <-ctx.Done():
+		return ErrTimeout
+	}
+}
+
+func (g *FakeGateway) timeout(ctx context.Context) error {
+	select {
+	case <-time.After(g.config.Timeout):
+	case <-ctx.Done():
+	}
+	return ErrTimeout
+}
+
+func randomID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
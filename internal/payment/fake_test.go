@@ -0,0 +1,161 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeGatewayAuthorizeCapture(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{})
+	ctx := context.Background()
+
+	auth, err := gateway.Authorize(ctx, AuthorizeRequest{Amount: 29900, Currency: "USD", PaymentMethod: "tok_visa", Reference: "ref-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth.Status != StatusAuthorized {
+		t.Fatalf("expected authorized, got %s", auth.Status)
+	}
+
+	again, err := gateway.Authorize(ctx, AuthorizeRequest{Amount: 29900, Currency: "USD", PaymentMethod: "tok_visa", Reference: "ref-1"})
+	if err != nil || again.ID != auth.ID {
+		t.Fatalf("expected retry with the same reference to return %s, got %+v (%v)", auth.ID, again, err)
+	}
+
+	captured, err := gateway.Capture(ctx, auth.ID, 29900)
+	if err != nil {
+		t.Fatalf("unexpected capture error: %v", err)
+	}
+	if captured.Status != StatusCaptured || captured.CapturedAmount != 29900 {
+		t.Errorf("unexpected capture result: %+v", captured)
+	}
+
+	if _, err := gateway.Void(ctx, auth.ID); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("expected voiding a captured payment to fail, got %v", err)
+	}
+
+	refunded, err := gateway.Refund(ctx, auth.ID, 10000)
+	if err != nil {
+		t.Fatalf("unexpected refund error: %v", err)
+	}
+	if refunded.Status != StatusCaptured || refunded.RefundedAmount != 10000 {
+		t.Errorf("expected partial refund, got %+v", refunded)
+	}
+	refunded, err = gateway.Refund(ctx, auth.ID, 19900)
+	if err != nil || refunded.Status != StatusRefunded {
+		t.Errorf("expected full refund, got %+v (%v)", refunded, err)
+	}
+	if _, err := gateway.Refund(ctx, auth.ID, 1); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("expected over-refund to fail, got %v", err)
+	}
+}
+
+func TestFakeGatewayVoid(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{})
+	ctx := context.Background()
+
+	auth, err := gateway.Authorize(ctx, AuthorizeRequest{Amount: 5000, Currency: "USD", PaymentMethod: "tok_visa"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := gateway.Void(ctx, auth.ID); err != nil {
+		t.Fatalf("unexpected void error: %v", err)
+	}
+	if _, err := gateway.Capture(ctx, auth.ID, 5000); !errors.Is(err, ErrInvalidState) {
+		t.Errorf("expected capturing a voided authorization to fail, got %v", err)
+	}
+	if current, _ := gateway.Lookup(auth.ID); current.Status != StatusVoided {
+		t.Errorf("expected voided, got %s", current.Status)
+	}
+}
+
+func TestFakeGatewayDeclines(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{Behaviors: map[string]Behavior{"tok_custom": BehaviorDecline}})
+
+	tests := map[string]string{
+		"tok_decline":            "card_declined",
+		"tok_insufficient_funds": "insufficient_funds",
+		"tok_custom":             "card_declined",
+	}
+	for token, code := range tests {
+		_, err := gateway.Authorize(context.Background(), AuthorizeRequest{Amount: 100, Currency: "USD", PaymentMethod: token})
+		var decline *DeclineError
+		if !errors.As(err, &decline) || !errors.Is(err, ErrDeclined) {
+			t.Errorf("%s: expected a decline, got %v", token, err)
+			continue
+		}
+		if decline.Code != code {
+			t.Errorf("%s: expected code %s, got %s", token, code, decline.Code)
+		}
+	}
+}
+
+func TestFakeGatewayTimeout(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{Timeout: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := gateway.Authorize(ctx, AuthorizeRequest{Amount: 100, Currency: "USD", PaymentMethod: "tok_timeout"})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the context deadline to cut the call short, took %s", elapsed)
+	}
+}
+
+func TestFakeGatewayCaptureFailure(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{Timeout: time.Millisecond})
+	ctx := context.Background()
+
+	auth, err := gateway.Authorize(ctx, AuthorizeRequest{Amount: 100, Currency: "USD", PaymentMethod: "tok_capture_failure"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := gateway.Capture(ctx, auth.ID, 100); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected capture timeout, got %v", err)
+	}
+	if _, err := gateway.Void(ctx, auth.ID); err != nil {
+		t.Errorf("expected the uncaptured authorization to be voidable, got %v", err)
+	}
+}
+
+func TestFakeGateway3DSChallenge(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{})
+	ctx := context.Background()
+	req := AuthorizeRequest{Amount: 100, Currency: "USD", PaymentMethod: "tok_3ds", Reference: "ref-3ds"}
+
+	_, err := gateway.Authorize(ctx, req)
+	var challenge *ChallengeError
+	if !errors.As(err, &challenge) {
+		t.Fatalf("expected a challenge, got %v", err)
+	}
+	if challenge.RedirectURL == "" {
+		t.Error("expected a redirect URL")
+	}
+
+	req.ChallengeID = "3ds_unknown"
+	if _, err := gateway.Authorize(ctx, req); !errors.Is(err, ErrChallengeRequired) {
+		t.Errorf("expected an unknown challenge to be rejected, got %v", err)
+	}
+
+	req.ChallengeID = challenge.ChallengeID
+	auth, err := gateway.Authorize(ctx, req)
+	if err != nil {
+		t.Fatalf("expected authorization after the challenge, got %v", err)
+	}
+	if auth.Status != StatusAuthorized {
+		t.Errorf("expected authorized, got %s", auth.Status)
+	}
+}
+
+func TestFakeGatewayUnknownAuthorization(t *testing.T) {
+	gateway := NewFakeGateway(FakeConfig{})
+	if _, err := gateway.Capture(context.Background(), "auth_missing", 100); !errors.Is(err, ErrAuthorizationNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Package payment abstracts the card payment provider behind a two-step
+// authorize/capture flow.
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"airline-booking/internal/config"
+)
+
+var (
+	// ErrDeclined is wrapped by DeclineError
+	ErrDeclined = errors.New("payment declined")
+	// ErrChallengeRequired is wrapped by ChallengeError
+	ErrChallengeRequired = errors.New("3-D Secure challenge required")
+	// ErrTimeout is returned when the provider did not answer in time. The
+	// outcome of the operation is unknown.
+	ErrTimeout = errors.New("payment provider timeout")
+	// ErrAuthorizationNotFound is returned for unknown authorization IDs
+	ErrAuthorizationNotFound = errors.New("authorization not found")
+	// ErrInvalidState is returned when an operation does not apply to the
+	// authorization's current status, e.g. capturing a voided authorization
+	ErrInvalidState = errors.New("invalid authorization state")
+)
+
+// Status is the lifecycle state of an authorization
+type Status string
+
+const (
+	StatusAuthorized Status = "authorized"
+	StatusCaptured   Status = "captured"
+	StatusVoided     Status = "voided"
+	StatusRefunded   Status = "refunded"
+	// StatusChargeback is reported by webhooks when the cardholder disputes
+	// a captured payment
+	StatusChargeback Status = "chargeback"
+)
+
+// AuthorizeRequest reserves funds on a payment method
+type AuthorizeRequest struct {
+	Amount        int64  // in minor units
+	Currency      string // ISO 4217
+	PaymentMethod string // provider token for the card
+	Reference     string // merchant reference, also used as idempotency key
+	CustomerID    string
+	// ChallengeID is set when retrying after the customer completed the
+	// 3-D Secure challenge returned by a previous attempt
+	ChallengeID string
+}
+
+// Authorization is a reservation of funds that can later be captured or voided
+type Authorization struct {
+	ID             string
+	Status         Status
+	Amount         int64
+	Currency       string
+	Reference      string
+	CapturedAmount int64
+	RefundedAmount int64
+	CreatedAt      time.Time
+}
+
+// PaymentGateway is implemented by payment providers
+type PaymentGateway interface {
+	// Authorize reserves req.Amount. It returns a *DeclineError or a
+	// *ChallengeError when the payment cannot be authorized as is.
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error)
+	// Capture settles amount, which must not exceed the authorized amount
+	Capture(ctx context.Context, authorizationID string, amount int64) (*Authorization, error)
+	// Void releases an authorization that has not been captured
+	Void(ctx context.Context, authorizationID string) (*Authorization, error)
+	// Refund returns amount of a captured payment to the customer
+	Refund(ctx context.Context, authorizationID string, amount int64) (*Authorization, error)
+	// Get returns the current state of an authorization
+	Get(ctx context.Context, authorizationID string) (*Authorization, error)
+}
+
+// NewGateway returns the gateway selected by cfg.Provider
+func NewGateway(cfg *config.PaymentConfig) (PaymentGateway, error) {
+	switch cfg.Provider {
+	case "fake":
+		return NewFakeGateway(FakeConfig{}), nil
+	}
+	return nil, fmt.Errorf("unknown payment provider %q", cfg.Provider)
+}
+
+// DeclineError reports a payment refused by the issuer or provider
+type DeclineError struct {
+	Code    string // e.g. "insufficient_funds", "card_declined"
+	Message string
+}
+
+func (e *DeclineError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDeclined, e.Code)
+}
+
+func (e *DeclineError) Unwrap() error {
+	return ErrDeclined
+}
+
+// ChallengeError asks the customer to complete a 3-D Secure challenge at
+// RedirectURL and retry with ChallengeID
+type ChallengeError struct {
+	ChallengeID string
+	RedirectURL string
+}
+
+func (e *ChallengeError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrChallengeRequired, e.ChallengeID)
+}
+
+func (e *ChallengeError) Unwrap() error {
+	return ErrChallengeRequired
+}
@@ -0,0 +1,110 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader carries the webhook signature in the form
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">"
+const SignatureHeader = "X-Payment-Signature"
+
+var (
+	// ErrInvalidSignature is returned when a webhook signature is missing,
+	// malformed, does not match or is outside the allowed clock skew
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrInvalidEvent is returned when a signed payload is not a valid event
+	ErrInvalidEvent = errors.New("invalid webhook event")
+)
+
+// EventType is the kind of asynchronous payment result
+type EventType string
+
+const (
+	EventCaptured           EventType = "payment.captured"
+	EventVoided             EventType = "payment.voided"
+	EventRefunded           EventType = "payment.refunded"
+	EventChargeback         EventType = "payment.chargeback"
+	EventChargebackReversed EventType = "payment.chargeback_reversed"
+)
+
+// WebhookEvent is an asynchronous notification from the payment provider
+type WebhookEvent struct {
+	ID              string    `json:"id"`
+	Type            EventType `json:"type"`
+	AuthorizationID string    `json:"authorization_id"`
+	Amount          int64     `json:"amount"`
+	Currency        string    `json:"currency"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// SignWebhook returns the signature header value for payload signed at t
+func SignWebhook(payload []byte, secret string, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, webhookMAC(payload, secret, timestamp))
+}
+
+// VerifyWebhook checks the signature header against payload and rejects
+// signatures older or newer than tolerance relative to now
+func VerifyWebhook(payload []byte, header, secret string, tolerance time.Duration, now time.Time) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing timestamp or signature", ErrInvalidSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+	}
+	if skew := now.Sub(time.Unix(seconds, 0)); skew > tolerance || skew < -tolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+	}
+
+	expected := webhookMAC(payload, secret, timestamp)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+}
+
+// ParseWebhookEvent decodes a verified webhook payload
+func ParseWebhookEvent(payload []byte) (*WebhookEvent, error) {
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+	if event.ID == "" || event.Type == "" {
+		return nil, fmt.Errorf("%w: id and type are required", ErrInvalidEvent)
+	}
+	return &event, nil
+}
+
+func webhookMAC(payload []byte, secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,52 @@
+package payment
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyWebhook(t *testing.T) {
+	payload := []byte(`{"id":"evt_1","type":"payment.chargeback","authorization_id":"auth_1"}`)
+	now := time.Unix(1700000000, 0)
+	header := SignWebhook(payload, "whsec_test", now)
+
+	if err := VerifyWebhook(payload, header, "whsec_test", 5*time.Minute, now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected a valid signature, got %v", err)
+	}
+
+	tests := map[string]struct {
+		payload []byte
+		header  string
+		secret  string
+		now     time.Time
+	}{
+		"tampered payload": {payload: []byte(`{"id":"evt_2"}`), header: header, secret: "whsec_test", now: now},
+		"wrong secret":     {payload: payload, header: header, secret: "whsec_other", now: now},
+		"expired":          {payload: payload, header: header, secret: "whsec_test", now: now.Add(10 * time.Minute)},
+		"missing header":   {payload: payload, header: "", secret: "whsec_test", now: now},
+		"malformed":        {payload: payload, header: "t=abc,v1=00", secret: "whsec_test", now: now},
+	}
+	for name, tc := range tests {
+		err := VerifyWebhook(tc.payload, tc.header, tc.secret, 5*time.Minute, tc.now)
+		if !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: expected ErrInvalidSignature, got %v", name, err)
+		}
+	}
+}
+
+func TestParseWebhookEvent(t *testing.T) {
+	event, err := ParseWebhookEvent([]byte(`{"id":"evt_1","type":"payment.refunded","authorization_id":"auth_1","amount":29900,"currency":"USD"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Type != EventRefunded || event.AuthorizationID != "auth_1" || event.Amount != 29900 {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	for _, payload := range []string{`not json`, `{"type":"payment.refunded"}`, `{"id":"evt_1"}`} {
+		if _, err := ParseWebhookEvent([]byte(payload)); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("%s: expected ErrInvalidEvent, got %v", payload, err)
+		}
+	}
+}
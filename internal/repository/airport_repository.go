@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+type AirportRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewAirportRepository(database *db.Database, logger *zap.Logger) *AirportRepository {
+	return &AirportRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// GetAirport retrieves an airport by IATA code, returning nil if it is unknown
+func (r *AirportRepository) GetAirport(ctx context.Context, iataCode string) (*models.Airport, error) {
+	airport, err := r.db.Queries.GetAirport(ctx, strings.ToUpper(iataCode))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get airport: %w", err)
+	}
+
+	result := toAirportModel(airport)
+	return &result, nil
+}
+
+// ListAirports retrieves every airport in the reference table
+func (r *AirportRepository) ListAirports(ctx context.Context) ([]models.Airport, error) {
+	airports, err := r.db.Queries.ListAirports(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list airports: %w", err)
+	}
+
+	result := make([]models.Airport, len(airports))
+	for i, airport := range airports {
+		result[i] = toAirportModel(airport)
+	}
+
+	return result, nil
+}
+
+// UpsertAirport inserts an airport or refreshes it if the IATA code exists
+func (r *AirportRepository) UpsertAirport(ctx context.Context, airport models.Airport) error {
+	err := r.db.Queries.UpsertAirport(ctx, db.UpsertAirportParams{
+		IataCode:   strings.ToUpper(airport.IATACode),
+		Name:       airport.Name,
+		City:       airport.City,
+		Country:    airport.Country,
+		Timezone:   airport.Timezone,
+		Latitude:   airport.Latitude,
+		Longitude:  airport.Longitude,
+		Popularity: int32(airport.Popularity),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert airport %s: %w", airport.IATACode, err)
+	}
+
+	return nil
+}
+
+func toAirportModel(airport db.Airport) models.Airport {
+	return models.Airport{
+		IATACode:   airport.IataCode,
+		Name:       airport.Name,
+		City:       airport.City,
+		Country:    airport.Country,
+		Timezone:   airport.Timezone,
+		Latitude:   airport.Latitude,
+		Longitude:  airport.Longitude,
+		Popularity: int(airport.Popularity),
+		CreatedAt:  airport.CreatedAt,
+		UpdatedAt:  airport.UpdatedAt,
+	}
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// AncillaryRepository reads the ancillaries catalog and tracks the units
+// sold per flight
+type AncillaryRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewAncillaryRepository(database *db.Database, logger *zap.Logger) *AncillaryRepository {
+	return &AncillaryRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// ListForFlight returns the active ancillaries offered on flight, each with
+// the units it has sold on that flight
+func (r *AncillaryRepository) ListForFlight(ctx context.Context, flight *models.Flight) ([]models.Ancillary, error) {
+	rows, err := r.db.Queries.ListFlightAncillaries(ctx, db.ListFlightAncillariesParams{
+		FlightID:    flight.ID,
+		Origin:      flight.Origin,
+		Destination: flight.Destination,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ancillaries: %w", err)
+	}
+
+	result := make([]models.Ancillary, len(rows))
+	for i, row := range rows {
+		result[i] = models.Ancillary{
+			ID:           row.ID,
+			Code:         row.Code,
+			Category:     models.AncillaryCategory(row.Category),
+			Name:         row.Name,
+			PriceAmount:  row.PriceAmount,
+			Origin:       row.Origin.String,
+			Destination:  row.Destination.String,
+			MaxPerTicket: int(row.MaxPerTicket),
+			Sold:         int(row.Sold),
+		}
+		if row.FlightID.Valid {
+			flightID := row.FlightID.Int64
+			result[i].FlightID = &flightID
+		}
+		if row.CapacityPerFlight.Valid {
+			capacity := int(row.CapacityPerFlight.Int32)
+			result[i].CapacityPerFlight = &capacity
+		}
+	}
+	return result, nil
+}
+
+// ReserveUnits sells quantity units of a limited ancillary on a flight
+// inside tx. It reports false, changing nothing, if that would exceed the
+// ancillary's capacity.
+func (r *AncillaryRepository) ReserveUnits(ctx context.Context, tx *sql.Tx, flightID, ancillaryID int64, quantity int) (bool, error) {
+	queries := r.db.WithTx(tx)
+
+	err := queries.EnsureAncillaryInventory(ctx, db.AncillaryInventoryParams{
+		FlightID:    flightID,
+		AncillaryID: ancillaryID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to create ancillary inventory: %w", err)
+	}
+
+	rowsAffected, err := queries.ReserveAncillaryUnits(ctx, db.ReserveAncillaryUnitsParams{
+		Quantity:    int32(quantity),
+		FlightID:    flightID,
+		AncillaryID: ancillaryID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve ancillary units: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ReleaseTicketUnits returns the ancillary units on a ticket to the flight's
+// inventory inside tx
+func (r *AncillaryRepository) ReleaseTicketUnits(ctx context.Context, tx *sql.Tx, ticketID, flightID int64) error {
+	err := r.db.WithTx(tx).ReleaseTicketAncillaryUnits(ctx, db.ReleaseTicketAncillaryUnitsParams{
+		TicketID: ticketID,
+		FlightID: flightID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release ancillary units: %w", err)
+	}
+	return nil
+}
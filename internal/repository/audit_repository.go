@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// AuditRepository appends to and reads the audit log. It has no update or
+// delete: events are written once, inside the transaction of the change
+// they record, and kept.
+type AuditRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewAuditRepository(database *db.Database, logger *zap.Logger) *AuditRepository {
+	return &AuditRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// Record appends an event inside tx, so it commits or rolls back with the
+// change it describes
+func (r *AuditRepository) Record(ctx context.Context, tx *sql.Tx, event models.AuditEvent) error {
+	err := r.db.WithTx(tx).CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		Actor:       event.Actor,
+		Action:      string(event.Action),
+		FlightID:    sql.NullInt64{Int64: event.FlightID, Valid: event.FlightID != 0},
+		SeatNo:      sql.NullString{String: event.SeatNo, Valid: event.SeatNo != ""},
+		PnrCode:     sql.NullString{String: event.PNRCode, Valid: event.PNRCode != ""},
+		BeforeState: event.Before,
+		AfterState:  event.After,
+		RequestID:   sql.NullString{String: event.RequestID, Valid: event.RequestID != ""},
+		IpAddress:   sql.NullString{String: event.IPAddress, Valid: event.IPAddress != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record audit event: %w", err)
+	}
+	return nil
+}
+
+// ListEvents returns the events matching filter, newest first
+func (r *AuditRepository) ListEvents(ctx context.Context, filter models.AuditEventFilter) ([]models.AuditEvent, error) {
+	params := db.ListAuditEventsParams{
+		PnrCode:  filter.PNRCode,
+		Actor:    filter.UserID,
+		FlightID: filter.FlightID,
+		Limit:    int32(filter.Limit),
+	}
+	if !filter.From.IsZero() {
+		params.From = sql.NullTime{Time: filter.From.UTC(), Valid: true}
+	}
+	if !filter.To.IsZero() {
+		params.To = sql.NullTime{Time: filter.To.UTC(), Valid: true}
+	}
+
+	events, err := r.db.Queries.ListAuditEvents(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	result := make([]models.AuditEvent, len(events))
+	for i, event := range events {
+		result[i] = toAuditEventModel(event)
+	}
+	return result, nil
+}
+
+func toAuditEventModel(event db.AuditEvent) models.AuditEvent {
+	return models.AuditEvent{
+		ID:         event.ID,
+		OccurredAt: event.OccurredAt,
+		Actor:      event.Actor,
+		Action:     models.AuditAction(event.Action),
+		FlightID:   event.FlightID.Int64,
+		SeatNo:     event.SeatNo.String,
+		PNRCode:    event.PnrCode.String,
+		Before:     event.BeforeState,
+		After:      event.AfterState,
+		RequestID:  event.RequestID.String,
+		IPAddress:  event.IpAddress.String,
+	}
+}
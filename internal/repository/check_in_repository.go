@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// CheckInRepository stores completed check-ins and numbers them per flight
+type CheckInRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewCheckInRepository(database *db.Database, logger *zap.Logger) *CheckInRepository {
+	return &CheckInRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// NextBoardingSequence returns the sequence number the next passenger to
+// check in for a flight gets. The flight stays locked until tx ends.
+func (r *CheckInRepository) NextBoardingSequence(ctx context.Context, tx *sql.Tx, flightID int64) (int, error) {
+	sequence, err := r.db.WithTx(tx).NextBoardingSequence(ctx, flightID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get boarding sequence: %w", err)
+	}
+	return int(sequence), nil
+}
+
+// CreateCheckIn records a check-in inside tx
+func (r *CheckInRepository) CreateCheckIn(ctx context.Context, tx *sql.Tx, checkIn models.CheckIn) (*models.CheckIn, error) {
+	queries := r.db.WithTx(tx)
+
+	_, err := queries.CreateCheckIn(ctx, db.CreateCheckInParams{
+		TicketID:         checkIn.TicketID,
+		FlightID:         checkIn.FlightID,
+		SeatNo:           checkIn.SeatNo,
+		BoardingSequence: int32(checkIn.BoardingSequence),
+		FirstName:        checkIn.FirstName,
+		LastName:         checkIn.LastName,
+		DateOfBirth:      checkIn.DateOfBirth,
+		Nationality:      checkIn.Nationality,
+		DocumentType:     string(checkIn.DocumentType),
+		DocumentNumber:   checkIn.DocumentNumber,
+		DocumentCountry:  checkIn.DocumentCountry,
+		DocumentExpiry:   checkIn.DocumentExpiry,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create check-in: %w", err)
+	}
+
+	created, err := queries.GetCheckInByTicket(ctx, checkIn.TicketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created check-in: %w", err)
+	}
+	result := toCheckInModel(created)
+	return &result, nil
+}
+
+// GetCheckInByTicket returns a ticket's check-in, or nil if it has not
+// checked in. tx may be nil.
+func (r *CheckInRepository) GetCheckInByTicket(ctx context.Context, tx *sql.Tx, ticketID int64) (*models.CheckIn, error) {
+	queries := r.db.Queries
+	if tx != nil {
+		queries = r.db.WithTx(tx)
+	}
+
+	checkIn, err := queries.GetCheckInByTicket(ctx, ticketID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get check-in: %w", err)
+	}
+
+	result := toCheckInModel(checkIn)
+	return &result, nil
+}
+
+// ListFlightCheckIns returns the check-ins of a flight in boarding sequence
+// order
+func (r *CheckInRepository) ListFlightCheckIns(ctx context.Context, flightID int64) ([]models.CheckIn, error) {
+	checkIns, err := r.db.Queries.ListFlightCheckIns(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list check-ins: %w", err)
+	}
+
+	result := make([]models.CheckIn, len(checkIns))
+	for i, checkIn := range checkIns {
+		result[i] = toCheckInModel(checkIn)
+	}
+	return result, nil
+}
+
+// MarkBoarded records that a checked-in ticket boarded at boardedAt. It
+// reports false, changing nothing, if the ticket had already boarded.
+func (r *CheckInRepository) MarkBoarded(ctx context.Context, ticketID int64, boardedAt time.Time) (bool, error) {
+	rowsAffected, err := r.db.Queries.MarkCheckInBoarded(ctx, db.MarkCheckInBoardedParams{
+		BoardedAt: boardedAt,
+		TicketID:  ticketID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark check-in boarded: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+func toCheckInModel(checkIn db.CheckIn) models.CheckIn {
+	return models.CheckIn{
+		ID:               checkIn.ID,
+		TicketID:         checkIn.TicketID,
+		FlightID:         checkIn.FlightID,
+		SeatNo:           checkIn.SeatNo,
+		BoardingSequence: int(checkIn.BoardingSequence),
+		FirstName:        checkIn.FirstName,
+		LastName:         checkIn.LastName,
+		DateOfBirth:      checkIn.DateOfBirth,
+		Nationality:      checkIn.Nationality,
+		DocumentType:     models.DocumentType(checkIn.DocumentType),
+		DocumentNumber:   checkIn.DocumentNumber,
+		DocumentCountry:  checkIn.DocumentCountry,
+		DocumentExpiry:   checkIn.DocumentExpiry,
+		CheckedInAt:      checkIn.CheckedInAt,
+		BoardedAt:        checkIn.BoardedAt,
+	}
+}
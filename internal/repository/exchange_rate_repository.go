@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// ExchangeRateRepository stores the dated exchange rate table used to price
+// fares in currencies other than the base currency
+type ExchangeRateRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewExchangeRateRepository(database *db.Database, logger *zap.Logger) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// UpsertRate stores a rate inside tx, replacing the rate already scheduled
+// for the same pair and effective time
+func (r *ExchangeRateRepository) UpsertRate(ctx context.Context, tx *sql.Tx, rate models.ExchangeRate) error {
+	err := r.db.WithTx(tx).UpsertExchangeRate(ctx, db.UpsertExchangeRateParams{
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		EffectiveFrom: rate.EffectiveFrom,
+		Source:        rate.Source,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert exchange rate %s/%s: %w", rate.BaseCurrency, rate.QuoteCurrency, err)
+	}
+	return nil
+}
+
+// GetEffectiveRate returns the rate for the pair in effect at at, or nil if
+// the pair has no rate yet
+func (r *ExchangeRateRepository) GetEffectiveRate(ctx context.Context, base, quote string, at time.Time) (*models.ExchangeRate, error) {
+	rate, err := r.db.Queries.GetEffectiveExchangeRate(ctx, db.GetEffectiveExchangeRateParams{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		At:            at,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	result := toExchangeRateModel(rate)
+	return &result, nil
+}
+
+// ListRates returns every rate, past and scheduled, quoted against base
+func (r *ExchangeRateRepository) ListRates(ctx context.Context, base string) ([]models.ExchangeRate, error) {
+	rates, err := r.db.Queries.ListExchangeRates(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+
+	result := make([]models.ExchangeRate, len(rates))
+	for i, rate := range rates {
+		result[i] = toExchangeRateModel(rate)
+	}
+	return result, nil
+}
+
+func toExchangeRateModel(rate db.ExchangeRate) models.ExchangeRate {
+	return models.ExchangeRate{
+		ID:            rate.ID,
+		BaseCurrency:  rate.BaseCurrency,
+		QuoteCurrency: rate.QuoteCurrency,
+		Rate:          rate.Rate,
+		EffectiveFrom: rate.EffectiveFrom,
+		Source:        rate.Source,
+		CreatedAt:     rate.CreatedAt,
+	}
+}
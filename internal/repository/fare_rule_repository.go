@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// FareRuleRepository reads the taxes and fees table used to build fare
+// breakdowns
+type FareRuleRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewFareRuleRepository(database *db.Database, logger *zap.Logger) *FareRuleRepository {
+	return &FareRuleRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// ListRulesForRoute returns the active rules keyed to the route's origin or
+// destination country, or to any country. Cabin restrictions are left to the
+// caller.
+func (r *FareRuleRepository) ListRulesForRoute(ctx context.Context, originCountry, destinationCountry string) ([]models.FareRule, error) {
+	rules, err := r.db.Queries.ListActiveFareRules(ctx, originCountry, destinationCountry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fare rules: %w", err)
+	}
+
+	result := make([]models.FareRule, len(rules))
+	for i, rule := range rules {
+		result[i] = models.FareRule{
+			ID:                 rule.ID,
+			Code:               rule.Code,
+			Component:          models.FareComponent(rule.Component),
+			Description:        rule.Description,
+			OriginCountry:      rule.OriginCountry.String,
+			DestinationCountry: rule.DestinationCountry.String,
+			CabinClass:         rule.CabinClass.String,
+			FixedAmount:        rule.FixedAmount,
+			PercentBps:         int(rule.PercentBps),
+		}
+	}
+	return result, nil
+}
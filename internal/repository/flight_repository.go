@@ -34,18 +34,60 @@ func (r *FlightRepository) GetFlight(ctx context.Context, id int64) (*models.Fli
 		return nil, fmt.Errorf("failed to get flight: %w", err)
 	}
 	
+	return flightFromDB(flight), nil
+}
+
+// SearchFlights returns a page of the flights on a route departing within
+// window, in departure order, and the total number of matches. It backs
+// flight search while Elasticsearch is unavailable.
+func (r *FlightRepository) SearchFlights(ctx context.Context, req models.FlightSearchRequest, window models.TimeRange) ([]models.Flight, int64, error) {
+	params := db.SearchFlightsParams{
+		Origin:        req.Origin,
+		Destination:   req.Destination,
+		DepartureFrom: window.From.UTC(),
+		DepartureTo:   window.To.UTC(),
+		FareClass:     req.FareClass,
+		Airline:       req.Airline,
+		Limit:         int32(req.Size),
+		Offset:        int32((req.Page - 1) * req.Size),
+	}
+
+	total, err := r.db.Queries.CountSearchFlights(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count flights: %w", err)
+	}
+	if total == 0 {
+		return []models.Flight{}, 0, nil
+	}
+
+	rows, err := r.db.Queries.SearchFlights(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search flights: %w", err)
+	}
+
+	flights := make([]models.Flight, len(rows))
+	for i, row := range rows {
+		flights[i] = *flightFromDB(row)
+	}
+	return flights, total, nil
+}
+
+func flightFromDB(flight db.Flight) *models.Flight {
 	return &models.Flight{
 		ID:            flight.ID,
 		Origin:        flight.Origin,
 		Destination:   flight.Destination,
 		DepartureTime: flight.DepartureTime,
 		ArrivalTime:   flight.ArrivalTime,
+		DepartureTimeLocal: flight.DepartureTimeLocal,
+		ArrivalTimeLocal:   flight.ArrivalTimeLocal,
 		Airline:       flight.Airline,
 		Aircraft:      flight.Aircraft,
 		FareClass:     flight.FareClass,
+		BasePrice:     flight.BasePrice,
 		CreatedAt:     flight.CreatedAt,
 		UpdatedAt:     flight.UpdatedAt,
-	}, nil
+	}
 }
 
 // CreateFlight creates a new flight
@@ -56,9 +98,12 @@ func (r *FlightRepository) CreateFlight(ctx context.Context, flight models.Fligh
 		Destination:   flight.Destination,
 		DepartureTime: flight.DepartureTime,
 		ArrivalTime:   flight.ArrivalTime,
+		DepartureTimeLocal: flight.DepartureTimeLocal,
+		ArrivalTimeLocal:   flight.ArrivalTimeLocal,
 		Airline:       flight.Airline,
 		Aircraft:      flight.Aircraft,
 		FareClass:     flight.FareClass,
+		BasePrice:     flight.BasePrice,
 	})
 	
 	log.Printf("DEBUG Repository.CreateFlight - CreateFlight returned ID: %d, err: %v", flightID, err)
@@ -73,10 +118,21 @@ func (r *FlightRepository) CreateFlight(ctx context.Context, flight models.Fligh
 	return result, err
 }
 
-// CreateSeats creates seats for a flight
+// CreateSeats creates seats for a flight and adds them to the capacity of
+// their cabins in flight_inventory
 func (r *FlightRepository) CreateSeats(ctx context.Context, flightID int64, seats []models.Seat) error {
+	tx, err := r.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	queries := r.db.WithTx(tx)
+	capacity := make(map[string]int32)
+	var classes []string
+	
 	for _, seat := range seats {
-		_, err := r.db.Queries.CreateSeat(ctx, db.CreateSeatParams{
+		_, err := queries.CreateSeat(ctx, db.CreateSeatParams{
 			FlightID: flightID,
 			SeatNo:   seat.SeatNo,
 			Class:    seat.Class,
@@ -85,6 +141,26 @@ func (r *FlightRepository) CreateSeats(ctx context.Context, flightID int64, seat
 		if err != nil {
 			return fmt.Errorf("failed to create seat %s: %w", seat.SeatNo, err)
 		}
+		
+		if _, ok := capacity[seat.Class]; !ok {
+			classes = append(classes, seat.Class)
+		}
+		capacity[seat.Class]++
+	}
+	
+	for _, class := range classes {
+		err := queries.IncrementInventoryCapacity(ctx, db.IncrementInventoryCapacityParams{
+			FlightID:   flightID,
+			CabinClass: class,
+			Capacity:   capacity[class],
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update %s inventory: %w", class, err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit seats: %w", err)
 	}
 	
 	r.logger.Info("Seats created successfully",
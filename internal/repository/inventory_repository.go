@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// InventoryRepository maintains the per-cabin counters in flight_inventory.
+// Every mutation takes the transaction that changes the underlying seat lock
+// or ticket so the counters never drift from the rows they summarize.
+type InventoryRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewInventoryRepository(database *db.Database, logger *zap.Logger) *InventoryRepository {
+	return &InventoryRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// AdjustForSeat applies held and sold deltas to the cabin of the given seat
+func (r *InventoryRepository) AdjustForSeat(ctx context.Context, tx *sql.Tx, flightID int64, seatNo string, heldDelta, soldDelta int) error {
+	rowsAffected, err := r.db.WithTx(tx).AdjustSeatInventory(ctx, db.AdjustSeatInventoryParams{
+		HeldDelta: int32(heldDelta),
+		SoldDelta: int32(soldDelta),
+		FlightID:  flightID,
+		SeatNo:    seatNo,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to adjust flight inventory: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		// Flights created before inventory tracking have no rows until rebuilt
+		r.logger.Warn("No inventory row for seat",
+			zap.Int64("flight_id", flightID),
+			zap.String("seat_no", seatNo))
+	}
+
+	return nil
+}
+
+// ReleaseExpired decrements held for all locks expiring before cutoff. Call it
+// in the same transaction, and before, the locks are deleted.
+func (r *InventoryRepository) ReleaseExpired(ctx context.Context, tx *sql.Tx, cutoff time.Time) error {
+	if _, err := r.db.WithTx(tx).ReleaseExpiredInventory(ctx, cutoff); err != nil {
+		return fmt.Errorf("failed to release expired inventory: %w", err)
+	}
+	return nil
+}
+
+// ReserveOversoldSeat counts a seatless ticket against the cabin's
+// overbooking limit. It reports false when the cabin is full, including its
+// overbooking allowance, or does not exist.
+func (r *InventoryRepository) ReserveOversoldSeat(ctx context.Context, tx *sql.Tx, flightID int64, cabinClass string) (bool, error) {
+	rowsAffected, err := r.db.WithTx(tx).ReserveOversoldSeat(ctx, db.CabinInventoryParams{
+		FlightID:   flightID,
+		CabinClass: cabinClass,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve oversold seat: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ReleaseOversoldSeat returns a seatless ticket to the cabin's overbooking
+// allowance
+func (r *InventoryRepository) ReleaseOversoldSeat(ctx context.Context, tx *sql.Tx, flightID int64, cabinClass string) error {
+	rowsAffected, err := r.db.WithTx(tx).ReleaseOversoldSeat(ctx, db.CabinInventoryParams{
+		FlightID:   flightID,
+		CabinClass: cabinClass,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release oversold seat: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		r.logger.Warn("No oversold seat to release",
+			zap.Int64("flight_id", flightID),
+			zap.String("cabin_class", cabinClass))
+	}
+
+	return nil
+}
+
+// SetOverbookingLimit sets how many seats a cabin may sell beyond capacity.
+// It reports false when the flight has no such cabin.
+func (r *InventoryRepository) SetOverbookingLimit(ctx context.Context, flightID int64, cabinClass string, limit int) (bool, error) {
+	rowsAffected, err := r.db.Queries.SetOverbookingLimit(ctx, db.SetOverbookingLimitParams{
+		OverbookingLimit: int32(limit),
+		FlightID:         flightID,
+		CabinClass:       cabinClass,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to set overbooking limit: %w", err)
+	}
+
+	// MySQL reports zero affected rows when the value is unchanged
+	if rowsAffected == 0 {
+		rows, err := r.db.Queries.GetFlightInventory(ctx, flightID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get flight inventory: %w", err)
+		}
+		for _, row := range rows {
+			if row.CabinClass == cabinClass {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	r.logger.Info("Overbooking limit updated",
+		zap.Int64("flight_id", flightID),
+		zap.String("cabin_class", cabinClass),
+		zap.Int("limit", limit))
+
+	return true, nil
+}
+
+// ListFlightsAtRisk returns the cabins departing after since that have more
+// passengers, including active holds, than seats
+func (r *InventoryRepository) ListFlightsAtRisk(ctx context.Context, since time.Time) ([]models.FlightAtRisk, error) {
+	rows, err := r.db.Queries.ListFlightsAtRisk(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flights at risk: %w", err)
+	}
+
+	result := make([]models.FlightAtRisk, len(rows))
+	for i, row := range rows {
+		inventory := toFlightInventoryModel(row.FlightInventory)
+		result[i] = models.FlightAtRisk{
+			FlightID:         row.FlightID,
+			Origin:           row.Origin,
+			Destination:      row.Destination,
+			Airline:          row.Airline,
+			DepartureTime:    row.DepartureTime,
+			CabinClass:       inventory.CabinClass,
+			Capacity:         inventory.Capacity,
+			Held:             inventory.Held,
+			Sold:             inventory.Sold,
+			Blocked:          inventory.Blocked,
+			Oversold:         inventory.Oversold,
+			OverbookingLimit: inventory.OverbookingLimit,
+			Shortfall:        inventory.Shortfall(),
+		}
+	}
+
+	return result, nil
+}
+
+// GetFlightInventory returns the cabin counters of a flight
+func (r *InventoryRepository) GetFlightInventory(ctx context.Context, flightID int64) ([]models.FlightInventory, error) {
+	rows, err := r.db.Queries.GetFlightInventory(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight inventory: %w", err)
+	}
+
+	result := make([]models.FlightInventory, len(rows))
+	for i, row := range rows {
+		result[i] = toFlightInventoryModel(row)
+	}
+
+	return result, nil
+}
+
+// ListFlightInventory returns the cabin counters of several flights, keyed by
+// flight ID, using a single query. Flights without seats are absent.
+func (r *InventoryRepository) ListFlightInventory(ctx context.Context, flightIDs []int64) (map[int64][]models.FlightInventory, error) {
+	rows, err := r.db.Queries.ListFlightInventory(ctx, flightIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flight inventory: %w", err)
+	}
+
+	result := make(map[int64][]models.FlightInventory, len(flightIDs))
+	for _, row := range rows {
+		result[row.FlightID] = append(result[row.FlightID], toFlightInventoryModel(row))
+	}
+
+	return result, nil
+}
+
+func toFlightInventoryModel(row db.FlightInventory) models.FlightInventory {
+	return models.FlightInventory{
+		FlightID:         row.FlightID,
+		CabinClass:       row.CabinClass,
+		Capacity:         int(row.Capacity),
+		Held:             int(row.Held),
+		Sold:             int(row.Sold),
+		Blocked:          int(row.Blocked),
+		OverbookingLimit: int(row.OverbookingLimit),
+		Oversold:         int(row.Oversold),
+		UpdatedAt:        row.UpdatedAt,
+	}
+}
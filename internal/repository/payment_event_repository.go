@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// PaymentEventRepository stores payment provider webhook events. Events are
+// unique per provider and event ID so redeliveries are recorded once.
+type PaymentEventRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewPaymentEventRepository(database *db.Database, logger *zap.Logger) *PaymentEventRepository {
+	return &PaymentEventRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// RecordEvent stores an event inside tx. It reports false, without error,
+// when the provider already delivered an event with the same ID.
+func (r *PaymentEventRepository) RecordEvent(ctx context.Context, tx *sql.Tx, event models.PaymentWebhookEvent) (*models.PaymentWebhookEvent, bool, error) {
+	queries := r.db.WithTx(tx)
+
+	id, err := queries.CreatePaymentWebhookEvent(ctx, db.CreatePaymentWebhookEventParams{
+		Provider:        event.Provider,
+		EventID:         event.EventID,
+		EventType:       event.EventType,
+		AuthorizationID: sql.NullString{String: event.AuthorizationID, Valid: event.AuthorizationID != ""},
+		Payload:         event.Payload,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record payment event: %w", err)
+	}
+	if id == 0 {
+		return nil, false, nil
+	}
+
+	stored, err := queries.GetPaymentWebhookEventForUpdate(ctx, id)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get recorded payment event: %w", err)
+	}
+
+	result := toPaymentWebhookEventModel(stored)
+	return &result, true, nil
+}
+
+// GetEvent retrieves a stored event by provider event ID
+func (r *PaymentEventRepository) GetEvent(ctx context.Context, provider, eventID string) (*models.PaymentWebhookEvent, error) {
+	event, err := r.db.Queries.GetPaymentWebhookEvent(ctx, db.GetPaymentWebhookEventParams{
+		Provider: provider,
+		EventID:  eventID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get payment event: %w", err)
+	}
+
+	result := toPaymentWebhookEventModel(event)
+	return &result, nil
+}
+
+// LockEvent locks a stored event inside tx so concurrent replays serialize
+func (r *PaymentEventRepository) LockEvent(ctx context.Context, tx *sql.Tx, id int64) (*models.PaymentWebhookEvent, error) {
+	event, err := r.db.WithTx(tx).GetPaymentWebhookEventForUpdate(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock payment event: %w", err)
+	}
+
+	result := toPaymentWebhookEventModel(event)
+	return &result, nil
+}
+
+// ListUnprocessedEvents returns the provider's events that have not been
+// applied, oldest first
+func (r *PaymentEventRepository) ListUnprocessedEvents(ctx context.Context, provider string) ([]models.PaymentWebhookEvent, error) {
+	events, err := r.db.Queries.ListUnprocessedPaymentWebhookEvents(ctx, provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unprocessed payment events: %w", err)
+	}
+
+	result := make([]models.PaymentWebhookEvent, len(events))
+	for i, event := range events {
+		result[i] = toPaymentWebhookEventModel(event)
+	}
+	return result, nil
+}
+
+// MarkProcessed records the outcome of applying an event. A non-empty
+// processingError keeps the event pending for replay.
+func (r *PaymentEventRepository) MarkProcessed(ctx context.Context, tx *sql.Tx, id int64, processingError string) error {
+	err := r.db.WithTx(tx).MarkPaymentWebhookEventProcessed(ctx, db.MarkPaymentWebhookEventProcessedParams{
+		ProcessingError: sql.NullString{String: processingError, Valid: processingError != ""},
+		ID:              id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark payment event processed: %w", err)
+	}
+	return nil
+}
+
+func toPaymentWebhookEventModel(event db.PaymentWebhookEvent) models.PaymentWebhookEvent {
+	result := models.PaymentWebhookEvent{
+		ID:              event.ID,
+		Provider:        event.Provider,
+		EventID:         event.EventID,
+		EventType:       event.EventType,
+		AuthorizationID: event.AuthorizationID.String,
+		Payload:         event.Payload,
+		ReceivedAt:      event.ReceivedAt,
+		ProcessingError: event.ProcessingError.String,
+	}
+	if event.ProcessedAt.Valid {
+		result.ProcessedAt = &event.ProcessedAt.Time
+	}
+	return result
+}
@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+)
+
+// PromotionRepository stores promo codes, the codes applied to holds and
+// the redemptions made when tickets are issued
+type PromotionRepository struct {
+	db     *db.Database
+	logger *zap.Logger
+}
+
+func NewPromotionRepository(database *db.Database, logger *zap.Logger) *PromotionRepository {
+	return &PromotionRepository{
+		db:     database,
+		logger: logger,
+	}
+}
+
+// CreatePromotion stores a new promotion and returns it as stored
+func (r *PromotionRepository) CreatePromotion(ctx context.Context, promotion models.Promotion) (*models.Promotion, error) {
+	id, err := r.db.Queries.CreatePromotion(ctx, db.CreatePromotionParams{
+		Code:                  promotion.Code,
+		Description:           promotion.Description,
+		DiscountType:          string(promotion.DiscountType),
+		DiscountValue:         promotion.DiscountValue,
+		MaxDiscountAmount:     nullInt64(promotion.MaxDiscountAmount),
+		Origin:                sql.NullString{String: promotion.Origin, Valid: promotion.Origin != ""},
+		Destination:           sql.NullString{String: promotion.Destination, Valid: promotion.Destination != ""},
+		Airline:               sql.NullString{String: promotion.Airline, Valid: promotion.Airline != ""},
+		FareClass:             sql.NullString{String: promotion.FareClass, Valid: promotion.FareClass != ""},
+		TravelFrom:            nullTime(promotion.TravelFrom),
+		TravelUntil:           nullTime(promotion.TravelUntil),
+		ValidFrom:             promotion.ValidFrom,
+		ValidUntil:            nullTime(promotion.ValidUntil),
+		MaxRedemptions:        nullInt32(promotion.MaxRedemptions),
+		MaxRedemptionsPerUser: nullInt32(promotion.MaxRedemptionsPerUser),
+		Stackable:             promotion.Stackable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create promotion %s: %w", promotion.Code, err)
+	}
+
+	created, err := r.db.Queries.GetPromotion(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get created promotion: %w", err)
+	}
+
+	result := toPromotionModel(created)
+	return &result, nil
+}
+
+// GetPromotionByCode retrieves a promotion by code, returning nil if the code
+// does not exist
+func (r *PromotionRepository) GetPromotionByCode(ctx context.Context, code string) (*models.Promotion, error) {
+	promotion, err := r.db.Queries.GetPromotionByCode(ctx, strings.ToUpper(code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get promotion: %w", err)
+	}
+
+	result := toPromotionModel(promotion)
+	return &result, nil
+}
+
+// ListPromotions retrieves every promotion, newest first
+func (r *PromotionRepository) ListPromotions(ctx context.Context) ([]models.Promotion, error) {
+	promotions, err := r.db.Queries.ListPromotions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promotions: %w", err)
+	}
+
+	result := make([]models.Promotion, len(promotions))
+	for i, promotion := range promotions {
+		result[i] = toPromotionModel(promotion)
+	}
+	return result, nil
+}
+
+// LockPromotion locks a promotion inside tx so that concurrent redemptions
+// of the same code serialize, returning nil if it no longer exists
+func (r *PromotionRepository) LockPromotion(ctx context.Context, tx *sql.Tx, id int64) (*models.Promotion, error) {
+	promotion, err := r.db.WithTx(tx).GetPromotionForUpdate(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to lock promotion: %w", err)
+	}
+
+	result := toPromotionModel(promotion)
+	return &result, nil
+}
+
+// CountUserRedemptions returns how many times userID has redeemed the
+// promotion. With a transaction the count is a locking read.
+func (r *PromotionRepository) CountUserRedemptions(ctx context.Context, tx *sql.Tx, promotionID int64, userID string) (int, error) {
+	params := db.CountUserPromotionRedemptionsParams{
+		PromotionID: promotionID,
+		UserID:      userID,
+	}
+
+	var count int64
+	var err error
+	if tx != nil {
+		count, err = r.db.WithTx(tx).CountUserPromotionRedemptionsForUpdate(ctx, params)
+	} else {
+		count, err = r.db.Queries.CountUserPromotionRedemptions(ctx, params)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count promotion redemptions: %w", err)
+	}
+	return int(count), nil
+}
+
+// RecordRedemption records a redemption inside tx and bumps the promotion's
+// redemption count
+func (r *PromotionRepository) RecordRedemption(ctx context.Context, tx *sql.Tx, promotionID int64, userID string, ticketID, discountAmount int64) error {
+	queries := r.db.WithTx(tx)
+
+	err := queries.CreatePromotionRedemption(ctx, db.CreatePromotionRedemptionParams{
+		PromotionID:    promotionID,
+		UserID:         userID,
+		TicketID:       ticketID,
+		DiscountAmount: discountAmount,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record promotion redemption: %w", err)
+	}
+
+	if err := queries.IncrementPromotionRedemptions(ctx, promotionID); err != nil {
+		return fmt.Errorf("failed to record promotion redemption: %w", err)
+	}
+	return nil
+}
+
+// ReplaceHoldPromotions sets the promotions applied to a seat's hold inside
+// tx, dropping any left by an earlier hold on the seat
+func (r *PromotionRepository) ReplaceHoldPromotions(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string, promotionIDs []int64) error {
+	queries := r.db.WithTx(tx)
+
+	if err := queries.DeleteHoldPromotions(ctx, db.GetSeatLockParams{FlightID: flightID, SeatNo: seatNo}); err != nil {
+		return fmt.Errorf("failed to clear hold promotions: %w", err)
+	}
+
+	for _, promotionID := range promotionIDs {
+		err := queries.CreateHoldPromotion(ctx, db.HoldPromotionParams{
+			FlightID:    flightID,
+			SeatNo:      seatNo,
+			HolderID:    holderID,
+			PromotionID: promotionID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply promotion to hold: %w", err)
+		}
+	}
+	return nil
+}
+
+// ClearHoldPromotions removes the promotions applied to a seat's hold inside tx
+func (r *PromotionRepository) ClearHoldPromotions(ctx context.Context, tx *sql.Tx, flightID int64, seatNo string) error {
+	if err := r.db.WithTx(tx).DeleteHoldPromotions(ctx, db.GetSeatLockParams{FlightID: flightID, SeatNo: seatNo}); err != nil {
+		return fmt.Errorf("failed to clear hold promotions: %w", err)
+	}
+	return nil
+}
+
+// ListHoldPromotionCodes returns the codes applied to the holder's hold
+func (r *PromotionRepository) ListHoldPromotionCodes(ctx context.Context, flightID int64, seatNo, holderID string) ([]string, error) {
+	codes, err := r.db.Queries.ListHoldPromotionCodes(ctx, db.ListHoldPromotionCodesParams{
+		FlightID: flightID,
+		SeatNo:   seatNo,
+		HolderID: holderID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hold promotions: %w", err)
+	}
+	return codes, nil
+}
+
+func toPromotionModel(promotion db.Promotion) models.Promotion {
+	result := models.Promotion{
+		ID:              promotion.ID,
+		Code:            promotion.Code,
+		Description:     promotion.Description,
+		DiscountType:    models.DiscountType(promotion.DiscountType),
+		DiscountValue:   promotion.DiscountValue,
+		Origin:          promotion.Origin.String,
+		Destination:     promotion.Destination.String,
+		Airline:         promotion.Airline.String,
+		FareClass:       promotion.FareClass.String,
+		ValidFrom:       promotion.ValidFrom,
+		RedemptionCount: int(promotion.RedemptionCount),
+		Stackable:       promotion.Stackable,
+		Active:          promotion.Active,
+		CreatedAt:       promotion.CreatedAt,
+	}
+	if promotion.MaxDiscountAmount.Valid {
+		result.MaxDiscountAmount = &promotion.MaxDiscountAmount.Int64
+	}
+	if promotion.TravelFrom.Valid {
+		result.TravelFrom = &promotion.TravelFrom.Time
+	}
+	if promotion.TravelUntil.Valid {
+		result.TravelUntil = &promotion.TravelUntil.Time
+	}
+	if promotion.ValidUntil.Valid {
+		result.ValidUntil = &promotion.ValidUntil.Time
+	}
+	if promotion.MaxRedemptions.Valid {
+		limit := int(promotion.MaxRedemptions.Int32)
+		result.MaxRedemptions = &limit
+	}
+	if promotion.MaxRedemptionsPerUser.Valid {
+		limit := int(promotion.MaxRedemptionsPerUser.Int32)
+		result.MaxRedemptionsPerUser = &limit
+	}
+	return result
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+func nullInt32(v *int) sql.NullInt32 {
+	if v == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*v), Valid: true}
+}
+
+func nullTime(v *time.Time) sql.NullTime {
+	if v == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *v, Valid: true}
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -12,6 +13,15 @@ import (
 	"airline-booking/internal/models"
 )
 
+var (
+	// ErrSeatHeld is returned by CreateHold when another holder has a live
+	// hold on the seat
+	ErrSeatHeld = errors.New("seat is already held by another user")
+	// ErrNoValidHold is returned by ConfirmHold when the holder has no
+	// payment_pending hold on the seat
+	ErrNoValidHold = errors.New("no valid hold found to confirm")
+)
+
 type SeatRepository struct {
 	db     *db.Database
 	logger *zap.Logger
@@ -24,10 +34,17 @@ func NewSeatRepository(database *db.Database, logger *zap.Logger) *SeatRepositor
 	}
 }
 
-// CreateHold attempts to create or update a seat hold using compare-and-set logic
-func (r *SeatRepository) CreateHold(ctx context.Context, flightID int64, seatNo, holderID string, expiresAt time.Time) error {
+// CreateHold attempts to create or update a seat hold using compare-and-set
+// logic inside tx. It reports whether the hold adds to the seat's held count:
+// true for a new lock or one restarted after it expired or was released, false
+// when a live hold is extended or an expired one not yet cleaned up is taken
+// over.
+func (r *SeatRepository) CreateHold(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string, expiresAt time.Time) (bool, error) {
+	queries := r.db.WithTx(tx)
+	created := true
+	
 	// First try to insert a new lock
-	err := r.db.Queries.CreateSeatLock(ctx, db.CreateSeatLockParams{
+	err := queries.CreateSeatLock(ctx, db.CreateSeatLockParams{
 		FlightID:  flightID,
 		SeatNo:    seatNo,
 		HolderID:  holderID,
@@ -35,8 +52,22 @@ func (r *SeatRepository) CreateHold(ctx context.Context, flightID int64, seatNo,
 	})
 	
 	if err != nil {
-		// If insert fails due to duplicate key, try to update with CAS logic
-		rowsAffected, updateErr := r.db.Queries.UpdateSeatLock(ctx, db.UpdateSeatLockParams{
+		// The seat has a lock row; restart it if its last hold has ended
+		rowsAffected, reactivateErr := queries.ReactivateSeatLock(ctx, db.ReactivateSeatLockParams{
+			HolderID:  holderID,
+			ExpiresAt: &expiresAt,
+			FlightID:  flightID,
+			SeatNo:    seatNo,
+		})
+		if reactivateErr != nil {
+			return false, fmt.Errorf("failed to reactivate seat lock: %w", reactivateErr)
+		}
+		created = rowsAffected > 0
+	}
+	
+	if err != nil && !created {
+		// Otherwise try to extend or take over the active hold with CAS logic
+		rowsAffected, updateErr := queries.UpdateSeatLock(ctx, db.UpdateSeatLockParams{
 			HolderID:   holderID,
 			ExpiresAt:  &expiresAt,
 			FlightID:   flightID,
@@ -45,11 +76,11 @@ func (r *SeatRepository) CreateHold(ctx context.Context, flightID int64, seatNo,
 		})
 		
 		if updateErr != nil {
-			return fmt.Errorf("failed to update seat lock: %w", updateErr)
+			return false, fmt.Errorf("failed to update seat lock: %w", updateErr)
 		}
 		
 		if rowsAffected == 0 {
-			return fmt.Errorf("seat is already held by another user")
+			return false, ErrSeatHeld
 		}
 	}
 	
@@ -57,9 +88,10 @@ func (r *SeatRepository) CreateHold(ctx context.Context, flightID int64, seatNo,
 		zap.Int64("flight_id", flightID),
 		zap.String("seat_no", seatNo),
 		zap.String("holder_id", holderID),
-		zap.Time("expires_at", expiresAt))
+		zap.Time("expires_at", expiresAt),
+		zap.Bool("created", created))
 	
-	return nil
+	return created, nil
 }
 
 // GetSeatLock retrieves a seat lock
@@ -76,20 +108,37 @@ func (r *SeatRepository) GetSeatLock(ctx context.Context, flightID int64, seatNo
 		return nil, fmt.Errorf("failed to get seat lock: %w", err)
 	}
 	
-	return &models.SeatLock{
-		ID:        lock.ID,
-		FlightID:  lock.FlightID,
-		SeatNo:    lock.SeatNo,
-		HolderID:  lock.HolderID,
-		ExpiresAt: lock.ExpiresAt,
-		CreatedAt: lock.CreatedAt,
-		UpdatedAt: lock.UpdatedAt,
+	result := toSeatLockModel(lock)
+	return &result, nil
+}
+
+// GetSeat retrieves a seat of a flight
+func (r *SeatRepository) GetSeat(ctx context.Context, flightID int64, seatNo string) (*models.Seat, error) {
+	seat, err := r.db.Queries.GetSeat(ctx, db.GetSeatParams{
+		FlightID: flightID,
+		SeatNo:   seatNo,
+	})
+	
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get seat: %w", err)
+	}
+	
+	return &models.Seat{
+		ID:        seat.ID,
+		FlightID:  seat.FlightID,
+		SeatNo:    seat.SeatNo,
+		Class:     seat.Class,
+		CreatedAt: seat.CreatedAt,
+		UpdatedAt: seat.UpdatedAt,
 	}, nil
 }
 
 // ConfirmHold converts a hold to a permanent ticket lock
-func (r *SeatRepository) ConfirmHold(ctx context.Context, flightID int64, seatNo, holderID string) error {
-	rowsAffected, err := r.db.Queries.ConfirmSeatLock(ctx, db.ConfirmSeatLockParams{
+func (r *SeatRepository) ConfirmHold(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string) error {
+	rowsAffected, err := r.db.WithTx(tx).ConfirmSeatLock(ctx, db.ConfirmSeatLockParams{
 		FlightID: flightID,
 		SeatNo:   seatNo,
 		HolderID: holderID,
@@ -100,7 +149,7 @@ func (r *SeatRepository) ConfirmHold(ctx context.Context, flightID int64, seatNo
 	}
 	
 	if rowsAffected == 0 {
-		return fmt.Errorf("no valid hold found to confirm")
+		return ErrNoValidHold
 	}
 	
 	r.logger.Info("Seat hold confirmed successfully",
@@ -111,38 +160,99 @@ func (r *SeatRepository) ConfirmHold(ctx context.Context, flightID int64, seatNo
 	return nil
 }
 
-// ReleaseHold releases a seat hold
-func (r *SeatRepository) ReleaseHold(ctx context.Context, flightID int64, seatNo, holderID string) error {
-	err := r.db.Queries.ReleaseSeatLock(ctx, db.ReleaseSeatLockParams{
+// ReleaseHold releases a seat hold inside tx and reports whether one was
+// released. Only active holds can be released: a payment may be in flight for
+// a payment_pending hold, and confirmed locks back a ticket.
+func (r *SeatRepository) ReleaseHold(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string) (bool, error) {
+	rowsAffected, err := r.db.WithTx(tx).ReleaseSeatLock(ctx, db.ReleaseSeatLockParams{
 		FlightID: flightID,
 		SeatNo:   seatNo,
 		HolderID: holderID,
 	})
 	
 	if err != nil {
-		return fmt.Errorf("failed to release seat lock: %w", err)
+		return false, fmt.Errorf("failed to release seat lock: %w", err)
 	}
 	
 	r.logger.Info("Seat hold released successfully",
 		zap.Int64("flight_id", flightID),
 		zap.String("seat_no", seatNo),
-		zap.String("holder_id", holderID))
+		zap.String("holder_id", holderID),
+		zap.Bool("released", rowsAffected > 0))
 	
+	return rowsAffected > 0, nil
+}
+
+// MarkPaymentPending moves the holder's live hold to payment_pending inside
+// tx and extends it to expiresAt so it cannot expire while the payment is
+// processed. It reports false when the holder has no live hold on the seat.
+func (r *SeatRepository) MarkPaymentPending(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string, expiresAt time.Time) (bool, error) {
+	rowsAffected, err := r.db.WithTx(tx).MarkSeatLockPaymentPending(ctx, db.MarkSeatLockPaymentPendingParams{
+		ExpiresAt: &expiresAt,
+		FlightID:  flightID,
+		SeatNo:    seatNo,
+		HolderID:  holderID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to mark seat lock payment pending: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// RevertToActive returns a payment_pending hold to active inside tx, with the
+// expiry it had before the payment started, and reports whether it did
+func (r *SeatRepository) RevertToActive(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string) (bool, error) {
+	rowsAffected, err := r.db.WithTx(tx).RevertSeatLockToActive(ctx, db.ConfirmSeatLockParams{
+		FlightID: flightID,
+		SeatNo:   seatNo,
+		HolderID: holderID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revert seat lock to active: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// DeleteLock removes whatever lock exists on a seat, including the permanent
+// lock of a sold seat; used when a ticket is cancelled
+func (r *SeatRepository) DeleteLock(ctx context.Context, tx *sql.Tx, flightID int64, seatNo string) error {
+	_, err := r.db.WithTx(tx).DeleteSeatLock(ctx, db.GetSeatLockParams{
+		FlightID: flightID,
+		SeatNo:   seatNo,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete seat lock: %w", err)
+	}
 	return nil
 }
 
-// CleanupExpiredHolds removes all expired holds
-func (r *SeatRepository) CleanupExpiredHolds(ctx context.Context) error {
-	err := r.db.Queries.CleanupExpiredLocks(ctx)
+// CleanupExpiredHolds ends all holds that expired before cutoff inside tx and
+// returns them as they were before. Active holds become expired; holds still
+// payment_pending past their extended expiry are rolled back to released. The
+// selected locks are locked until tx ends.
+func (r *SeatRepository) CleanupExpiredHolds(ctx context.Context, tx *sql.Tx, cutoff time.Time) ([]models.SeatLock, error) {
+	queries := r.db.WithTx(tx)
+	
+	locks, err := queries.ListExpiredSeatLocks(ctx, cutoff)
 	if err != nil {
-		return fmt.Errorf("failed to cleanup expired locks: %w", err)
+		return nil, fmt.Errorf("failed to list expired locks: %w", err)
 	}
 	
-	r.logger.Debug("Expired holds cleaned up successfully")
-	return nil
+	if _, err := queries.CleanupExpiredLocks(ctx, cutoff); err != nil {
+		return nil, fmt.Errorf("failed to cleanup expired locks: %w", err)
+	}
+	
+	expired := make([]models.SeatLock, len(locks))
+	for i, lock := range locks {
+		expired[i] = toSeatLockModel(lock)
+	}
+	
+	r.logger.Debug("Expired holds cleaned up successfully", zap.Int("count", len(expired)))
+	return expired, nil
 }
 
-// GetFlightSeatAvailability returns seat availability for a flight
+// GetFlightSeatAvailability returns seat availability for a flight. Prices
+// are left for the booking service to fill in from the flight's base price.
 func (r *SeatRepository) GetFlightSeatAvailability(ctx context.Context, flightID int64) ([]models.SeatAvailability, error) {
 	seats, err := r.db.Queries.ListSeats(ctx, flightID)
 	if err != nil {
@@ -167,29 +277,26 @@ func (r *SeatRepository) GetFlightSeatAvailability(ctx context.Context, flightID
 	
 	ticketMap := make(map[string]bool)
 	for _, ticket := range tickets {
-		ticketMap[ticket.SeatNo] = true
+		if ticket.SeatNo.Valid && ticket.Status != string(models.TicketStatusCancelled) {
+			ticketMap[ticket.SeatNo.String] = true
+		}
 	}
 	
 	// Build availability list
+	now := time.Now()
 	availability := make([]models.SeatAvailability, len(seats))
 	for i, seat := range seats {
 		seatAvail := models.SeatAvailability{
 			SeatNo: seat.SeatNo,
 			Class:  seat.Class,
-			Price:  29900, // Base price in cents ($299.00)
 		}
 		
 		// Check if sold
 		if ticketMap[seat.SeatNo] {
 			seatAvail.Status = models.SeatStatusSold
-		} else if lock, exists := lockMap[seat.SeatNo]; exists {
-			// Check if lock is expired
-			if lock.ExpiresAt != nil && lock.ExpiresAt.Before(time.Now()) {
-				seatAvail.Status = models.SeatStatusAvailable
-			} else {
-				seatAvail.Status = models.SeatStatusHeld
-				seatAvail.ExpiresAt = lock.ExpiresAt
-			}
+		} else if lock, exists := lockMap[seat.SeatNo]; exists && toSeatLockModel(*lock).Live(now) {
+			seatAvail.Status = models.SeatStatusHeld
+			seatAvail.ExpiresAt = lock.ExpiresAt
 		} else {
 			seatAvail.Status = models.SeatStatusAvailable
 		}
@@ -200,7 +307,63 @@ func (r *SeatRepository) GetFlightSeatAvailability(ctx context.Context, flightID
 	return availability, nil
 }
 
+// AssignSeat gives a seat with no live lock to a ticket holder outside the
+// hold flow, as at check-in, creating or reclaiming a confirmed lock inside
+// tx. It reports false when the seat is held or sold.
+func (r *SeatRepository) AssignSeat(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string) (bool, error) {
+	queries := r.db.WithTx(tx)
+	
+	err := queries.CreateConfirmedSeatLock(ctx, db.ConfirmSeatLockParams{
+		FlightID: flightID,
+		SeatNo:   seatNo,
+		HolderID: holderID,
+	})
+	if err == nil {
+		return true, nil
+	}
+	
+	// The seat has a lock row; take it only if its last hold has ended
+	rowsAffected, err := queries.ReclaimSeatLock(ctx, db.ReclaimSeatLockParams{
+		HolderID: holderID,
+		FlightID: flightID,
+		SeatNo:   seatNo,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to reclaim seat lock: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// FindFreeSeat returns the first seat of a cabin that is neither held nor
+// sold, or an empty string if there is none
+func (r *SeatRepository) FindFreeSeat(ctx context.Context, tx *sql.Tx, flightID int64, cabinClass string) (string, error) {
+	seatNo, err := r.db.WithTx(tx).GetFreeCabinSeat(ctx, db.GetFreeCabinSeatParams{
+		FlightID: flightID,
+		Class:    cabinClass,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find free seat: %w", err)
+	}
+	return seatNo, nil
+}
+
 // GetHold is an alias for GetSeatLock for consistency with the booking service
 func (r *SeatRepository) GetHold(ctx context.Context, flightID int64, seatNo string) (*models.SeatLock, error) {
 	return r.GetSeatLock(ctx, flightID, seatNo)
 }
+
+func toSeatLockModel(lock db.SeatLock) models.SeatLock {
+	return models.SeatLock{
+		ID:        lock.ID,
+		FlightID:  lock.FlightID,
+		SeatNo:    lock.SeatNo,
+		HolderID:  lock.HolderID,
+		Status:    models.HoldStatus(lock.Status),
+		ExpiresAt: lock.ExpiresAt,
+		CreatedAt: lock.CreatedAt,
+		UpdatedAt: lock.UpdatedAt,
+	}
+}
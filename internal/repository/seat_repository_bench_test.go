@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/db"
+)
+
+// fakeConnector is a minimal database/sql driver that answers the seat
+// availability and inventory queries from memory after a fixed per-query delay, standing in
+// for a MySQL round trip. It records how many queries were issued.
+type fakeConnector struct {
+	latency        time.Duration
+	seatsPerFlight int
+	emptyFlights   map[int64]bool
+	queries        atomic.Int64
+}
+
+func (c *fakeConnector) Connect(context.Context) (driver.Conn, error) { return &fakeConn{c}, nil }
+func (c *fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ c *fakeConnector }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, fmt.Errorf("transactions not supported") }
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.c.queries.Add(1)
+	time.Sleep(c.c.latency)
+
+	now := time.Now()
+	switch {
+	case strings.Contains(query, "FROM flight_inventory"):
+		rows := &fakeRows{columns: []string{"flight_id", "cabin_class", "capacity", "held", "sold", "blocked", "overbooking_limit", "oversold", "created_at", "updated_at"}}
+		for _, arg := range args {
+			flightID := arg.Value.(int64)
+			if c.c.emptyFlights[flightID] {
+				continue
+			}
+			business := int64(c.c.seatsPerFlight / 6)
+			rows.values = append(rows.values,
+				[]driver.Value{flightID, "business", business, int64(1), int64(2), int64(0), int64(0), int64(0), now, now},
+				[]driver.Value{flightID, "economy", int64(c.c.seatsPerFlight) - business, int64(0), int64(0), int64(0), int64(4), int64(1), now, now})
+		}
+		return rows, nil
+	case strings.Contains(query, "FROM seats WHERE flight_id"):
+		flightID := args[0].Value.(int64)
+		rows := &fakeRows{columns: []string{"id", "flight_id", "seat_no", "class", "created_at", "updated_at"}}
+		for i := 0; i < c.c.seatsPerFlight; i++ {
+			seatNo := fmt.Sprintf("%d%c", i/6+1, 'A'+i%6)
+			rows.values = append(rows.values, []driver.Value{int64(i + 1), flightID, seatNo, "economy", now, now})
+		}
+		return rows, nil
+	case strings.Contains(query, "FROM seat_locks"), strings.Contains(query, "FROM tickets"):
+		return &fakeRows{}, nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func newFakeDatabase(connector *fakeConnector) *db.Database {
+	sqlDB := sql.OpenDB(connector)
+	return &db.Database{DB: sqlDB, Queries: db.New(sqlDB)}
+}
+
+func flightIDRange(n int) []int64 {
+	ids := make([]int64, n)
+	for i := range ids {
+		ids[i] = int64(i + 1)
+	}
+	return ids
+}
+
+func TestListFlightInventoryGroupsByFlight(t *testing.T) {
+	connector := &fakeConnector{seatsPerFlight: 180, emptyFlights: map[int64]bool{2: true}}
+	repo := NewInventoryRepository(newFakeDatabase(connector), zap.NewNop())
+
+	inventory, err := repo.ListFlightInventory(context.Background(), []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := inventory[2]; ok {
+		t.Errorf("expected no cabins for a flight without seats")
+	}
+	for _, id := range []int64{1, 3} {
+		cabins := inventory[id]
+		if len(cabins) != 2 {
+			t.Fatalf("flight %d: expected 2 cabins, got %d", id, len(cabins))
+		}
+		if got := cabins[0].Available(); cabins[0].CabinClass != "business" || got != 27 {
+			t.Errorf("flight %d: expected 27 business seats available, got %s=%d", id, cabins[0].CabinClass, got)
+		}
+		if got := cabins[1].Available(); got != 150 {
+			t.Errorf("flight %d: expected 150 economy seats available, got %d", id, got)
+		}
+	}
+	if q := connector.queries.Load(); q != 1 {
+		t.Errorf("expected a single query, got %d", q)
+	}
+}
+
+// The benchmarks compare a 100-result search page resolved with one
+// GetFlightSeatAvailability call per flight (three queries each, loading every
+// seat row) against a single ListFlightInventory lookup of the cabin counters.
+//
+//	go test ./internal/repository -run '^$' -bench SearchAvailability -benchmem
+
+const (
+	benchFlights        = 100
+	benchSeatsPerFlight = 180
+	benchQueryLatency   = 200 * time.Microsecond
+)
+
+func BenchmarkSearchAvailabilityPerFlight(b *testing.B) {
+	connector := &fakeConnector{latency: benchQueryLatency, seatsPerFlight: benchSeatsPerFlight}
+	repo := NewSeatRepository(newFakeDatabase(connector), zap.NewNop())
+	ctx := context.Background()
+	ids := flightIDRange(benchFlights)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, id := range ids {
+			seats, err := repo.GetFlightSeatAvailability(ctx, id)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_ = len(seats)
+		}
+	}
+	b.ReportMetric(float64(connector.queries.Load())/float64(b.N), "queries/op")
+}
+
+func BenchmarkSearchAvailabilityBatched(b *testing.B) {
+	connector := &fakeConnector{latency: benchQueryLatency, seatsPerFlight: benchSeatsPerFlight}
+	repo := NewInventoryRepository(newFakeDatabase(connector), zap.NewNop())
+	ctx := context.Background()
+	ids := flightIDRange(benchFlights)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.ListFlightInventory(ctx, ids); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(connector.queries.Load())/float64(b.N), "queries/op")
+}
@@ -24,22 +24,33 @@ func NewTicketRepository(database *db.Database, logger *zap.Logger) *TicketRepos
 	}
 }
 
-// CreateTicket creates a new ticket in a transaction
+// CreateTicket creates a new ticket and its fare line items in a transaction
 func (r *TicketRepository) CreateTicket(ctx context.Context, tx *sql.Tx, ticket models.Ticket) (*models.Ticket, error) {
 	queries := r.db.WithTx(tx)
 	
 	// Generate PNR code
 	pnrCode := r.generatePNRCode()
 	
-	ticketID, err := queries.CreateTicket(ctx, db.CreateTicketParams{
-		FlightID:    ticket.FlightID,
-		SeatNo:      ticket.SeatNo,
-		UserID:      ticket.UserID,
-		PriceAmount: ticket.PriceAmount,
-		Currency:    ticket.Currency,
-		PnrCode:     pnrCode,
-		PaymentRef:  ticket.PaymentRef,
-	})
+	params := db.CreateTicketParams{
+		FlightID:               ticket.FlightID,
+		SeatNo:                 sql.NullString{String: ticket.SeatNo, Valid: ticket.SeatNo != ""},
+		CabinClass:             sql.NullString{String: ticket.CabinClass, Valid: ticket.CabinClass != ""},
+		UserID:                 ticket.UserID,
+		PriceAmount:            ticket.PriceAmount,
+		Currency:               ticket.Currency,
+		BasePriceAmount:        ticket.BasePriceAmount,
+		BaseCurrency:           ticket.BaseCurrency,
+		ExchangeRate:           ticket.ExchangeRate,
+		PnrCode:                pnrCode,
+		PaymentRef:             ticket.PaymentRef,
+		PaymentAuthorizationID: sql.NullString{String: ticket.PaymentAuthorizationID, Valid: ticket.PaymentAuthorizationID != ""},
+		PaymentStatus:          ticket.PaymentStatus,
+	}
+	if ticket.ExchangeRateEffectiveFrom != nil {
+		params.ExchangeRateEffectiveFrom = sql.NullTime{Time: *ticket.ExchangeRateEffectiveFrom, Valid: true}
+	}
+
+	ticketID, err := queries.CreateTicket(ctx, params)
 	
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ticket: %w", err)
@@ -51,18 +62,22 @@ func (r *TicketRepository) CreateTicket(ctx context.Context, tx *sql.Tx, ticket
 		return nil, fmt.Errorf("failed to get created ticket: %w", err)
 	}
 	
-	result := &models.Ticket{
-		ID:          createdTicket.ID,
-		FlightID:    createdTicket.FlightID,
-		SeatNo:      createdTicket.SeatNo,
-		UserID:      createdTicket.UserID,
-		PriceAmount: createdTicket.PriceAmount,
-		Currency:    createdTicket.Currency,
-		IssuedAt:    createdTicket.IssuedAt,
-		PNRCode:     createdTicket.PnrCode,
-		PaymentRef:  createdTicket.PaymentRef,
-		CreatedAt:   createdTicket.CreatedAt,
+	result := toTicketModel(createdTicket)
+	
+	for _, item := range ticket.LineItems {
+		err := queries.CreateTicketLineItem(ctx, db.CreateTicketLineItemParams{
+			TicketID:    result.ID,
+			Component:   string(item.Component),
+			Code:        item.Code,
+			Description: item.Description,
+			Amount:      item.Amount,
+			BaseAmount:  item.BaseAmount,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ticket line item %s: %w", item.Code, err)
+		}
 	}
+	result.LineItems = ticket.LineItems
 	
 	r.logger.Info("Ticket created successfully",
 		zap.Int64("ticket_id", result.ID),
@@ -70,7 +85,7 @@ func (r *TicketRepository) CreateTicket(ctx context.Context, tx *sql.Tx, ticket
 		zap.Int64("flight_id", result.FlightID),
 		zap.String("seat_no", result.SeatNo))
 	
-	return result, nil
+	return &result, nil
 }
 
 // GetTicketByPNR retrieves a ticket by PNR code
@@ -83,18 +98,8 @@ func (r *TicketRepository) GetTicketByPNR(ctx context.Context, pnrCode string) (
 		return nil, fmt.Errorf("failed to get ticket by PNR: %w", err)
 	}
 	
-	return &models.Ticket{
-		ID:          ticket.ID,
-		FlightID:    ticket.FlightID,
-		SeatNo:      ticket.SeatNo,
-		UserID:      ticket.UserID,
-		PriceAmount: ticket.PriceAmount,
-		Currency:    ticket.Currency,
-		IssuedAt:    ticket.IssuedAt,
-		PNRCode:     ticket.PnrCode,
-		PaymentRef:  ticket.PaymentRef,
-		CreatedAt:   ticket.CreatedAt,
-	}, nil
+	result := toTicketModel(ticket)
+	return &result, nil
 }
 
 // GetTicketByFlightSeat checks if a ticket exists for a flight/seat combination
@@ -111,18 +116,173 @@ func (r *TicketRepository) GetTicketByFlightSeat(ctx context.Context, flightID i
 		return nil, fmt.Errorf("failed to get ticket by flight/seat: %w", err)
 	}
 	
-	return &models.Ticket{
-		ID:          ticket.ID,
-		FlightID:    ticket.FlightID,
-		SeatNo:      ticket.SeatNo,
-		UserID:      ticket.UserID,
-		PriceAmount: ticket.PriceAmount,
-		Currency:    ticket.Currency,
-		IssuedAt:    ticket.IssuedAt,
-		PNRCode:     ticket.PnrCode,
-		PaymentRef:  ticket.PaymentRef,
-		CreatedAt:   ticket.CreatedAt,
-	}, nil
+	result := toTicketModel(ticket)
+	return &result, nil
+}
+
+// CancelTicket marks the ticket with the given PNR as cancelled inside tx and
+// returns it. The ticket row is locked so concurrent cancellations of the same
+// booking serialize; a ticket that is already cancelled is returned unchanged
+// with cancelled=false.
+func (r *TicketRepository) CancelTicket(ctx context.Context, tx *sql.Tx, pnrCode string) (ticket *models.Ticket, cancelled bool, err error) {
+	queries := r.db.WithTx(tx)
+	
+	current, err := queries.GetTicketByPNRForUpdate(ctx, pnrCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get ticket by PNR: %w", err)
+	}
+	
+	rowsAffected, err := queries.CancelTicket(ctx, current.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to cancel ticket: %w", err)
+	}
+	
+	updated, err := queries.GetTicket(ctx, current.ID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get cancelled ticket: %w", err)
+	}
+	
+	result := toTicketModel(updated)
+	return &result, rowsAffected > 0, nil
+}
+
+// GetTicketByPNRForUpdate locks the ticket with the given PNR inside tx
+func (r *TicketRepository) GetTicketByPNRForUpdate(ctx context.Context, tx *sql.Tx, pnrCode string) (*models.Ticket, error) {
+	ticket, err := r.db.WithTx(tx).GetTicketByPNRForUpdate(ctx, pnrCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ticket by PNR: %w", err)
+	}
+	
+	result := toTicketModel(ticket)
+	return &result, nil
+}
+
+// AddLineItems adds items bought after the ticket was issued inside tx and
+// raises the ticket's totals by their sum, keeping the breakdown equal to
+// the price
+func (r *TicketRepository) AddLineItems(ctx context.Context, tx *sql.Tx, ticketID int64, items []models.FareLineItem) error {
+	queries := r.db.WithTx(tx)
+	
+	var amount, baseAmount int64
+	for _, item := range items {
+		err := queries.CreateTicketLineItem(ctx, db.CreateTicketLineItemParams{
+			TicketID:    ticketID,
+			Component:   string(item.Component),
+			Code:        item.Code,
+			Description: item.Description,
+			Amount:      item.Amount,
+			BaseAmount:  item.BaseAmount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create ticket line item %s: %w", item.Code, err)
+		}
+		amount += item.Amount
+		baseAmount += item.BaseAmount
+	}
+	
+	err := queries.AddTicketAmounts(ctx, db.AddTicketAmountsParams{
+		PriceAmount:     amount,
+		BasePriceAmount: baseAmount,
+		ID:              ticketID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update ticket totals: %w", err)
+	}
+	return nil
+}
+
+// UpdateSeat moves a ticket to another seat, or gives a seatless ticket one,
+// inside tx
+func (r *TicketRepository) UpdateSeat(ctx context.Context, tx *sql.Tx, ticketID int64, seatNo string) error {
+	err := r.db.WithTx(tx).UpdateTicketSeat(ctx, db.UpdateTicketSeatParams{
+		SeatNo: seatNo,
+		ID:     ticketID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update ticket seat: %w", err)
+	}
+	return nil
+}
+
+// UpdatePaymentStatus records the gateway status of a ticket's payment
+func (r *TicketRepository) UpdatePaymentStatus(ctx context.Context, ticketID int64, status string) error {
+	err := r.db.Queries.UpdateTicketPaymentStatus(ctx, db.UpdateTicketPaymentStatusParams{
+		PaymentStatus: status,
+		ID:            ticketID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update ticket payment status: %w", err)
+	}
+	return nil
+}
+
+// GetTicketByPaymentAuthorizationForUpdate locks the ticket paid with the
+// given gateway authorization inside tx
+func (r *TicketRepository) GetTicketByPaymentAuthorizationForUpdate(ctx context.Context, tx *sql.Tx, authorizationID string) (*models.Ticket, error) {
+	ticket, err := r.db.WithTx(tx).GetTicketByPaymentAuthorizationForUpdate(ctx, authorizationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get ticket by payment authorization: %w", err)
+	}
+	
+	result := toTicketModel(ticket)
+	return &result, nil
+}
+
+// UpdatePaymentState sets a ticket's payment status and ticket status inside tx
+func (r *TicketRepository) UpdatePaymentState(ctx context.Context, tx *sql.Tx, ticketID int64, paymentStatus string, status models.TicketStatus) error {
+	err := r.db.WithTx(tx).UpdateTicketPaymentState(ctx, db.UpdateTicketPaymentStateParams{
+		PaymentStatus: paymentStatus,
+		Status:        string(status),
+		ID:            ticketID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update ticket payment state: %w", err)
+	}
+	return nil
+}
+
+// ListDeniedBoardingCandidates returns the active tickets of a flight in the
+// order volunteers should be sought: lowest fare first, then latest booking.
+// An empty cabinClass includes every cabin.
+func (r *TicketRepository) ListDeniedBoardingCandidates(ctx context.Context, flightID int64, cabinClass string) ([]models.Ticket, error) {
+	tickets, err := r.db.Queries.ListDeniedBoardingCandidates(ctx, db.ListDeniedBoardingCandidatesParams{
+		FlightID:   flightID,
+		CabinClass: cabinClass,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list denied boarding candidates: %w", err)
+	}
+	
+	result := make([]models.Ticket, len(tickets))
+	for i, ticket := range tickets {
+		result[i] = toTicketModel(ticket)
+	}
+	
+	return result, nil
+}
+
+// ListFlightTickets returns every ticket issued on a flight, cancelled ones
+// included
+func (r *TicketRepository) ListFlightTickets(ctx context.Context, flightID int64) ([]models.Ticket, error) {
+	tickets, err := r.db.Queries.ListFlightTickets(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flight tickets: %w", err)
+	}
+	
+	result := make([]models.Ticket, len(tickets))
+	for i, ticket := range tickets {
+		result[i] = toTicketModel(ticket)
+	}
+	return result, nil
 }
 
 // ListUserTickets retrieves all tickets for a user
@@ -134,23 +294,65 @@ func (r *TicketRepository) ListUserTickets(ctx context.Context, userID string) (
 	
 	result := make([]models.Ticket, len(tickets))
 	for i, ticket := range tickets {
-		result[i] = models.Ticket{
-			ID:          ticket.ID,
-			FlightID:    ticket.FlightID,
-			SeatNo:      ticket.SeatNo,
-			UserID:      ticket.UserID,
-			PriceAmount: ticket.PriceAmount,
-			Currency:    ticket.Currency,
-			IssuedAt:    ticket.IssuedAt,
-			PNRCode:     ticket.PnrCode,
-			PaymentRef:  ticket.PaymentRef,
-			CreatedAt:   ticket.CreatedAt,
-		}
+		result[i] = toTicketModel(ticket)
+	}
+	
+	return result, nil
+}
+
+// ListLineItems retrieves a ticket's fare breakdown, inside tx if it is not
+// nil
+func (r *TicketRepository) ListLineItems(ctx context.Context, tx *sql.Tx, ticketID int64) ([]models.FareLineItem, error) {
+	queries := r.db.Queries
+	if tx != nil {
+		queries = r.db.WithTx(tx)
 	}
 	
+	items, err := queries.ListTicketLineItems(ctx, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ticket line items: %w", err)
+	}
+
+	result := make([]models.FareLineItem, len(items))
+	for i, item := range items {
+		result[i] = models.FareLineItem{
+			Component:   models.FareComponent(item.Component),
+			Code:        item.Code,
+			Description: item.Description,
+			Amount:      item.Amount,
+			BaseAmount:  item.BaseAmount,
+		}
+	}
 	return result, nil
 }
 
+func toTicketModel(ticket db.Ticket) models.Ticket {
+	result := models.Ticket{
+		ID:                     ticket.ID,
+		FlightID:               ticket.FlightID,
+		SeatNo:                 ticket.SeatNo.String,
+		CabinClass:             ticket.CabinClass.String,
+		UserID:                 ticket.UserID,
+		PriceAmount:            ticket.PriceAmount,
+		Currency:               ticket.Currency,
+		BasePriceAmount:        ticket.BasePriceAmount,
+		BaseCurrency:           ticket.BaseCurrency,
+		ExchangeRate:           ticket.ExchangeRate,
+		IssuedAt:               ticket.IssuedAt,
+		PNRCode:                ticket.PnrCode,
+		PaymentRef:             ticket.PaymentRef,
+		PaymentAuthorizationID: ticket.PaymentAuthorizationID.String,
+		PaymentStatus:          ticket.PaymentStatus,
+		Status:                 models.TicketStatus(ticket.Status),
+		CancelledAt:            ticket.CancelledAt,
+		CreatedAt:              ticket.CreatedAt,
+	}
+	if ticket.ExchangeRateEffectiveFrom.Valid {
+		result.ExchangeRateEffectiveFrom = &ticket.ExchangeRateEffectiveFrom.Time
+	}
+	return result
+}
+
 // generatePNRCode generates a random 6-character PNR code
 func (r *TicketRepository) generatePNRCode() string {
 	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
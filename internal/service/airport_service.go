@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/es"
+	"airline-booking/internal/models"
+	"airline-booking/internal/repository"
+)
+
+const (
+	defaultSuggestSize = 10
+	maxSuggestSize     = 25
+)
+
+// airportCSVHeader is the column layout of data/airports.csv
+var airportCSVHeader = []string{"iata_code", "name", "city", "country", "timezone", "latitude", "longitude", "popularity"}
+
+type AirportService struct {
+	airportRepo *repository.AirportRepository
+	esClient    *es.Client
+	logger      *zap.Logger
+}
+
+func NewAirportService(airportRepo *repository.AirportRepository, esClient *es.Client, logger *zap.Logger) *AirportService {
+	return &AirportService{
+		airportRepo: airportRepo,
+		esClient:    esClient,
+		logger:      logger,
+	}
+}
+
+// Suggest returns autocomplete suggestions for a partially typed airport code,
+// name or city
+func (s *AirportService) Suggest(ctx context.Context, req models.AirportSuggestRequest) (*models.AirportSuggestResponse, error) {
+	size := req.Size
+	if size <= 0 {
+		size = defaultSuggestSize
+	}
+	if size > maxSuggestSize {
+		size = maxSuggestSize
+	}
+
+	docs, err := s.esClient.SuggestAirports(ctx, req.Query, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest airports: %w", err)
+	}
+
+	suggestions := make([]models.AirportSuggestion, len(docs))
+	for i, doc := range docs {
+		suggestions[i] = models.AirportSuggestion{
+			IATACode: doc.IATACode,
+			Name:     doc.Name,
+			City:     doc.City,
+			Country:  doc.Country,
+		}
+	}
+
+	return &models.AirportSuggestResponse{
+		Query:       req.Query,
+		Suggestions: suggestions,
+	}, nil
+}
+
+// ImportAirports upserts airports into MySQL and indexes them for autocomplete
+func (s *AirportService) ImportAirports(ctx context.Context, airports []models.Airport) error {
+	docs := make([]es.AirportDocument, 0, len(airports))
+	for _, airport := range airports {
+		if err := s.airportRepo.UpsertAirport(ctx, airport); err != nil {
+			return err
+		}
+		docs = append(docs, es.AirportDocument{
+			IATACode:   airport.IATACode,
+			Name:       airport.Name,
+			City:       airport.City,
+			Country:    airport.Country,
+			Timezone:   airport.Timezone,
+			Latitude:   airport.Latitude,
+			Longitude:  airport.Longitude,
+			Popularity: airport.Popularity,
+		})
+	}
+
+	if err := s.esClient.BulkIndexAirports(ctx, docs); err != nil {
+		return fmt.Errorf("failed to index airports: %w", err)
+	}
+
+	s.logger.Info("Airports imported successfully", zap.Int("count", len(airports)))
+	return nil
+}
+
+// ParseAirportsCSV reads airports in the data/airports.csv layout, validating
+// codes and time zones so bad rows are rejected before anything is written
+func ParseAirportsCSV(r io.Reader) ([]models.Airport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(airportCSVHeader)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i, column := range airportCSVHeader {
+		if strings.TrimSpace(header[i]) != column {
+			return nil, fmt.Errorf("unexpected column %q at position %d, want %q", header[i], i+1, column)
+		}
+	}
+
+	var airports []models.Airport
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		airport, err := parseAirportRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		airports = append(airports, airport)
+	}
+
+	return airports, nil
+}
+
+func parseAirportRecord(record []string) (models.Airport, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+
+	code := strings.ToUpper(record[0])
+	if len(code) != 3 {
+		return models.Airport{}, fmt.Errorf("invalid IATA code %q", record[0])
+	}
+	if _, err := time.LoadLocation(record[4]); err != nil {
+		return models.Airport{}, fmt.Errorf("invalid time zone %q for %s", record[4], code)
+	}
+	latitude, err := strconv.ParseFloat(record[5], 64)
+	if err != nil {
+		return models.Airport{}, fmt.Errorf("invalid latitude for %s: %w", code, err)
+	}
+	longitude, err := strconv.ParseFloat(record[6], 64)
+	if err != nil {
+		return models.Airport{}, fmt.Errorf("invalid longitude for %s: %w", code, err)
+	}
+	popularity, err := strconv.Atoi(record[7])
+	if err != nil {
+		return models.Airport{}, fmt.Errorf("invalid popularity for %s: %w", code, err)
+	}
+
+	return models.Airport{
+		IATACode:   code,
+		Name:       record[1],
+		City:       record[2],
+		Country:    strings.ToUpper(record[3]),
+		Timezone:   record[4],
+		Latitude:   latitude,
+		Longitude:  longitude,
+		Popularity: popularity,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseAirportsCSV(t *testing.T) {
+	input := `iata_code,name,city,country,timezone,latitude,longitude,popularity
+gru,São Paulo/Guarulhos International Airport,São Paulo,br,America/Sao_Paulo,-23.435556,-46.473056,41300
+JFK,John F. Kennedy International Airport,New York,US,America/New_York,40.639801,-73.778900,62464
+`
+	airports, err := ParseAirportsCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(airports) != 2 {
+		t.Fatalf("expected 2 airports, got %d", len(airports))
+	}
+
+	gru := airports[0]
+	if gru.IATACode != "GRU" || gru.Country != "BR" {
+		t.Errorf("expected codes to be upper-cased, got %s/%s", gru.IATACode, gru.Country)
+	}
+	if gru.Popularity != 41300 {
+		t.Errorf("expected popularity 41300, got %d", gru.Popularity)
+	}
+}
+
+func TestParseAirportsCSVRejectsBadRows(t *testing.T) {
+	tests := map[string]string{
+		"bad header":    "code,name,city,country,timezone,latitude,longitude,popularity\n",
+		"bad code":      "iata_code,name,city,country,timezone,latitude,longitude,popularity\nGRUX,x,y,BR,America/Sao_Paulo,1,2,3\n",
+		"bad time zone": "iata_code,name,city,country,timezone,latitude,longitude,popularity\nGRU,x,y,BR,America/Nowhere,1,2,3\n",
+		"bad latitude":  "iata_code,name,city,country,timezone,latitude,longitude,popularity\nGRU,x,y,BR,America/Sao_Paulo,north,2,3\n",
+	}
+
+	for name, input := range tests {
+		if _, err := ParseAirportsCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBundledAirportsCSV(t *testing.T) {
+	f, err := os.Open("../../data/airports.csv")
+	if err != nil {
+		t.Fatalf("failed to open bundled airports file: %v", err)
+	}
+	defer f.Close()
+
+	airports, err := ParseAirportsCSV(f)
+	if err != nil {
+		t.Fatalf("bundled airports file is invalid: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, airport := range airports {
+		if seen[airport.IATACode] {
+			t.Errorf("duplicate airport %s", airport.IATACode)
+		}
+		seen[airport.IATACode] = true
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/repository"
+)
+
+var (
+	// ErrAncillaryNotOffered is returned for an ancillary code that is not
+	// sold on the flight
+	ErrAncillaryNotOffered = errors.New("ancillary not offered on this flight")
+	// ErrAncillaryLimitExceeded is returned when a ticket would hold more
+	// units of an ancillary than its per-ticket limit
+	ErrAncillaryLimitExceeded = errors.New("ancillary per-ticket limit exceeded")
+	// ErrAncillarySoldOut is returned when a flight has too few units left
+	ErrAncillarySoldOut = errors.New("ancillary sold out on this flight")
+)
+
+// AncillaryService prices the extras sold with tickets and keeps their
+// per-flight inventory
+type AncillaryService struct {
+	ancillaryRepo *repository.AncillaryRepository
+	rateService   *ExchangeRateService
+	logger        *zap.Logger
+}
+
+func NewAncillaryService(
+	ancillaryRepo *repository.AncillaryRepository,
+	rateService *ExchangeRateService,
+	logger *zap.Logger,
+) *AncillaryService {
+	return &AncillaryService{
+		ancillaryRepo: ancillaryRepo,
+		rateService:   rateService,
+		logger:        logger,
+	}
+}
+
+// Catalog lists the ancillaries offered on flight, priced in currency
+func (s *AncillaryService) Catalog(ctx context.Context, flight *models.Flight, currency string) (*models.FlightAncillariesResponse, error) {
+	rate, err := s.rateService.Quote(ctx, currency, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	ancillaries, err := s.ancillaryRepo.ListForFlight(ctx, flight)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]models.AncillaryOffer, 0, len(ancillaries))
+	for _, ancillary := range ancillaries {
+		price, err := s.rateService.Convert(ancillary.PriceAmount, rate)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, models.AncillaryOffer{
+			Code:         ancillary.Code,
+			Category:     ancillary.Category,
+			Name:         ancillary.Name,
+			Price:        price,
+			Currency:     rate.QuoteCurrency,
+			MaxPerTicket: ancillary.MaxPerTicket,
+			Remaining:    ancillary.Remaining(),
+		})
+	}
+
+	return &models.FlightAncillariesResponse{
+		FlightID:    flight.ID,
+		Ancillaries: offers,
+	}, nil
+}
+
+// Select checks selections against what flight offers and the ticket's
+// owned line items and returns one ancillary line per unit, priced in the
+// base currency. Inventory is only checked here; it is taken by Reserve.
+func (s *AncillaryService) Select(ctx context.Context, flight *models.Flight, selections []models.AncillarySelection, owned []models.FareLineItem) ([]models.FareLineItem, error) {
+	if len(selections) == 0 {
+		return nil, nil
+	}
+
+	catalog, err := s.ancillaryRepo.ListForFlight(ctx, flight)
+	if err != nil {
+		return nil, err
+	}
+	return ancillaryLineItems(catalog, selections, owned)
+}
+
+// Reserve takes the units of limited ancillaries among items from flight's
+// inventory inside tx, returning ErrAncillarySoldOut if any has run out
+func (s *AncillaryService) Reserve(ctx context.Context, tx *sql.Tx, flight *models.Flight, items []models.FareLineItem) error {
+	units := countAncillaryUnits(items)
+	if len(units) == 0 {
+		return nil
+	}
+
+	catalog, err := s.ancillaryRepo.ListForFlight(ctx, flight)
+	if err != nil {
+		return err
+	}
+
+	for _, ancillary := range catalog {
+		quantity := units[ancillary.Code]
+		if quantity == 0 || ancillary.CapacityPerFlight == nil {
+			continue
+		}
+		reserved, err := s.ancillaryRepo.ReserveUnits(ctx, tx, flight.ID, ancillary.ID, quantity)
+		if err != nil {
+			return err
+		}
+		if !reserved {
+			return fmt.Errorf("%w: %s", ErrAncillarySoldOut, ancillary.Code)
+		}
+	}
+	return nil
+}
+
+// Release returns a cancelled ticket's ancillary units to its flight's
+// inventory inside tx
+func (s *AncillaryService) Release(ctx context.Context, tx *sql.Tx, ticket *models.Ticket) error {
+	return s.ancillaryRepo.ReleaseTicketUnits(ctx, tx, ticket.ID, ticket.FlightID)
+}
+
+// ancillaryLineItems prices selections against catalog, one line per unit in
+// the order selected. owned holds the ticket's existing line items, which
+// count towards each ancillary's per-ticket limit.
+func ancillaryLineItems(catalog []models.Ancillary, selections []models.AncillarySelection, owned []models.FareLineItem) ([]models.FareLineItem, error) {
+	byCode := make(map[string]models.Ancillary, len(catalog))
+	for _, ancillary := range catalog {
+		byCode[ancillary.Code] = ancillary
+	}
+
+	held := countAncillaryUnits(owned)
+	requested := make(map[string]int)
+	var items []models.FareLineItem
+
+	for _, selection := range selections {
+		code := strings.ToUpper(strings.TrimSpace(selection.Code))
+		ancillary, ok := byCode[code]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrAncillaryNotOffered, code)
+		}
+
+		quantity := selection.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		requested[code] += quantity
+
+		if held[code]+requested[code] > ancillary.MaxPerTicket {
+			return nil, fmt.Errorf("%w: at most %d %s per ticket", ErrAncillaryLimitExceeded, ancillary.MaxPerTicket, code)
+		}
+		if remaining := ancillary.Remaining(); remaining != nil && requested[code] > *remaining {
+			return nil, fmt.Errorf("%w: %s", ErrAncillarySoldOut, code)
+		}
+
+		for i := 0; i < quantity; i++ {
+			items = append(items, models.FareLineItem{
+				Component:   models.FareComponentAncillary,
+				Code:        ancillary.Code,
+				Description: ancillary.Name,
+				BaseAmount:  ancillary.PriceAmount,
+			})
+		}
+	}
+
+	return items, nil
+}
+
+// countAncillaryUnits returns the ancillary units among items per code
+func countAncillaryUnits(items []models.FareLineItem) map[string]int {
+	units := make(map[string]int)
+	for _, item := range items {
+		if item.Component == models.FareComponentAncillary {
+			units[item.Code]++
+		}
+	}
+	return units
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"airline-booking/internal/models"
+)
+
+func TestAncillaryLineItems(t *testing.T) {
+	legroomCapacity := 12
+	catalog := []models.Ancillary{
+		{Code: "CHECKED_BAG", Name: "Checked bag", PriceAmount: 4500, MaxPerTicket: 3},
+		{Code: "EXTRA_LEGROOM", Name: "Extra legroom", PriceAmount: 3900, MaxPerTicket: 1, CapacityPerFlight: &legroomCapacity, Sold: 11},
+	}
+
+	items, err := ancillaryLineItems(catalog, []models.AncillarySelection{
+		{Code: "checked_bag", Quantity: 2},
+		{Code: "EXTRA_LEGROOM"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"CHECKED_BAG", "CHECKED_BAG", "EXTRA_LEGROOM"}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, item := range items {
+		if item.Component != models.FareComponentAncillary || item.Code != want[i] {
+			t.Errorf("item %d: got %s %s, want ancillary %s", i, item.Component, item.Code, want[i])
+		}
+	}
+	if items[0].BaseAmount != 4500 || items[2].BaseAmount != 3900 {
+		t.Errorf("expected unit prices, got %d and %d", items[0].BaseAmount, items[2].BaseAmount)
+	}
+}
+
+func TestAncillaryLineItemsRejections(t *testing.T) {
+	legroomCapacity := 12
+	catalog := []models.Ancillary{
+		{Code: "CHECKED_BAG", PriceAmount: 4500, MaxPerTicket: 3},
+		{Code: "EXTRA_LEGROOM", PriceAmount: 3900, MaxPerTicket: 2, CapacityPerFlight: &legroomCapacity, Sold: 11},
+	}
+	twoBags := []models.FareLineItem{
+		{Component: models.FareComponentAncillary, Code: "CHECKED_BAG"},
+		{Component: models.FareComponentAncillary, Code: "CHECKED_BAG"},
+	}
+
+	tests := []struct {
+		name       string
+		selections []models.AncillarySelection
+		owned      []models.FareLineItem
+		want       error
+	}{
+		{"unknown code", []models.AncillarySelection{{Code: "LOUNGE_ACCESS"}}, nil, ErrAncillaryNotOffered},
+		{"over the per-ticket limit", []models.AncillarySelection{{Code: "CHECKED_BAG", Quantity: 4}}, nil, ErrAncillaryLimitExceeded},
+		{"limit counts repeated selections", []models.AncillarySelection{{Code: "CHECKED_BAG", Quantity: 2}, {Code: "CHECKED_BAG", Quantity: 2}}, nil, ErrAncillaryLimitExceeded},
+		{"limit counts units already bought", []models.AncillarySelection{{Code: "CHECKED_BAG", Quantity: 2}}, twoBags, ErrAncillaryLimitExceeded},
+		{"more than the flight has left", []models.AncillarySelection{{Code: "EXTRA_LEGROOM", Quantity: 2}}, nil, ErrAncillarySoldOut},
+	}
+
+	for _, tt := range tests {
+		if _, err := ancillaryLineItems(catalog, tt.selections, tt.owned); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/repository"
+)
+
+// ErrInvalidAuditFilter is returned when an audit query's time range is empty
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+// RequestInfo identifies the request a change was made in, for the audit log
+type RequestInfo struct {
+	ID string
+	IP string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns ctx carrying info, recorded with every audit
+// event written under it
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the request info ctx carries, zero outside a
+// request
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// AuditService writes the audit log of booking state changes and serves it
+// to operators
+type AuditService struct {
+	auditRepo *repository.AuditRepository
+	logger    *zap.Logger
+}
+
+func NewAuditService(auditRepo *repository.AuditRepository, logger *zap.Logger) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// Record appends event inside tx with before and after as its JSON
+// snapshots, nil for a hold or ticket that did not exist. The request ID and
+// IP are taken from ctx.
+func (s *AuditService) Record(ctx context.Context, tx *sql.Tx, event models.AuditEvent, before, after interface{}) error {
+	var err error
+	if event.Before, err = snapshotJSON(before); err != nil {
+		return err
+	}
+	if event.After, err = snapshotJSON(after); err != nil {
+		return err
+	}
+
+	info := RequestInfoFrom(ctx)
+	event.RequestID = info.ID
+	event.IPAddress = info.IP
+	return s.auditRepo.Record(ctx, tx, event)
+}
+
+// ListEvents returns the events matching filter, newest first
+func (s *AuditService) ListEvents(ctx context.Context, filter models.AuditEventFilter) (*models.AuditEventsResponse, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidAuditFilter)
+	}
+	filter.PNRCode = strings.ToUpper(filter.PNRCode)
+
+	events, err := s.auditRepo.ListEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuditEventsResponse{
+		Events: events,
+		Count:  len(events),
+	}, nil
+}
+
+func snapshotJSON(snapshot interface{}) (json.RawMessage, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// holdSnapshot is the audited state of a seat hold
+type holdSnapshot struct {
+	Status     models.HoldStatus `json:"status"`
+	HolderID   string            `json:"holder_id"`
+	ExpiresAt  *time.Time        `json:"expires_at,omitempty"`
+	PromoCodes []string          `json:"promo_codes,omitempty"`
+}
+
+// snapshotHold returns hold's audited state, nil without a hold
+func snapshotHold(hold *models.SeatLock) interface{} {
+	if hold == nil {
+		return nil
+	}
+	return holdSnapshot{
+		Status:    hold.Status,
+		HolderID:  hold.HolderID,
+		ExpiresAt: hold.ExpiresAt,
+	}
+}
+
+// ticketSnapshot is the audited state of a ticket
+type ticketSnapshot struct {
+	Status        models.TicketStatus `json:"status"`
+	UserID        string              `json:"user_id"`
+	SeatNo        string              `json:"seat_no,omitempty"`
+	CabinClass    string              `json:"cabin_class"`
+	PriceAmount   int64               `json:"price_amount"`
+	Currency      string              `json:"currency"`
+	PaymentStatus string              `json:"payment_status,omitempty"`
+	Ancillaries   []string            `json:"ancillaries,omitempty"`
+}
+
+func snapshotTicket(ticket *models.Ticket, lineItems []models.FareLineItem) ticketSnapshot {
+	snapshot := ticketSnapshot{
+		Status:        ticket.Status,
+		UserID:        ticket.UserID,
+		SeatNo:        ticket.SeatNo,
+		CabinClass:    ticket.CabinClass,
+		PriceAmount:   ticket.PriceAmount,
+		Currency:      ticket.Currency,
+		PaymentStatus: ticket.PaymentStatus,
+	}
+	for _, item := range lineItems {
+		if item.Component == models.FareComponentAncillary {
+			snapshot.Ancillaries = append(snapshot.Ancillaries, item.Code)
+		}
+	}
+	return snapshot
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"airline-booking/internal/models"
+)
+
+func TestSnapshotJSON(t *testing.T) {
+	data, err := snapshotJSON(nil)
+	if err != nil || data != nil {
+		t.Fatalf("got %s, %v for a nil snapshot, want no data", data, err)
+	}
+
+	if got := snapshotHold(nil); got != nil {
+		t.Fatalf("got %v for a missing hold, want nil", got)
+	}
+
+	expiresAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	data, err = snapshotJSON(snapshotHold(&models.SeatLock{
+		HolderID:  "user-1",
+		Status:    models.HoldStatusActive,
+		ExpiresAt: &expiresAt,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"status":"active","holder_id":"user-1","expires_at":"2026-03-01T12:00:00Z"}`
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestSnapshotTicketListsAncillaries(t *testing.T) {
+	ticket := &models.Ticket{
+		Status:      models.TicketStatusConfirmed,
+		UserID:      "user-1",
+		SeatNo:      "12A",
+		CabinClass:  "economy",
+		PriceAmount: 34400,
+		Currency:    "USD",
+	}
+	snapshot := snapshotTicket(ticket, []models.FareLineItem{
+		{Component: models.FareComponentBaseFare, Code: "BASE"},
+		{Component: models.FareComponentAncillary, Code: "CHECKED_BAG"},
+		{Component: models.FareComponentAncillary, Code: "CHECKED_BAG"},
+	})
+
+	if len(snapshot.Ancillaries) != 2 || snapshot.Ancillaries[0] != "CHECKED_BAG" {
+		t.Errorf("got ancillaries %v, want two CHECKED_BAG", snapshot.Ancillaries)
+	}
+	if snapshot.Status != models.TicketStatusConfirmed || snapshot.PriceAmount != 34400 {
+		t.Errorf("got %+v, want the ticket's status and price", snapshot)
+	}
+}
+
+func TestRequestInfoContext(t *testing.T) {
+	if info := RequestInfoFrom(context.Background()); info != (RequestInfo{}) {
+		t.Errorf("got %+v without request info, want zero", info)
+	}
+
+	ctx := WithRequestInfo(context.Background(), RequestInfo{ID: "req-1", IP: "203.0.113.7"})
+	if info := RequestInfoFrom(ctx); info.ID != "req-1" || info.IP != "203.0.113.7" {
+		t.Errorf("got %+v, want the request's ID and IP", info)
+	}
+}
+
+func TestListEventsRejectsEmptyRange(t *testing.T) {
+	s := &AuditService{}
+	now := time.Now().UTC()
+
+	_, err := s.ListEvents(context.Background(), models.AuditEventFilter{From: now, To: now.Add(-time.Hour), Limit: 10})
+	if !errors.Is(err, ErrInvalidAuditFilter) {
+		t.Errorf("got %v, want ErrInvalidAuditFilter", err)
+	}
+}
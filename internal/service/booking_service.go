@@ -4,46 +4,99 @@ import (
 	"context"
 	"crypto/sha256"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"airline-booking/internal/config"
+	"airline-booking/internal/currency"
 	"airline-booking/internal/db"
 	"airline-booking/internal/es"
 	"airline-booking/internal/models"
+	"airline-booking/internal/metrics"
+	"airline-booking/internal/payment"
 	"airline-booking/internal/repository"
 )
 
+var (
+	// ErrUnknownAirport is returned when an IATA code is not in the airports table
+	ErrUnknownAirport = errors.New("unknown airport")
+	// ErrInvalidDate is returned when a search date is not in YYYY-MM-DD format
+	ErrInvalidDate = errors.New("invalid date")
+	// ErrTicketNotFound is returned when no ticket matches a PNR
+	ErrTicketNotFound = errors.New("ticket not found")
+	// ErrTicketNotOwned is returned when a user acts on another user's ticket
+	ErrTicketNotOwned = errors.New("ticket belongs to another user")
+	// ErrCabinFull is returned when a cabin has no seat left to sell, even
+	// counting its overbooking allowance
+	ErrCabinFull = errors.New("cabin is sold out, including overbooking allowance")
+	// ErrInvalidBasePrice is returned for a flight base price that is not a
+	// positive amount in the base currency's precision
+	ErrInvalidBasePrice = errors.New("invalid base price")
+	// ErrTicketNotActive is returned when changing a cancelled or suspended
+	// ticket, or one whose flight has departed
+	ErrTicketNotActive = errors.New("ticket is not active")
+)
+
+// localDateTimeLayout is accepted for flight times given without a UTC offset
+const localDateTimeLayout = "2006-01-02T15:04:05"
+
 type BookingService struct {
-	seatRepo   *repository.SeatRepository
-	ticketRepo *repository.TicketRepository
-	flightRepo *repository.FlightRepository
-	esClient   *es.Client
-	db         *db.Database
-	config     *config.Config
-	logger     *zap.Logger
+	seatRepo      *repository.SeatRepository
+	ticketRepo    *repository.TicketRepository
+	flightRepo    *repository.FlightRepository
+	airportRepo   *repository.AirportRepository
+	inventoryRepo  *repository.InventoryRepository
+	fareRuleRepo   *repository.FareRuleRepository
+	paymentGateway payment.PaymentGateway
+	rateService    *ExchangeRateService
+	promoService   *PromotionService
+	ancillaryService *AncillaryService
+	auditService   *AuditService
+	esClient       *es.Client
+	db             *db.Database
+	config         *config.Config
+	logger         *zap.Logger
 }
 
 func NewBookingService(
 	seatRepo *repository.SeatRepository,
 	ticketRepo *repository.TicketRepository,
 	flightRepo *repository.FlightRepository,
+	airportRepo *repository.AirportRepository,
+	inventoryRepo *repository.InventoryRepository,
+	fareRuleRepo *repository.FareRuleRepository,
+	paymentGateway payment.PaymentGateway,
+	rateService *ExchangeRateService,
+	promoService *PromotionService,
+	ancillaryService *AncillaryService,
+	auditService *AuditService,
 	esClient *es.Client,
 	database *db.Database,
 	cfg *config.Config,
 	logger *zap.Logger,
 ) *BookingService {
 	return &BookingService{
-		seatRepo:   seatRepo,
-		ticketRepo: ticketRepo,
-		flightRepo: flightRepo,
-		esClient:   esClient,
-		db:         database,
-		config:     cfg,
-		logger:     logger,
+		seatRepo:       seatRepo,
+		ticketRepo:     ticketRepo,
+		flightRepo:     flightRepo,
+		airportRepo:    airportRepo,
+		inventoryRepo:  inventoryRepo,
+		fareRuleRepo:   fareRuleRepo,
+		paymentGateway: paymentGateway,
+		rateService:    rateService,
+		promoService:   promoService,
+		ancillaryService: ancillaryService,
+		auditService:   auditService,
+		esClient:       esClient,
+		db:             database,
+		config:         cfg,
+		logger:         logger,
 	}
 }
 
@@ -62,7 +115,15 @@ func (s *BookingService) CreateHold(ctx context.Context, req models.CreateHoldRe
 		return nil, fmt.Errorf("failed to get flight: %w", err)
 	}
 	if flight == nil {
-		return nil, fmt.Errorf("flight not found")
+		return nil, ErrFlightNotFound
+	}
+	
+	seat, err := s.seatRepo.GetSeat(ctx, req.FlightID, req.SeatNo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat: %w", err)
+	}
+	if seat == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSeatNotFound, req.SeatNo)
 	}
 	
 	// Check if seat is already ticketed
@@ -76,17 +137,75 @@ func (s *BookingService) CreateHold(ctx context.Context, req models.CreateHoldRe
 		return nil, fmt.Errorf("failed to check existing ticket: %w", err)
 	}
 	if existingTicket != nil {
-		return nil, fmt.Errorf("seat is already sold")
+		metrics.HoldConflicts.Inc()
+		return nil, ErrSeatSold
+	}
+	
+	promotions, err := s.promoService.Validate(ctx, req.PromoCodes, flight, holderID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	
+	var promoCodes []string
+	for _, promotion := range promotions {
+		promoCodes = append(promoCodes, promotion.Code)
+	}
+	
+	// An expired hold may be taken over, or the holder's own extended
+	previous, err := s.seatRepo.GetHold(ctx, req.FlightID, req.SeatNo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
 	}
 	
 	// Calculate expiration time
-	expiresAt := time.Now().UTC().Add(s.config.Hold.TTL)
+	expiresAt := time.Now().UTC().Add(s.config.Live().Hold.TTL)
+	
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 	
 	// Attempt to create hold
-	err = s.seatRepo.CreateHold(ctx, req.FlightID, req.SeatNo, holderID, expiresAt)
+	created, err := s.seatRepo.CreateHold(ctx, tx, req.FlightID, req.SeatNo, holderID, expiresAt)
+	if errors.Is(err, repository.ErrSeatHeld) {
+		metrics.HoldConflicts.Inc()
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create hold: %w", err)
 	}
+	
+	// Extensions and takeovers of an expired hold are already counted as held
+	if created {
+		if err := s.inventoryRepo.AdjustForSeat(ctx, tx, req.FlightID, req.SeatNo, 1, 0); err != nil {
+			return nil, fmt.Errorf("failed to create hold: %w", err)
+		}
+	}
+	
+	if err := s.promoService.AttachToHold(ctx, tx, req.FlightID, req.SeatNo, holderID, promotions); err != nil {
+		return nil, fmt.Errorf("failed to create hold: %w", err)
+	}
+	
+	event := models.AuditEvent{
+		Actor:    holderID,
+		Action:   models.AuditActionHoldCreated,
+		FlightID: req.FlightID,
+		SeatNo:   req.SeatNo,
+	}
+	after := holdSnapshot{
+		Status:     models.HoldStatusActive,
+		HolderID:   holderID,
+		ExpiresAt:  &expiresAt,
+		PromoCodes: promoCodes,
+	}
+	if err := s.auditService.Record(ctx, tx, event, snapshotHold(previous), after); err != nil {
+		return nil, err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
 	// Get the created hold for indexing
 	hold, err := s.seatRepo.GetHold(ctx, req.FlightID, req.SeatNo)
@@ -117,7 +236,8 @@ func (s *BookingService) CreateHold(ctx context.Context, req models.CreateHoldRe
 		FlightID:  req.FlightID,
 		SeatNo:    req.SeatNo,
 		HolderID:  holderID,
-		ExpiresAt: expiresAt,
+		ExpiresAt:  expiresAt,
+		PromoCodes: promoCodes,
 	}
 	
 	// Store idempotency key if provided
@@ -127,6 +247,7 @@ func (s *BookingService) CreateHold(ctx context.Context, req models.CreateHoldRe
 		}
 	}
 	
+	metrics.HoldsCreated.Inc()
 	s.logger.Info("Hold created successfully",
 		zap.Int64("flight_id", req.FlightID),
 		zap.String("seat_no", req.SeatNo),
@@ -135,7 +256,13 @@ func (s *BookingService) CreateHold(ctx context.Context, req models.CreateHoldRe
 	return response, nil
 }
 
-// ConfirmTicket confirms a hold and creates a ticket
+// ConfirmTicket confirms a hold and creates a ticket. The hold moves to
+// payment_pending, extended by the payment TTL, while the fare is authorized;
+// the ticket is then issued and the payment captured once it is committed.
+// A declined or challenged payment returns the hold to active, and the
+// authorization is voided if the ticket cannot be issued. A payment timeout
+// leaves the hold pending, since the provider may still approve it; the
+// cleanup job releases it if nothing resolves it within the payment TTL.
 func (s *BookingService) ConfirmTicket(ctx context.Context, req models.ConfirmTicketRequest, userID, idempotencyKey string) (*models.ConfirmTicketResponse, error) {
 	// Check idempotency if key provided
 	if idempotencyKey != "" {
@@ -150,41 +277,73 @@ func (s *BookingService) ConfirmTicket(ctx context.Context, req models.ConfirmTi
 		return nil, fmt.Errorf("failed to get flight: %w", err)
 	}
 	if flight == nil {
-		return nil, fmt.Errorf("flight not found")
+		return nil, ErrFlightNotFound
 	}
 	
-	// Start transaction
-	tx, err := s.db.BeginTx()
+	seat, err := s.seatRepo.GetSeat(ctx, req.FlightID, req.SeatNo)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to get seat: %w", err)
+	}
+	cabinClass := ""
+	if seat != nil {
+		cabinClass = seat.Class
 	}
-	defer tx.Rollback()
 	
-	// Confirm the hold (this will set expires_at to NULL)
-	err = s.seatRepo.ConfirmHold(ctx, req.FlightID, req.SeatNo, userID)
+	ticket := models.Ticket{
+		FlightID:   req.FlightID,
+		SeatNo:     req.SeatNo,
+		CabinClass: cabinClass,
+		UserID:     userID,
+		PaymentRef: req.PaymentRef,
+	}
+	
+	// Codes applied at hold time must still be valid when the ticket is sold
+	promotions, err := s.promoService.HoldPromotions(ctx, flight, req.SeatNo, userID, time.Now().UTC())
 	if err != nil {
-		return nil, fmt.Errorf("failed to confirm hold: %w", err)
+		return nil, err
+	}
+	ancillaries, err := s.ancillaryService.Select(ctx, flight, req.Ancillaries, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.priceTicket(ctx, &ticket, flight, req.Currency, promotions, ancillaries); err != nil {
+		return nil, err
 	}
 	
-	// Create ticket
-	ticket := models.Ticket{
-		FlightID:    req.FlightID,
-		SeatNo:      req.SeatNo,
-		UserID:      userID,
-		PriceAmount: 29900, // $299.00 in cents
-		Currency:    "USD",
-		PaymentRef:  req.PaymentRef,
+	// Don't charge for a seat the user doesn't hold
+	hold, err := s.seatRepo.GetHold(ctx, req.FlightID, req.SeatNo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	if hold == nil || hold.HolderID != userID || !hold.Live(time.Now().UTC()) {
+		return nil, ErrNoValidHold
 	}
 	
-	createdTicket, err := s.ticketRepo.CreateTicket(ctx, tx, ticket)
+	if err := s.markPaymentPending(ctx, hold, time.Now().UTC().Add(s.config.Live().Hold.PaymentTTL)); err != nil {
+		return nil, err
+	}
+	
+	reference := fmt.Sprintf("hold-%d", hold.ID)
+	authorization, err := s.authorizePayment(ctx, ticket, reference, req.ChallengeID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create ticket: %w", err)
+		if !errors.Is(err, payment.ErrTimeout) {
+			s.revertHold(ctx, ticket)
+		}
+		return nil, err
 	}
+	ticket.PaymentAuthorizationID = authorization.ID
+	ticket.PaymentStatus = string(authorization.Status)
 	
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	createdTicket, err := s.issueSeatTicket(ctx, flight, ticket, promotions)
+	if err != nil {
+		s.voidPayment(ctx, authorization.ID)
+		s.revertHold(ctx, ticket)
+		return nil, err
 	}
+	
+	s.capturePayment(ctx, createdTicket)
+	metrics.TicketsIssued.WithLabelValues("seat").Inc()
+	metrics.Revenue.WithLabelValues(createdTicket.Currency).Add(float64(createdTicket.PriceAmount))
 
 	// Index ticket in Elasticsearch
 	ticketDoc := es.TicketDocument{
@@ -216,11 +375,20 @@ func (s *BookingService) ConfirmTicket(ctx context.Context, req models.ConfirmTi
 	}
 	
 	response := &models.ConfirmTicketResponse{
-		TicketID:   createdTicket.ID,
-		FlightID:   createdTicket.FlightID,
-		SeatNo:     createdTicket.SeatNo,
-		PNRCode:    createdTicket.PNRCode,
-		PaymentRef: createdTicket.PaymentRef,
+		TicketID:               createdTicket.ID,
+		FlightID:               createdTicket.FlightID,
+		SeatNo:                 createdTicket.SeatNo,
+		CabinClass:             createdTicket.CabinClass,
+		PNRCode:                createdTicket.PNRCode,
+		PaymentRef:             createdTicket.PaymentRef,
+		PaymentAuthorizationID: createdTicket.PaymentAuthorizationID,
+		PaymentStatus:          createdTicket.PaymentStatus,
+		PriceAmount:            createdTicket.PriceAmount,
+		Currency:               createdTicket.Currency,
+		BasePriceAmount:        createdTicket.BasePriceAmount,
+		BaseCurrency:           createdTicket.BaseCurrency,
+		ExchangeRate:           createdTicket.ExchangeRate,
+		LineItems:              createdTicket.LineItems,
 	}
 	
 	// Store idempotency key if provided
@@ -239,6 +407,118 @@ func (s *BookingService) ConfirmTicket(ctx context.Context, req models.ConfirmTi
 	return response, nil
 }
 
+// ConfirmSeatlessTicket sells a ticket without a seat assignment against the
+// cabin's overbooking allowance. The seat is assigned at check-in.
+func (s *BookingService) ConfirmSeatlessTicket(ctx context.Context, req models.ConfirmSeatlessTicketRequest, userID, idempotencyKey string) (*models.ConfirmTicketResponse, error) {
+	// Check idempotency if key provided
+	if idempotencyKey != "" {
+		if response, err := s.checkIdempotency(ctx, idempotencyKey, "POST /tickets/seatless", userID); err == nil && response != nil {
+			return response.(*models.ConfirmTicketResponse), nil
+		}
+	}
+	req.CabinClass = strings.ToLower(req.CabinClass)
+	
+	// Validate flight exists
+	flight, err := s.flightRepo.GetFlight(ctx, req.FlightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, ErrFlightNotFound
+	}
+	
+	ticket := models.Ticket{
+		FlightID:   req.FlightID,
+		CabinClass: req.CabinClass,
+		UserID:     userID,
+		PaymentRef: req.PaymentRef,
+	}
+	
+	promotions, err := s.promoService.Validate(ctx, req.PromoCodes, flight, userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	ancillaries, err := s.ancillaryService.Select(ctx, flight, req.Ancillaries, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.priceTicket(ctx, &ticket, flight, req.Currency, promotions, ancillaries); err != nil {
+		return nil, err
+	}
+	
+	// Without an idempotency key every request is a new purchase
+	reference := ""
+	if idempotencyKey != "" {
+		reference = fmt.Sprintf("seatless-%s-%s", userID, idempotencyKey)
+	}
+	authorization, err := s.authorizePayment(ctx, ticket, reference, req.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	ticket.PaymentAuthorizationID = authorization.ID
+	ticket.PaymentStatus = string(authorization.Status)
+	
+	createdTicket, err := s.issueSeatlessTicket(ctx, flight, ticket, promotions)
+	if err != nil {
+		s.voidPayment(ctx, authorization.ID)
+		return nil, err
+	}
+	
+	s.capturePayment(ctx, createdTicket)
+	metrics.TicketsIssued.WithLabelValues("seatless").Inc()
+	metrics.Revenue.WithLabelValues(createdTicket.Currency).Add(float64(createdTicket.PriceAmount))
+	
+	ticketDoc := es.TicketDocument{
+		ID:          createdTicket.ID,
+		FlightID:    createdTicket.FlightID,
+		UserID:      createdTicket.UserID,
+		PriceAmount: createdTicket.PriceAmount,
+		Currency:    createdTicket.Currency,
+		IssuedAt:    createdTicket.IssuedAt,
+		PnrCode:     createdTicket.PNRCode,
+		PaymentRef:  createdTicket.PaymentRef,
+		CreatedAt:   createdTicket.CreatedAt,
+		Status:      "confirmed",
+	}
+	
+	if err := s.esClient.IndexTicket(ctx, ticketDoc); err != nil {
+		s.logger.Error("Failed to index ticket in Elasticsearch", 
+			zap.Error(err),
+			zap.Int64("ticket_id", createdTicket.ID))
+		// Don't fail the request if ES indexing fails
+	}
+	
+	response := &models.ConfirmTicketResponse{
+		TicketID:               createdTicket.ID,
+		FlightID:               createdTicket.FlightID,
+		CabinClass:             createdTicket.CabinClass,
+		PNRCode:                createdTicket.PNRCode,
+		PaymentRef:             createdTicket.PaymentRef,
+		PaymentAuthorizationID: createdTicket.PaymentAuthorizationID,
+		PaymentStatus:          createdTicket.PaymentStatus,
+		PriceAmount:            createdTicket.PriceAmount,
+		Currency:               createdTicket.Currency,
+		BasePriceAmount:        createdTicket.BasePriceAmount,
+		BaseCurrency:           createdTicket.BaseCurrency,
+		ExchangeRate:           createdTicket.ExchangeRate,
+		LineItems:              createdTicket.LineItems,
+	}
+	
+	if idempotencyKey != "" {
+		if err := s.storeIdempotency(ctx, idempotencyKey, "POST /tickets/seatless", userID, response); err != nil {
+			s.logger.Warn("Failed to store idempotency key", zap.Error(err))
+		}
+	}
+	
+	s.logger.Info("Seatless ticket confirmed successfully",
+		zap.Int64("ticket_id", createdTicket.ID),
+		zap.String("pnr_code", createdTicket.PNRCode),
+		zap.Int64("flight_id", req.FlightID),
+		zap.String("cabin_class", req.CabinClass))
+	
+	return response, nil
+}
+
 // ReleaseHold releases a hold for a specific user
 func (s *BookingService) ReleaseHold(ctx context.Context, flightID int64, seatNo, holderID string) error {
 	// Get hold before releasing to get the ID for ES deletion
@@ -247,13 +527,40 @@ func (s *BookingService) ReleaseHold(ctx context.Context, flightID int64, seatNo
 		s.logger.Warn("Failed to get hold for ES deletion", zap.Error(err))
 	}
 
-	err = s.seatRepo.ReleaseHold(ctx, flightID, seatNo, holderID)
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	released, err := s.seatRepo.ReleaseHold(ctx, tx, flightID, seatNo, holderID)
 	if err != nil {
 		return fmt.Errorf("failed to release hold: %w", err)
 	}
 	
+	if released {
+		if err := s.inventoryRepo.AdjustForSeat(ctx, tx, flightID, seatNo, -1, 0); err != nil {
+			return fmt.Errorf("failed to release hold: %w", err)
+		}
+		
+		event := models.AuditEvent{
+			Actor:    holderID,
+			Action:   models.AuditActionHoldReleased,
+			FlightID: flightID,
+			SeatNo:   seatNo,
+		}
+		after := holdSnapshot{Status: models.HoldStatusReleased, HolderID: holderID}
+		if err := s.auditService.Record(ctx, tx, event, snapshotHold(hold), after); err != nil {
+			return err
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	
 	// Delete hold from Elasticsearch if we got the hold info
-	if hold != nil {
+	if released && hold != nil {
 		if err := s.esClient.DeleteHold(ctx, hold.ID); err != nil {
 			s.logger.Warn("Failed to delete hold from Elasticsearch", 
 				zap.Error(err),
@@ -270,166 +577,979 @@ func (s *BookingService) ReleaseHold(ctx context.Context, flightID int64, seatNo
 	return nil
 }
 
-// GetFlightSeatAvailability returns seat availability for a flight
-func (s *BookingService) GetFlightSeatAvailability(ctx context.Context, flightID int64) ([]models.SeatAvailability, error) {
-	availability, err := s.seatRepo.GetFlightSeatAvailability(ctx, flightID)
+// CancelTicket cancels a ticket by PNR, returning its seat to the flight's
+// inventory. Cancelling an already cancelled ticket is a no-op.
+func (s *BookingService) CancelTicket(ctx context.Context, pnrCode, userID string) (*models.CancelTicketResponse, error) {
+	tx, err := s.db.BeginTx()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get seat availability: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 	
-	return availability, nil
-}
-
-// SearchFlights searches for flights using Elasticsearch
-func (s *BookingService) SearchFlights(ctx context.Context, req models.FlightSearchRequest) (*models.FlightSearchResponse, error) {
-	// Search in Elasticsearch
-	esResponse, err := s.esClient.SearchFlights(ctx, req)
+	// Locks the ticket, so the state audited is the one cancelled
+	previous, err := s.ticketRepo.GetTicketByPNRForUpdate(ctx, tx, strings.ToUpper(pnrCode))
 	if err != nil {
-		return nil, fmt.Errorf("failed to search flights in elasticsearch: %w", err)
+		return nil, fmt.Errorf("failed to cancel ticket: %w", err)
+	}
+	if previous == nil {
+		return nil, ErrTicketNotFound
 	}
 	
-	// For each flight, get available seat count by checking locks and tickets
-	for i := range esResponse.Flights {
-		flight := &esResponse.Flights[i]
-		
-		availability, err := s.seatRepo.GetFlightSeatAvailability(ctx, flight.ID)
-		if err != nil {
-			s.logger.Warn("Failed to get seat availability for flight",
-				zap.Int64("flight_id", flight.ID),
-				zap.Error(err))
-			continue
+	ticket, cancelled, err := s.ticketRepo.CancelTicket(ctx, tx, previous.PNRCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel ticket: %w", err)
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.UserID != userID {
+		return nil, ErrTicketNotOwned
+	}
+	
+	if cancelled && ticket.SeatNo == "" {
+		if err := s.inventoryRepo.ReleaseOversoldSeat(ctx, tx, ticket.FlightID, ticket.CabinClass); err != nil {
+			return nil, fmt.Errorf("failed to cancel ticket: %w", err)
 		}
-		
-		// Count available seats
-		availableCount := 0
-		for _, seat := range availability {
-			if seat.Status == models.SeatStatusAvailable {
-				availableCount++
-			}
+	} else if cancelled {
+		if err := s.seatRepo.DeleteLock(ctx, tx, ticket.FlightID, ticket.SeatNo); err != nil {
+			return nil, fmt.Errorf("failed to cancel ticket: %w", err)
+		}
+		if err := s.inventoryRepo.AdjustForSeat(ctx, tx, ticket.FlightID, ticket.SeatNo, 0, -1); err != nil {
+			return nil, fmt.Errorf("failed to cancel ticket: %w", err)
 		}
-		
-		flight.AvailableSeats = availableCount
 	}
 	
-	return esResponse, nil
-}
-
-// CleanupExpiredHolds removes expired holds from both database and Elasticsearch
-func (s *BookingService) CleanupExpiredHolds(ctx context.Context) error {
-	// Get expired holds before deleting them
-	query := `SELECT id, flight_id, seat_no, holder_id, expires_at, created_at, updated_at 
-	          FROM seat_locks WHERE expires_at < NOW()`
+	if cancelled {
+		if err := s.ancillaryService.Release(ctx, tx, ticket); err != nil {
+			return nil, fmt.Errorf("failed to cancel ticket: %w", err)
+		}
+		
+		event := models.AuditEvent{
+			Actor:    userID,
+			Action:   models.AuditActionTicketCancelled,
+			FlightID: ticket.FlightID,
+			SeatNo:   ticket.SeatNo,
+			PNRCode:  ticket.PNRCode,
+		}
+		if err := s.auditService.Record(ctx, tx, event, snapshotTicket(previous, nil), snapshotTicket(ticket, nil)); err != nil {
+			return nil, err
+		}
+	}
 	
-	rows, err := s.db.DB.QueryContext(ctx, query)
-	if err != nil {
-		s.logger.Error("Failed to get expired holds", zap.Error(err))
-		return s.seatRepo.CleanupExpiredHolds(ctx) // Fallback to just database cleanup
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
-	defer rows.Close()
-
-	var expiredHoldIDs []int64
-	for rows.Next() {
-		var id, flightID int64
-		var seatNo, holderID string
-		var expiresAt sql.NullTime
-		var createdAt, updatedAt time.Time
+	
+	if cancelled {
+		if err := s.esClient.UpdateTicketStatus(ctx, ticket.ID, string(models.TicketStatusCancelled)); err != nil {
+			s.logger.Warn("Failed to update ticket status in Elasticsearch",
+				zap.Error(err),
+				zap.Int64("ticket_id", ticket.ID))
+		}
 		
-		if err := rows.Scan(&id, &flightID, &seatNo, &holderID, &expiresAt, &createdAt, &updatedAt); err != nil {
-			s.logger.Error("Failed to scan expired hold", zap.Error(err))
-			continue
+		s.logger.Info("Ticket cancelled successfully",
+			zap.Int64("ticket_id", ticket.ID),
+			zap.String("pnr_code", ticket.PNRCode),
+			zap.Int64("flight_id", ticket.FlightID),
+			zap.String("seat_no", ticket.SeatNo))
+		
+		if ticket.PaymentAuthorizationID != "" {
+			s.settleCancelledPayment(ctx, ticket)
 		}
-		expiredHoldIDs = append(expiredHoldIDs, id)
 	}
-
-	// Clean up from database first
-	err = s.seatRepo.CleanupExpiredHolds(ctx)
-	if err != nil {
-		return err
+	
+	response := &models.CancelTicketResponse{
+		TicketID:      ticket.ID,
+		PNRCode:       ticket.PNRCode,
+		Status:        ticket.Status,
+		PaymentStatus: ticket.PaymentStatus,
 	}
-
-	// Clean up from Elasticsearch
-	for _, holdID := range expiredHoldIDs {
-		err := s.esClient.DeleteHold(ctx, holdID)
-		if err != nil {
-			s.logger.Error("Failed to delete hold from Elasticsearch", 
-				zap.Error(err), 
-				zap.Int64("hold_id", holdID))
-			// Log error but don't fail the entire cleanup
-		} else {
-			s.logger.Debug("Hold deleted from Elasticsearch", zap.Int64("hold_id", holdID))
-		}
+	if ticket.CancelledAt != nil {
+		response.CancelledAt = *ticket.CancelledAt
 	}
-
-	return nil
+	
+	return response, nil
 }
 
-// Helper methods for idempotency
-func (s *BookingService) checkIdempotency(ctx context.Context, requestID, route, userID string) (interface{}, error) {
-	_, err := s.db.Queries.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
-		RequestID: requestID,
-		Route:     route,
-	})
+// GetBooking returns a ticket by PNR with its fare breakdown
+func (s *BookingService) GetBooking(ctx context.Context, pnrCode, userID string) (*models.Ticket, error) {
+	ticket, err := s.ticketRepo.GetTicketByPNR(ctx, strings.ToUpper(pnrCode))
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.UserID != userID {
+		return nil, ErrTicketNotOwned
+	}
 	
+	ticket.LineItems, err = s.ticketRepo.ListLineItems(ctx, nil, ticket.ID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Not found, proceed with request
-		}
-		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+		return nil, err
 	}
 	
-	// TODO: In a real implementation, we would deserialize the response
-	// For this POC, we'll return a simple response
-	s.logger.Info("Idempotent request detected", zap.String("request_id", requestID))
-	return nil, nil
+	return ticket, nil
 }
 
-func (s *BookingService) storeIdempotency(ctx context.Context, requestID, route, userID string, response interface{}) error {
-	// Create a hash of the response for storage
-	responseHash := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%+v", response))))
+// GetFlightAncillaries lists the ancillaries sold on a flight, priced in
+// currency
+func (s *BookingService) GetFlightAncillaries(ctx context.Context, flightID int64, currency string) (*models.FlightAncillariesResponse, error) {
+	flight, err := s.flightRepo.GetFlight(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, ErrFlightNotFound
+	}
 	
-	return s.db.Queries.CreateIdempotencyKey(ctx, db.CreateIdempotencyKeyParams{
-		RequestID:    requestID,
-		Route:        route,
-		UserID:       userID,
-		ResponseHash: responseHash,
-	})
+	return s.ancillaryService.Catalog(ctx, flight, currency)
 }
 
-// CreateFlight creates a new flight and indexes it in Elasticsearch
-func (s *BookingService) CreateFlight(ctx context.Context, req models.CreateFlightRequest) (*models.CreateFlightResponse, error) {
-	s.logger.Info("CreateFlight called", 
-		zap.String("origin", req.Origin),
-		zap.String("destination", req.Destination),
-		zap.String("airline", req.Airline))
-
-	// Parse times
-	departureTime, err := time.Parse(time.RFC3339, req.DepartureTime)
+// PurchaseAncillaries adds ancillaries to an issued ticket before its flight
+// departs. They are converted at the ticket's exchange rate so the whole
+// booking stays in one currency, and charged as a separate payment that is
+// captured once the line items are committed. Returns the updated booking.
+func (s *BookingService) PurchaseAncillaries(ctx context.Context, pnrCode string, req models.PurchaseAncillariesRequest, userID, idempotencyKey string) (*models.Ticket, error) {
+	pnrCode = strings.ToUpper(pnrCode)
+	route := fmt.Sprintf("POST /tickets/%s/ancillaries", pnrCode)
+	if idempotencyKey != "" {
+		if response, err := s.checkIdempotency(ctx, idempotencyKey, route, userID); err == nil && response != nil {
+			return response.(*models.Ticket), nil
+		}
+	}
+	
+	ticket, err := s.GetBooking(ctx, pnrCode, userID)
 	if err != nil {
-		s.logger.Error("Failed to parse departure_time", zap.Error(err), zap.String("departure_time", req.DepartureTime))
-		return nil, fmt.Errorf("invalid departure_time format: %w", err)
+		return nil, err
+	}
+	flight, err := s.flightRepo.GetFlight(ctx, ticket.FlightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, ErrFlightNotFound
+	}
+	if ticket.Status != models.TicketStatusConfirmed || !time.Now().UTC().Before(flight.DepartureTime) {
+		return nil, ErrTicketNotActive
 	}
 	
-	arrivalTime, err := time.Parse(time.RFC3339, req.ArrivalTime)
+	items, err := s.ancillaryService.Select(ctx, flight, req.Ancillaries, ticket.LineItems)
 	if err != nil {
-		s.logger.Error("Failed to parse arrival_time", zap.Error(err), zap.String("arrival_time", req.ArrivalTime))
-		return nil, fmt.Errorf("invalid arrival_time format: %w", err)
+		return nil, err
 	}
 	
-	// Validate business logic
-	if arrivalTime.Before(departureTime) {
-		s.logger.Error("Arrival time before departure time")
-		return nil, fmt.Errorf("arrival time cannot be before departure time")
+	rate := &models.ExchangeRate{
+		BaseCurrency:  ticket.BaseCurrency,
+		QuoteCurrency: ticket.Currency,
+		Rate:          ticket.ExchangeRate,
+	}
+	purchase := models.Ticket{
+		FlightID:   ticket.FlightID,
+		UserID:     userID,
+		Currency:   ticket.Currency,
+		PaymentRef: req.PaymentRef,
+	}
+	for i := range items {
+		if items[i].Amount, err = s.rateService.Convert(items[i].BaseAmount, rate); err != nil {
+			return nil, err
+		}
+		purchase.PriceAmount += items[i].Amount
+	}
+	
+	// Without an idempotency key every request is a new purchase
+	reference := ""
+	if idempotencyKey != "" {
+		reference = fmt.Sprintf("ancillaries-%s-%s", pnrCode, idempotencyKey)
+	}
+	authorization, err := s.authorizePayment(ctx, purchase, reference, req.ChallengeID)
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := s.addAncillaries(ctx, flight, ticket.PNRCode, items); err != nil {
+		s.voidPayment(ctx, authorization.ID)
+		return nil, err
+	}
+	metrics.Revenue.WithLabelValues(purchase.Currency).Add(float64(purchase.PriceAmount))
+	
+	captureCtx, cancel := context.WithTimeout(ctx, s.config.Payment.Timeout)
+	defer cancel()
+	if _, err := s.paymentGateway.Capture(captureCtx, authorization.ID, purchase.PriceAmount); err != nil {
+		s.logger.Error("Failed to capture ancillaries payment",
+			zap.Error(err),
+			zap.Int64("ticket_id", ticket.ID),
+			zap.String("authorization_id", authorization.ID))
+	}
+	
+	updated, err := s.GetBooking(ctx, pnrCode, userID)
+	if err != nil {
+		return nil, err
+	}
+	
+	if idempotencyKey != "" {
+		if err := s.storeIdempotency(ctx, idempotencyKey, route, userID, updated); err != nil {
+			s.logger.Warn("Failed to store idempotency key", zap.Error(err))
+		}
+	}
+	
+	s.logger.Info("Ancillaries purchased successfully",
+		zap.Int64("ticket_id", ticket.ID),
+		zap.String("pnr_code", ticket.PNRCode),
+		zap.Int("units", len(items)),
+		zap.String("authorization_id", authorization.ID))
+	
+	return updated, nil
+}
+
+// addAncillaries adds paid-for ancillary lines to a ticket in one
+// transaction. The ticket is locked and its status and per-ticket limits
+// checked again, so concurrent purchases or a cancellation can't slip in
+// between validation and the write.
+func (s *BookingService) addAncillaries(ctx context.Context, flight *models.Flight, pnrCode string, items []models.FareLineItem) error {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	ticket, err := s.ticketRepo.GetTicketByPNRForUpdate(ctx, tx, pnrCode)
+	if err != nil {
+		return err
+	}
+	if ticket == nil {
+		return ErrTicketNotFound
+	}
+	if ticket.Status != models.TicketStatusConfirmed {
+		return ErrTicketNotActive
+	}
+	
+	owned, err := s.ticketRepo.ListLineItems(ctx, tx, ticket.ID)
+	if err != nil {
+		return err
+	}
+	selections := make([]models.AncillarySelection, len(items))
+	for i, item := range items {
+		selections[i] = models.AncillarySelection{Code: item.Code}
+	}
+	if _, err := s.ancillaryService.Select(ctx, flight, selections, owned); err != nil {
+		return err
+	}
+	
+	if err := s.ancillaryService.Reserve(ctx, tx, flight, items); err != nil {
+		return err
+	}
+	if err := s.ticketRepo.AddLineItems(ctx, tx, ticket.ID, items); err != nil {
+		return err
+	}
+	
+	updated := *ticket
+	for _, item := range items {
+		updated.PriceAmount += item.Amount
+	}
+	event := models.AuditEvent{
+		Actor:    ticket.UserID,
+		Action:   models.AuditActionAncillariesAdded,
+		FlightID: ticket.FlightID,
+		SeatNo:   ticket.SeatNo,
+		PNRCode:  ticket.PNRCode,
+	}
+	before := snapshotTicket(ticket, owned)
+	after := snapshotTicket(&updated, append(owned, items...))
+	if err := s.auditService.Record(ctx, tx, event, before, after); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetFlightAvailability returns the remaining seats per cabin of a flight
+func (s *BookingService) GetFlightAvailability(ctx context.Context, flightID int64) (*models.FlightAvailabilityResponse, error) {
+	inventory, err := s.inventoryRepo.GetFlightInventory(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight availability: %w", err)
+	}
+	
+	return &models.FlightAvailabilityResponse{
+		FlightID:     flightID,
+		Availability: cabinAvailability(inventory),
+	}, nil
+}
+
+// GetFlightSeatAvailability returns seat availability for a flight with
+// prices in currency, or in the base currency when currency is empty
+func (s *BookingService) GetFlightSeatAvailability(ctx context.Context, flightID int64, currency string) ([]models.SeatAvailability, error) {
+	rate, err := s.rateService.Quote(ctx, currency, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	
+	availability, err := s.seatRepo.GetFlightSeatAvailability(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seat availability: %w", err)
+	}
+	if len(availability) == 0 {
+		return availability, nil
+	}
+	
+	// Every seat is listed at the flight's base price
+	flight, err := s.flightRepo.GetFlight(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, ErrFlightNotFound
+	}
+	price, err := s.rateService.Convert(flight.BasePrice, rate)
+	if err != nil {
+		return nil, err
+	}
+	
+	for i := range availability {
+		availability[i].Price = price
+		availability[i].Currency = rate.QuoteCurrency
+	}
+	
+	return availability, nil
+}
+
+// SearchFlights searches for flights using Elasticsearch, or MySQL when
+// Elasticsearch fails. The requested date is interpreted in the origin
+// airport's local time zone.
+func (s *BookingService) SearchFlights(ctx context.Context, req models.FlightSearchRequest) (*models.FlightSearchResponse, error) {
+	req.Origin = strings.ToUpper(req.Origin)
+	req.Destination = strings.ToUpper(req.Destination)
+
+	originLoc, err := s.airportLocation(ctx, req.Origin)
+	if err != nil {
+		return nil, err
+	}
+	destinationLoc, err := s.airportLocation(ctx, req.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := localDayRange(req.Date, originLoc)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := s.rateService.Quote(ctx, req.Currency, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	// Search in Elasticsearch, falling back to MySQL while it is unavailable
+	esResponse, err := s.esClient.SearchFlights(ctx, req, window)
+	if err == nil {
+		esResponse.Source = models.SearchSourceElasticsearch
+	} else {
+		s.logger.Warn("Elasticsearch search failed, searching MySQL instead", zap.Error(err))
+		metrics.SearchFallbacks.Inc()
+		esResponse, err = s.searchFlightsInDB(ctx, req, window)
+		if err != nil {
+			return nil, err
+		}
+	}
+	
+	flightIDs := make([]int64, len(esResponse.Flights))
+	for i := range esResponse.Flights {
+		flight := &esResponse.Flights[i]
+		flightIDs[i] = flight.ID
+
+		// Documents indexed before local times were stored lack them
+		if flight.DepartureTimeLocal == "" {
+			flight.DepartureTimeLocal = flight.DepartureTime.In(originLoc).Format(time.RFC3339)
+		}
+		if flight.ArrivalTimeLocal == "" {
+			flight.ArrivalTimeLocal = flight.ArrivalTime.In(destinationLoc).Format(time.RFC3339)
+		}
+
+		price, err := s.rateService.Convert(flight.BasePrice, rate)
+		if err != nil {
+			return nil, err
+		}
+		flight.Price = price
+		flight.Currency = rate.QuoteCurrency
+	}
+
+	// Fetch cabin counters for the whole page in one query
+	if len(flightIDs) > 0 {
+		inventory, err := s.inventoryRepo.ListFlightInventory(ctx, flightIDs)
+		if err != nil {
+			s.logger.Warn("Failed to get seat availability for flights",
+				zap.Int("flights", len(flightIDs)),
+				zap.Error(err))
+		} else {
+			for i := range esResponse.Flights {
+				esResponse.Flights[i].Availability = cabinAvailability(inventory[esResponse.Flights[i].ID])
+			}
+		}
+	}
+	
+	return esResponse, nil
+}
+
+// searchFlightsInDB serves flight search from MySQL
+func (s *BookingService) searchFlightsInDB(ctx context.Context, req models.FlightSearchRequest, window models.TimeRange) (*models.FlightSearchResponse, error) {
+	flights, total, err := s.flightRepo.SearchFlights(ctx, req, window)
+	if err != nil {
+		return nil, err
+	}
+	
+	results := make([]models.FlightSearchResult, len(flights))
+	for i, flight := range flights {
+		results[i] = models.FlightSearchResult{
+			ID:            flight.ID,
+			Origin:        flight.Origin,
+			Destination:   flight.Destination,
+			DepartureTime: flight.DepartureTime,
+			ArrivalTime:   flight.ArrivalTime,
+			Airline:       flight.Airline,
+			Aircraft:      flight.Aircraft,
+			FareClass:     flight.FareClass,
+			BasePrice:     flight.BasePrice,
+		}
+	}
+	
+	return &models.FlightSearchResponse{
+		Flights: results,
+		Total:   total,
+		Page:    req.Page,
+		Size:    req.Size,
+		Source:  models.SearchSourceDatabase,
+	}, nil
+}
+
+// CleanupExpiredHolds removes expired holds from both database and Elasticsearch
+func (s *BookingService) CleanupExpiredHolds(ctx context.Context) error {
+	cutoff := time.Now().UTC()
+	
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	// Counters must be released while the expired locks still exist
+	if err := s.inventoryRepo.ReleaseExpired(ctx, tx, cutoff); err != nil {
+		return err
+	}
+	
+	expired, err := s.seatRepo.CleanupExpiredHolds(ctx, tx, cutoff)
+	if err != nil {
+		return err
+	}
+	
+	for i, hold := range expired {
+		event := models.AuditEvent{
+			Actor:    models.AuditActorSystem,
+			Action:   models.AuditActionHoldExpired,
+			FlightID: hold.FlightID,
+			SeatNo:   hold.SeatNo,
+		}
+		// Payments that never resolved are rolled back to released
+		after := holdSnapshot{Status: models.HoldStatusExpired, HolderID: hold.HolderID}
+		if hold.Status == models.HoldStatusPaymentPending {
+			after.Status = models.HoldStatusReleased
+		}
+		if err := s.auditService.Record(ctx, tx, event, snapshotHold(&expired[i]), after); err != nil {
+			return err
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	metrics.HoldsExpired.Add(float64(len(expired)))
+
+	// Clean up from Elasticsearch
+	for _, hold := range expired {
+		err := s.esClient.DeleteHold(ctx, hold.ID)
+		if err != nil {
+			s.logger.Error("Failed to delete hold from Elasticsearch", 
+				zap.Error(err), 
+				zap.Int64("hold_id", hold.ID))
+			// Log error but don't fail the entire cleanup
+		} else {
+			s.logger.Debug("Hold deleted from Elasticsearch", zap.Int64("hold_id", hold.ID))
+		}
+	}
+
+	return nil
+}
+
+// priceTicket sets the ticket's fare breakdown and total in currency: the
+// flight's base price plus the taxes and fees whose rules match its countries
+// and the ticket's cabin. Each line is converted on its own so the lines always
+// add up to the total charged. The base currency total and the exchange rate
+// are snapshotted on the ticket. Promotions are applied as discount lines
+// against the base fare, and ancillaries, priced by the ancillary service,
+// are added after them.
+func (s *BookingService) priceTicket(ctx context.Context, ticket *models.Ticket, flight *models.Flight, currency string, promotions []models.Promotion, ancillaries []models.FareLineItem) error {
+	rate, err := s.rateService.Quote(ctx, currency, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	
+	route := fareRoute{
+		CabinClass: ticket.CabinClass,
+		Seated:     ticket.SeatNo != "",
+	}
+	if route.OriginCountry, err = s.airportCountry(ctx, flight.Origin); err != nil {
+		return err
+	}
+	if route.DestinationCountry, err = s.airportCountry(ctx, flight.Destination); err != nil {
+		return err
+	}
+	
+	rules, err := s.fareRuleRepo.ListRulesForRoute(ctx, route.OriginCountry, route.DestinationCountry)
+	if err != nil {
+		return err
+	}
+	
+	items := buildFareBreakdown(flight.BasePrice, rules, route)
+	items = append(items, promotionLineItems(flight.BasePrice, promotions)...)
+	items = append(items, ancillaries...)
+	var total, baseTotal int64
+	for i := range items {
+		if items[i].Amount, err = s.rateService.Convert(items[i].BaseAmount, rate); err != nil {
+			return err
+		}
+		total += items[i].Amount
+		baseTotal += items[i].BaseAmount
+	}
+	
+	ticket.LineItems = items
+	ticket.PriceAmount = total
+	ticket.Currency = rate.QuoteCurrency
+	ticket.BasePriceAmount = baseTotal
+	ticket.BaseCurrency = rate.BaseCurrency
+	ticket.ExchangeRate = rate.Rate
+	if !rate.EffectiveFrom.IsZero() {
+		effectiveFrom := rate.EffectiveFrom
+		ticket.ExchangeRateEffectiveFrom = &effectiveFrom
+	}
+	return nil
+}
+
+// issueSeatTicket converts the user's hold into a ticket, redeems its
+// promotions and takes its ancillaries from inventory in one transaction
+func (s *BookingService) issueSeatTicket(ctx context.Context, flight *models.Flight, ticket models.Ticket, promotions []models.Promotion) (*models.Ticket, error) {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	// Confirm the hold (this makes the lock permanent)
+	if err := s.seatRepo.ConfirmHold(ctx, tx, ticket.FlightID, ticket.SeatNo, ticket.UserID); err != nil {
+		return nil, fmt.Errorf("failed to confirm hold: %w", err)
+	}
+	
+	// Move the seat from held to sold
+	if err := s.inventoryRepo.AdjustForSeat(ctx, tx, ticket.FlightID, ticket.SeatNo, -1, 1); err != nil {
+		return nil, fmt.Errorf("failed to confirm hold: %w", err)
+	}
+	
+	createdTicket, err := s.ticketRepo.CreateTicket(ctx, tx, ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+	
+	if err := s.promoService.Redeem(ctx, tx, promotions, createdTicket); err != nil {
+		return nil, err
+	}
+	
+	if err := s.ancillaryService.Reserve(ctx, tx, flight, createdTicket.LineItems); err != nil {
+		return nil, err
+	}
+	
+	before := holdSnapshot{Status: models.HoldStatusPaymentPending, HolderID: ticket.UserID}
+	if err := s.recordTicketIssued(ctx, tx, createdTicket, before); err != nil {
+		return nil, err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	
+	return createdTicket, nil
+}
+
+// issueSeatlessTicket sells a ticket against the cabin's overbooking
+// allowance, redeems its promotions and takes its ancillaries from inventory
+// in one transaction
+func (s *BookingService) issueSeatlessTicket(ctx context.Context, flight *models.Flight, ticket models.Ticket, promotions []models.Promotion) (*models.Ticket, error) {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	reserved, err := s.inventoryRepo.ReserveOversoldSeat(ctx, tx, ticket.FlightID, ticket.CabinClass)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve seatless ticket: %w", err)
+	}
+	if !reserved {
+		return nil, ErrCabinFull
+	}
+	
+	createdTicket, err := s.ticketRepo.CreateTicket(ctx, tx, ticket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+	
+	if err := s.promoService.Redeem(ctx, tx, promotions, createdTicket); err != nil {
+		return nil, err
+	}
+	
+	if err := s.ancillaryService.Reserve(ctx, tx, flight, createdTicket.LineItems); err != nil {
+		return nil, err
+	}
+	
+	if err := s.recordTicketIssued(ctx, tx, createdTicket, nil); err != nil {
+		return nil, err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	
+	return createdTicket, nil
+}
+
+// authorizePayment reserves the ticket's fare on the payment method in
+// PaymentRef. Gateway errors (payment.ErrDeclined, payment.ErrChallengeRequired,
+// payment.ErrTimeout) are returned wrapped for the handler to map.
+func (s *BookingService) authorizePayment(ctx context.Context, ticket models.Ticket, reference, challengeID string) (*payment.Authorization, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Payment.Timeout)
+	defer cancel()
+	
+	authorization, err := s.paymentGateway.Authorize(ctx, payment.AuthorizeRequest{
+		Amount:        ticket.PriceAmount,
+		Currency:      ticket.Currency,
+		PaymentMethod: ticket.PaymentRef,
+		Reference:     reference,
+		CustomerID:    ticket.UserID,
+		ChallengeID:   challengeID,
+	})
+	if err != nil {
+		s.logger.Info("Payment authorization failed",
+			zap.Error(err),
+			zap.String("user_id", ticket.UserID),
+			zap.Int64("flight_id", ticket.FlightID))
+		return nil, fmt.Errorf("failed to authorize payment: %w", err)
+	}
+	
+	return authorization, nil
+}
+
+// capturePayment settles the payment of a committed ticket. A failed capture
+// does not undo the ticket; it stays with payment_status authorized so the
+// payment can be reconciled.
+func (s *BookingService) capturePayment(ctx context.Context, ticket *models.Ticket) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Payment.Timeout)
+	defer cancel()
+	
+	authorization, err := s.paymentGateway.Capture(ctx, ticket.PaymentAuthorizationID, ticket.PriceAmount)
+	if err != nil {
+		s.logger.Error("Failed to capture payment",
+			zap.Error(err),
+			zap.Int64("ticket_id", ticket.ID),
+			zap.String("authorization_id", ticket.PaymentAuthorizationID))
+		return
+	}
+	
+	if err := s.ticketRepo.UpdatePaymentStatus(ctx, ticket.ID, string(authorization.Status)); err != nil {
+		s.logger.Error("Failed to record captured payment",
+			zap.Error(err),
+			zap.Int64("ticket_id", ticket.ID))
+		return
+	}
+	ticket.PaymentStatus = string(authorization.Status)
+}
+
+// settleCancelledPayment returns the payment of a cancelled ticket to the
+// customer and records the resulting payment status. The cancellation stands
+// if the provider fails; the payment is then left for an operator.
+func (s *BookingService) settleCancelledPayment(ctx context.Context, ticket *models.Ticket) {
+	// Settle even if the request was cancelled, or the customer keeps paying
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.config.Payment.Timeout)
+	defer cancel()
+	
+	authorization, err := settlePayment(ctx, s.paymentGateway, ticket.PaymentAuthorizationID)
+	if err != nil {
+		s.logger.Error("Failed to refund cancelled ticket",
+			zap.Error(err),
+			zap.Int64("ticket_id", ticket.ID),
+			zap.String("authorization_id", ticket.PaymentAuthorizationID))
+		return
+	}
+	if string(authorization.Status) == ticket.PaymentStatus {
+		return
+	}
+	
+	if err := s.recordPaymentStatus(ctx, ticket, string(authorization.Status)); err != nil {
+		s.logger.Error("Failed to record refunded payment",
+			zap.Error(err),
+			zap.Int64("ticket_id", ticket.ID),
+			zap.String("payment_status", string(authorization.Status)))
+		return
+	}
+	ticket.PaymentStatus = string(authorization.Status)
+}
+
+// recordPaymentStatus sets a ticket's payment status and audits the change
+func (s *BookingService) recordPaymentStatus(ctx context.Context, ticket *models.Ticket, paymentStatus string) error {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if err := s.ticketRepo.UpdatePaymentState(ctx, tx, ticket.ID, paymentStatus, ticket.Status); err != nil {
+		return err
+	}
+	
+	before := snapshotTicket(ticket, nil)
+	after := before
+	after.PaymentStatus = paymentStatus
+	event := models.AuditEvent{
+		Actor:    ticket.UserID,
+		Action:   models.AuditActionPaymentUpdated,
+		FlightID: ticket.FlightID,
+		SeatNo:   ticket.SeatNo,
+		PNRCode:  ticket.PNRCode,
+	}
+	if err := s.auditService.Record(ctx, tx, event, before, after); err != nil {
+		return err
+	}
+	
+	return tx.Commit()
+}
+
+// settlePayment voids an authorization that was never captured and refunds
+// what is left of a captured one. Other authorizations are returned as is.
+func settlePayment(ctx context.Context, gateway payment.PaymentGateway, authorizationID string) (*payment.Authorization, error) {
+	authorization, err := gateway.Get(ctx, authorizationID)
+	if err != nil {
+		return nil, err
+	}
+	
+	switch authorization.Status {
+	case payment.StatusAuthorized:
+		return gateway.Void(ctx, authorizationID)
+	case payment.StatusCaptured:
+		return gateway.Refund(ctx, authorizationID, authorization.CapturedAmount-authorization.RefundedAmount)
+	}
+	return authorization, nil
+}
+
+// recordTicketIssued audits a ticket issued inside tx; before is the hold it
+// was sold from, nil for seatless tickets
+func (s *BookingService) recordTicketIssued(ctx context.Context, tx *sql.Tx, ticket *models.Ticket, before interface{}) error {
+	event := models.AuditEvent{
+		Actor:    ticket.UserID,
+		Action:   models.AuditActionTicketIssued,
+		FlightID: ticket.FlightID,
+		SeatNo:   ticket.SeatNo,
+		PNRCode:  ticket.PNRCode,
+	}
+	return s.auditService.Record(ctx, tx, event, before, snapshotTicket(ticket, ticket.LineItems))
+}
+
+// markPaymentPending moves the user's live hold to payment_pending until
+// expiresAt, while its payment is authorized
+func (s *BookingService) markPaymentPending(ctx context.Context, hold *models.SeatLock, expiresAt time.Time) error {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	pending, err := s.seatRepo.MarkPaymentPending(ctx, tx, hold.FlightID, hold.SeatNo, hold.HolderID, expiresAt)
+	if err != nil {
+		return err
+	}
+	if !pending {
+		return ErrNoValidHold
+	}
+	
+	event := models.AuditEvent{
+		Actor:    hold.HolderID,
+		Action:   models.AuditActionHoldPaymentPending,
+		FlightID: hold.FlightID,
+		SeatNo:   hold.SeatNo,
+	}
+	after := holdSnapshot{Status: models.HoldStatusPaymentPending, HolderID: hold.HolderID, ExpiresAt: &expiresAt}
+	if err := s.auditService.Record(ctx, tx, event, snapshotHold(hold), after); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// revertHold returns a payment_pending hold to active after a payment that
+// will not be charged, so the user can retry before the hold expires
+func (s *BookingService) revertHold(ctx context.Context, ticket models.Ticket) {
+	if err := s.revertToActive(context.WithoutCancel(ctx), ticket); err != nil {
+		s.logger.Error("Failed to revert hold to active",
+			zap.Error(err),
+			zap.Int64("flight_id", ticket.FlightID),
+			zap.String("seat_no", ticket.SeatNo))
+	}
+}
+
+func (s *BookingService) revertToActive(ctx context.Context, ticket models.Ticket) error {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	reverted, err := s.seatRepo.RevertToActive(ctx, tx, ticket.FlightID, ticket.SeatNo, ticket.UserID)
+	if err != nil || !reverted {
+		return err
+	}
+	
+	event := models.AuditEvent{
+		Actor:    ticket.UserID,
+		Action:   models.AuditActionHoldReverted,
+		FlightID: ticket.FlightID,
+		SeatNo:   ticket.SeatNo,
+	}
+	before := holdSnapshot{Status: models.HoldStatusPaymentPending, HolderID: ticket.UserID}
+	after := holdSnapshot{Status: models.HoldStatusActive, HolderID: ticket.UserID}
+	if err := s.auditService.Record(ctx, tx, event, before, after); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// voidPayment releases an authorization whose ticket could not be issued
+func (s *BookingService) voidPayment(ctx context.Context, authorizationID string) {
+	// Void even if the request was cancelled, or the funds stay reserved
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.config.Payment.Timeout)
+	defer cancel()
+	
+	if _, err := s.paymentGateway.Void(ctx, authorizationID); err != nil {
+		s.logger.Error("Failed to void payment authorization",
+			zap.Error(err),
+			zap.String("authorization_id", authorizationID))
+	}
+}
+
+// Helper methods for idempotency
+func (s *BookingService) checkIdempotency(ctx context.Context, requestID, route, userID string) (interface{}, error) {
+	_, err := s.db.Queries.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		RequestID: requestID,
+		Route:     route,
+	})
+	
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Not found, proceed with request
+		}
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+	
+	// TODO: In a real implementation, we would deserialize the response
+	// For this POC, we'll return a simple response
+	s.logger.Info("Idempotent request detected", zap.String("request_id", requestID))
+	return nil, nil
+}
+
+func (s *BookingService) storeIdempotency(ctx context.Context, requestID, route, userID string, response interface{}) error {
+	// Create a hash of the response for storage
+	responseHash := fmt.Sprintf("%x", sha256.Sum256([]byte(fmt.Sprintf("%+v", response))))
+	
+	return s.db.Queries.CreateIdempotencyKey(ctx, db.CreateIdempotencyKeyParams{
+		RequestID:    requestID,
+		Route:        route,
+		UserID:       userID,
+		ResponseHash: responseHash,
+	})
+}
+
+// CreateFlight creates a new flight and indexes it in Elasticsearch
+func (s *BookingService) CreateFlight(ctx context.Context, req models.CreateFlightRequest) (*models.CreateFlightResponse, error) {
+	s.logger.Info("CreateFlight called", 
+		zap.String("origin", req.Origin),
+		zap.String("destination", req.Destination),
+		zap.String("airline", req.Airline))
+
+	originAirport, err := s.lookupAirport(ctx, req.Origin)
+	if err != nil {
+		return nil, err
+	}
+	destinationAirport, err := s.lookupAirport(ctx, req.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if originAirport.IATACode == destinationAirport.IATACode {
+		return nil, validationError("origin and destination are both %s", originAirport.IATACode)
+	}
+
+	originLoc, err := time.LoadLocation(originAirport.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone for %s: %w", originAirport.IATACode, err)
+	}
+	destinationLoc, err := time.LoadLocation(destinationAirport.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone for %s: %w", destinationAirport.IATACode, err)
+	}
+
+	// Parse times; values without an offset are local to the respective airport
+	departureTime, err := parseFlightTime(req.DepartureTime, originLoc)
+	if err != nil {
+		s.logger.Error("Failed to parse departure_time", zap.Error(err), zap.String("departure_time", req.DepartureTime))
+		return nil, validationError("invalid departure_time format: %v", err)
+	}
+	
+	arrivalTime, err := parseFlightTime(req.ArrivalTime, destinationLoc)
+	if err != nil {
+		s.logger.Error("Failed to parse arrival_time", zap.Error(err), zap.String("arrival_time", req.ArrivalTime))
+		return nil, validationError("invalid arrival_time format: %v", err)
+	}
+	
+	// Validate business logic
+	if arrivalTime.Before(departureTime) {
+		s.logger.Error("Arrival time before departure time")
+		return nil, validationError("arrival time cannot be before departure time")
+	}
+
+	departureLocal := wallClock(departureTime.In(originLoc))
+	arrivalLocal := wallClock(arrivalTime.In(destinationLoc))
+	
+	// base_price is sent as a decimal amount and stored in minor units
+	basePrice, err := currency.ParseAmount(strconv.FormatFloat(req.BasePrice, 'f', -1, 64), s.rateService.BaseCurrency())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBasePrice, err)
 	}
 	
 	// Create flight in database
 	flight := models.Flight{
-		Origin:        req.Origin,
-		Destination:   req.Destination,
+		Origin:        originAirport.IATACode,
+		Destination:   destinationAirport.IATACode,
 		DepartureTime: departureTime,
 		ArrivalTime:   arrivalTime,
-		Airline:       req.Airline,
+		DepartureTimeLocal: &departureLocal,
+		ArrivalTimeLocal:   &arrivalLocal,
+		Airline:       strings.ToUpper(req.Airline),
 		Aircraft:      req.Aircraft,
 		FareClass:     req.FareClass,
+		BasePrice:     basePrice,
 	}
 	
 	s.logger.Info("Calling flightRepo.CreateFlight")
@@ -464,10 +1584,14 @@ func (s *BookingService) CreateFlight(ctx context.Context, req models.CreateFlig
 		Destination:   createdFlight.Destination,
 		DepartureTime: createdFlight.DepartureTime,
 		ArrivalTime:   createdFlight.ArrivalTime,
+		DepartureTimeLocal:  departureTime.In(originLoc).Format(time.RFC3339),
+		ArrivalTimeLocal:    arrivalTime.In(destinationLoc).Format(time.RFC3339),
+		OriginTimezone:      originAirport.Timezone,
+		DestinationTimezone: destinationAirport.Timezone,
 		Airline:       createdFlight.Airline,
 		Aircraft:      createdFlight.Aircraft,
 		FareClass:     createdFlight.FareClass,
-		BasePrice:     req.BasePrice,
+		BasePrice:     createdFlight.BasePrice,
 	}
 	
 	if err := s.esClient.IndexFlight(ctx, esDoc); err != nil {
@@ -486,8 +1610,10 @@ func (s *BookingService) CreateFlight(ctx context.Context, req models.CreateFlig
 		ID:            createdFlight.ID,
 		Origin:        createdFlight.Origin,
 		Destination:   createdFlight.Destination,
-		DepartureTime: createdFlight.DepartureTime.Format(time.RFC3339),
-		ArrivalTime:   createdFlight.ArrivalTime.Format(time.RFC3339),
+		DepartureTime: createdFlight.DepartureTime.UTC().Format(time.RFC3339),
+		ArrivalTime:   createdFlight.ArrivalTime.UTC().Format(time.RFC3339),
+		DepartureTimeLocal: departureTime.In(originLoc).Format(time.RFC3339),
+		ArrivalTimeLocal:   arrivalTime.In(destinationLoc).Format(time.RFC3339),
 		Airline:       createdFlight.Airline,
 		Aircraft:      createdFlight.Aircraft,
 		FareClass:     createdFlight.FareClass,
@@ -497,6 +1623,92 @@ func (s *BookingService) CreateFlight(ctx context.Context, req models.CreateFlig
 	}, nil
 }
 
+// lookupAirport resolves an IATA code against the airports reference table
+func (s *BookingService) lookupAirport(ctx context.Context, code string) (*models.Airport, error) {
+	airport, err := s.airportRepo.GetAirport(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get airport %s: %w", code, err)
+	}
+	if airport == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAirport, strings.ToUpper(code))
+	}
+	return airport, nil
+}
+
+// airportCountry returns the airport's country, or "" when it is not in the
+// airports table
+func (s *BookingService) airportCountry(ctx context.Context, code string) (string, error) {
+	airport, err := s.airportRepo.GetAirport(ctx, code)
+	if err != nil {
+		return "", fmt.Errorf("failed to get airport %s: %w", code, err)
+	}
+	if airport == nil {
+		return "", nil
+	}
+	return airport.Country, nil
+}
+
+// airportLocation returns the time zone of an airport, or UTC when the code is
+// not in the reference table
+func (s *BookingService) airportLocation(ctx context.Context, code string) (*time.Location, error) {
+	airport, err := s.airportRepo.GetAirport(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get airport %s: %w", code, err)
+	}
+	if airport == nil {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(airport.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone for %s: %w", airport.IATACode, err)
+	}
+	return loc, nil
+}
+
+// parseFlightTime parses an RFC3339 timestamp, falling back to a wall-clock
+// time in loc when no offset is given, and returns it in UTC
+func parseFlightTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.ParseInLocation(localDateTimeLayout, value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.UTC(), nil
+}
+
+// localDayRange returns the UTC bounds of the calendar day date in loc
+func localDayRange(date string, loc *time.Location) (models.TimeRange, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return models.TimeRange{}, fmt.Errorf("%w: %s", ErrInvalidDate, date)
+	}
+	return models.TimeRange{
+		From: day.UTC(),
+		To:   day.AddDate(0, 0, 1).UTC(),
+	}, nil
+}
+
+// wallClock drops the zone from t, keeping its local date and time, so that
+// it is stored verbatim in a DATETIME column
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// cabinAvailability converts cabin counters into customer-facing availability
+func cabinAvailability(inventory []models.FlightInventory) []models.CabinAvailability {
+	result := make([]models.CabinAvailability, len(inventory))
+	for i, cabin := range inventory {
+		result[i] = models.CabinAvailability{
+			CabinClass: cabin.CabinClass,
+			Capacity:   cabin.Capacity,
+			Available:  cabin.Available(),
+		}
+	}
+	return result
+}
+
 // generateSeats creates seat configuration based on the provided configuration
 func (s *BookingService) generateSeats(config models.SeatConfiguration, basePrice float64) []models.Seat {
 	var seats []models.Seat
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"airline-booking/internal/payment"
+)
+
+func TestLocalDayRange(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	window, err := localDayRange("2025-08-30", saoPaulo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// São Paulo is UTC-3, so the local day starts at 03:00 UTC
+	wantFrom := time.Date(2025, 8, 30, 3, 0, 0, 0, time.UTC)
+	wantTo := time.Date(2025, 8, 31, 3, 0, 0, 0, time.UTC)
+	if !window.From.Equal(wantFrom) || !window.To.Equal(wantTo) {
+		t.Errorf("expected [%v, %v), got [%v, %v)", wantFrom, wantTo, window.From, window.To)
+	}
+
+	// A 23:30 local departure is 02:30 UTC the next day but still on the 30th
+	departure := time.Date(2025, 8, 31, 2, 30, 0, 0, time.UTC)
+	if departure.Before(window.From) || !departure.Before(window.To) {
+		t.Errorf("expected %v to fall inside the local day", departure)
+	}
+}
+
+func TestLocalDayRangeInvalidDate(t *testing.T) {
+	_, err := localDayRange("30/08/2025", time.UTC)
+	if !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("expected ErrInvalidDate, got %v", err)
+	}
+}
+
+func TestParseFlightTime(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+
+	want := time.Date(2025, 8, 31, 2, 30, 0, 0, time.UTC)
+
+	withOffset, err := parseFlightTime("2025-08-30T23:30:00-03:00", saoPaulo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !withOffset.Equal(want) {
+		t.Errorf("expected %v, got %v", want, withOffset)
+	}
+
+	local, err := parseFlightTime("2025-08-30T23:30:00", saoPaulo)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !local.Equal(want) {
+		t.Errorf("expected %v, got %v", want, local)
+	}
+
+	if _, err := parseFlightTime("tomorrow", saoPaulo); err == nil {
+		t.Error("expected an error for an unparseable time")
+	}
+}
+
+func TestSettlePaymentRefundsCapturedPayment(t *testing.T) {
+	gateway := payment.NewFakeGateway(payment.FakeConfig{})
+	ctx := context.Background()
+
+	auth, err := gateway.Authorize(ctx, payment.AuthorizeRequest{Amount: 29900, Currency: "USD", PaymentMethod: "tok_visa"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := gateway.Capture(ctx, auth.ID, 29900); err != nil {
+		t.Fatalf("unexpected capture error: %v", err)
+	}
+
+	settled, err := settlePayment(ctx, gateway, auth.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settled.Status != payment.StatusRefunded || settled.RefundedAmount != 29900 {
+		t.Errorf("expected a full refund, got %+v", settled)
+	}
+
+	again, err := settlePayment(ctx, gateway, auth.ID)
+	if err != nil || again.Status != payment.StatusRefunded || again.RefundedAmount != 29900 {
+		t.Errorf("expected settling again to leave the refund as is, got %+v (%v)", again, err)
+	}
+}
+
+func TestSettlePaymentVoidsAuthorization(t *testing.T) {
+	gateway := payment.NewFakeGateway(payment.FakeConfig{})
+	ctx := context.Background()
+
+	auth, err := gateway.Authorize(ctx, payment.AuthorizeRequest{Amount: 29900, Currency: "USD", PaymentMethod: "tok_visa"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	settled, err := settlePayment(ctx, gateway, auth.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settled.Status != payment.StatusVoided {
+		t.Errorf("expected the authorization voided, got %+v", settled)
+	}
+}
+
+func TestSettlePaymentUnknownAuthorization(t *testing.T) {
+	gateway := payment.NewFakeGateway(payment.FakeConfig{})
+	if _, err := settlePayment(context.Background(), gateway, "auth_missing"); !errors.Is(err, payment.ErrAuthorizationNotFound) {
+		t.Errorf("expected ErrAuthorizationNotFound, got %v", err)
+	}
+}
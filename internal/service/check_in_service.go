@@ -0,0 +1,848 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/apis"
+	"airline-booking/internal/boardingpass"
+	"airline-booking/internal/config"
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+	"airline-booking/internal/repository"
+)
+
+var (
+	// ErrCheckInNotOpen is returned before the check-in window opens
+	ErrCheckInNotOpen = errors.New("check-in is not open yet")
+	// ErrCheckInClosed is returned once the check-in window has closed
+	ErrCheckInClosed = errors.New("check-in has closed")
+	// ErrAlreadyCheckedIn is returned when a ticket checks in a second time
+	ErrAlreadyCheckedIn = errors.New("ticket is already checked in")
+	// ErrNotCheckedIn is returned for the boarding pass of a ticket that has
+	// not checked in
+	ErrNotCheckedIn = errors.New("ticket is not checked in")
+	// ErrInvalidTravelDocument is returned when the passenger or document
+	// details fail validation
+	ErrInvalidTravelDocument = errors.New("invalid travel document")
+	// ErrSeatUnavailable is returned when the seat chosen at check-in does
+	// not exist, is in another cabin or is taken
+	ErrSeatUnavailable = errors.New("seat is not available")
+	// ErrNoSeatAvailable is returned when a seatless ticket checks in and its
+	// cabin has no free seat left
+	ErrNoSeatAvailable = errors.New("no seat available in cabin")
+	// ErrWrongFlight is returned when a boarding pass is scanned at the gate
+	// of another flight
+	ErrWrongFlight = errors.New("boarding pass is for another flight")
+	// ErrBoardingPassMismatch is returned when a scanned boarding pass is not
+	// the ticket's current one, e.g. issued before a seat change
+	ErrBoardingPassMismatch = errors.New("boarding pass does not match the ticket")
+	// ErrAlreadyBoarded is returned when a boarding pass is scanned again
+	ErrAlreadyBoarded = errors.New("passenger has already boarded")
+)
+
+// BoardingPassFormat selects how a boarding pass is rendered
+type BoardingPassFormat string
+
+const (
+	BoardingPassPNG BoardingPassFormat = "png"
+	BoardingPassPDF BoardingPassFormat = "pdf"
+)
+
+// boardingLeadTime is how long before departure boarding starts
+const boardingLeadTime = 40 * time.Minute
+
+// seatAssignAttempts bounds the retries when the free seat picked for a
+// seatless ticket is taken by a concurrent check-in
+const seatAssignAttempts = 3
+
+var (
+	passengerNamePattern  = regexp.MustCompile(`^\p{L}+(?:[ '\-]\p{L}+)*$`)
+	documentNumberPattern = regexp.MustCompile(`^[A-Z0-9]{5,20}$`)
+	countryCodePattern    = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// CheckInService runs online check-in, issues boarding passes and boards
+// passengers at the gate
+type CheckInService struct {
+	checkInRepo   *repository.CheckInRepository
+	ticketRepo    *repository.TicketRepository
+	flightRepo    *repository.FlightRepository
+	seatRepo      *repository.SeatRepository
+	inventoryRepo *repository.InventoryRepository
+	airportRepo   *repository.AirportRepository
+	ancillaryRepo *repository.AncillaryRepository
+	auditService  *AuditService
+	db            *db.Database
+	config        *config.CheckInConfig
+	logger        *zap.Logger
+}
+
+func NewCheckInService(
+	checkInRepo *repository.CheckInRepository,
+	ticketRepo *repository.TicketRepository,
+	flightRepo *repository.FlightRepository,
+	seatRepo *repository.SeatRepository,
+	inventoryRepo *repository.InventoryRepository,
+	airportRepo *repository.AirportRepository,
+	ancillaryRepo *repository.AncillaryRepository,
+	auditService *AuditService,
+	database *db.Database,
+	cfg *config.CheckInConfig,
+	logger *zap.Logger,
+) *CheckInService {
+	return &CheckInService{
+		checkInRepo:   checkInRepo,
+		ticketRepo:    ticketRepo,
+		flightRepo:    flightRepo,
+		seatRepo:      seatRepo,
+		inventoryRepo: inventoryRepo,
+		airportRepo:   airportRepo,
+		ancillaryRepo: ancillaryRepo,
+		auditService:  auditService,
+		db:            database,
+		config:        cfg,
+		logger:        logger,
+	}
+}
+
+// CheckIn checks in the passenger of a confirmed ticket inside the check-in
+// window, recording their travel document and giving them a boarding
+// sequence number. The ticket keeps its seat unless req names another free
+// seat in its cabin; a seatless ticket is given one here.
+func (s *CheckInService) CheckIn(ctx context.Context, pnrCode string, req models.CheckInRequest, userID string) (*models.BoardingPass, error) {
+	ticket, flight, err := s.loadTicket(ctx, pnrCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := checkInWindow(flight.DepartureTime, s.config, now); err != nil {
+		return nil, err
+	}
+
+	international, err := s.isInternational(ctx, flight)
+	if err != nil {
+		return nil, err
+	}
+	checkIn, err := validateTravelDocument(req, international, flight.ArrivalTime, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the ticket so a concurrent cancellation or check-in waits
+	ticket, err = s.ticketRepo.GetTicketByPNRForUpdate(ctx, tx, ticket.PNRCode)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.Status != models.TicketStatusConfirmed {
+		return nil, fmt.Errorf("%w: ticket is %s", ErrTicketNotActive, ticket.Status)
+	}
+
+	existing, err := s.checkInRepo.GetCheckInByTicket(ctx, tx, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	sequence, err := s.checkInRepo.NextBoardingSequence(ctx, tx, flight.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	seatNo, err := s.assignSeat(ctx, tx, ticket, strings.ToUpper(strings.TrimSpace(req.SeatNo)))
+	if err != nil {
+		return nil, err
+	}
+
+	checkIn.TicketID = ticket.ID
+	checkIn.FlightID = flight.ID
+	checkIn.SeatNo = seatNo
+	checkIn.BoardingSequence = sequence
+	created, err := s.checkInRepo.CreateCheckIn(ctx, tx, checkIn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Passenger checked in",
+		zap.Int64("ticket_id", ticket.ID),
+		zap.String("pnr_code", ticket.PNRCode),
+		zap.Int64("flight_id", flight.ID),
+		zap.String("seat_no", seatNo),
+		zap.Int("boarding_sequence", sequence))
+
+	ticket.SeatNo = seatNo
+	pass, _, err := s.boardingPass(ctx, ticket, flight, created)
+	return pass, err
+}
+
+// GetBoardingPass returns the boarding pass of a checked-in ticket
+func (s *CheckInService) GetBoardingPass(ctx context.Context, pnrCode, userID string) (*models.BoardingPass, error) {
+	pass, _, err := s.loadBoardingPass(ctx, pnrCode, userID)
+	return pass, err
+}
+
+// RenderBoardingPass returns the boarding pass of a checked-in ticket as a
+// PNG barcode or a printable PDF
+func (s *CheckInService) RenderBoardingPass(ctx context.Context, pnrCode, userID string, format BoardingPassFormat, symbology boardingpass.Symbology) ([]byte, error) {
+	_, pass, err := s.loadBoardingPass(ctx, pnrCode, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == BoardingPassPDF {
+		return pass.PDF(symbology)
+	}
+	return pass.PNG(symbology)
+}
+
+// ScanBoardingPass boards the passenger whose boarding pass barcode was read
+// at the gate of flightID. The pass must be the current one of a checked-in
+// ticket on that flight; wrong-flight and repeated scans are rejected.
+func (s *CheckInService) ScanBoardingPass(ctx context.Context, flightID int64, barcode string) (*models.BoardingScanResponse, error) {
+	barcode = strings.TrimRight(barcode, "\r\n")
+	decoded, err := boardingpass.Decode(barcode)
+	if err != nil {
+		return nil, err
+	}
+
+	flight, err := s.flightRepo.GetFlight(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, fmt.Errorf("%w: %d", ErrFlightNotFound, flightID)
+	}
+
+	ticket, err := s.ticketRepo.GetTicketByPNR(ctx, strings.ToUpper(decoded.PNRCode))
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.FlightID != flightID {
+		s.logger.Warn("Boarding pass scanned at the wrong gate",
+			zap.String("pnr_code", ticket.PNRCode),
+			zap.Int64("ticket_flight_id", ticket.FlightID),
+			zap.Int64("gate_flight_id", flightID))
+		return nil, fmt.Errorf("%w: %s%s, flight %d", ErrWrongFlight, decoded.Airline, decoded.FlightNumber, ticket.FlightID)
+	}
+	if ticket.Status != models.TicketStatusConfirmed {
+		return nil, fmt.Errorf("%w: ticket is %s", ErrTicketNotActive, ticket.Status)
+	}
+
+	checkIn, err := s.checkInRepo.GetCheckInByTicket(ctx, nil, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+	if checkIn == nil {
+		return nil, ErrNotCheckedIn
+	}
+
+	_, current, err := s.boardingPass(ctx, ticket, flight, checkIn)
+	if err != nil {
+		return nil, err
+	}
+	if current.BCBP() != barcode[:len(current.BCBP())] {
+		return nil, fmt.Errorf("%w: current pass is seat %s, sequence %d", ErrBoardingPassMismatch, checkIn.SeatNo, checkIn.BoardingSequence)
+	}
+
+	now := time.Now().UTC()
+	boarded := checkIn.BoardedAt == nil
+	if boarded {
+		boarded, err = s.checkInRepo.MarkBoarded(ctx, ticket.ID, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !boarded {
+		s.logger.Warn("Duplicate boarding pass scan",
+			zap.String("pnr_code", ticket.PNRCode),
+			zap.Int64("flight_id", flightID))
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyBoarded, ticket.PNRCode)
+	}
+
+	s.logger.Info("Passenger boarded",
+		zap.Int64("ticket_id", ticket.ID),
+		zap.String("pnr_code", ticket.PNRCode),
+		zap.Int64("flight_id", flightID),
+		zap.String("seat_no", checkIn.SeatNo))
+
+	return &models.BoardingScanResponse{
+		TicketID:         ticket.ID,
+		PNRCode:          ticket.PNRCode,
+		FlightID:         flightID,
+		FirstName:        checkIn.FirstName,
+		LastName:         checkIn.LastName,
+		CabinClass:       ticket.CabinClass,
+		SeatNo:           checkIn.SeatNo,
+		BoardingSequence: checkIn.BoardingSequence,
+		BoardedAt:        now,
+	}, nil
+}
+
+// GetFlightManifest lists the confirmed tickets of a flight with where each
+// passenger stands: booked, checked in, boarded or no-show
+func (s *CheckInService) GetFlightManifest(ctx context.Context, flightID int64) (*models.FlightManifest, error) {
+	flight, err := s.flightRepo.GetFlight(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, fmt.Errorf("%w: %d", ErrFlightNotFound, flightID)
+	}
+
+	tickets, err := s.ticketRepo.ListFlightTickets(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+	checkIns, err := s.checkInRepo.ListFlightCheckIns(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+	byTicket := make(map[int64]*models.CheckIn, len(checkIns))
+	for i := range checkIns {
+		byTicket[checkIns[i].TicketID] = &checkIns[i]
+	}
+
+	now := time.Now().UTC()
+	manifest := &models.FlightManifest{
+		FlightID:      flight.ID,
+		Origin:        flight.Origin,
+		Destination:   flight.Destination,
+		DepartureTime: flight.DepartureTime,
+		Totals:        make(map[models.PassengerStatus]int),
+		Passengers:    []models.ManifestPassenger{},
+	}
+	for _, ticket := range tickets {
+		if ticket.Status != models.TicketStatusConfirmed {
+			continue
+		}
+
+		checkIn := byTicket[ticket.ID]
+		passenger := models.ManifestPassenger{
+			TicketID:   ticket.ID,
+			PNRCode:    ticket.PNRCode,
+			CabinClass: ticket.CabinClass,
+			SeatNo:     ticket.SeatNo,
+			Status:     passengerStatus(checkIn, flight.DepartureTime, s.config, now),
+		}
+		if checkIn != nil {
+			passenger.FirstName = checkIn.FirstName
+			passenger.LastName = checkIn.LastName
+			passenger.BoardingSequence = checkIn.BoardingSequence
+			passenger.CheckedInAt = &checkIn.CheckedInAt
+			passenger.BoardedAt = checkIn.BoardedAt
+		}
+
+		manifest.Passengers = append(manifest.Passengers, passenger)
+		manifest.Totals[passenger.Status]++
+	}
+
+	return manifest, nil
+}
+
+// APISManifest returns the advance passenger information of a flight: every
+// confirmed ticket that has checked in, with the travel document given at
+// check-in, in boarding sequence order
+func (s *CheckInService) APISManifest(ctx context.Context, flightID int64) (*apis.Manifest, error) {
+	flight, err := s.flightRepo.GetFlight(ctx, flightID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, fmt.Errorf("%w: %d", ErrFlightNotFound, flightID)
+	}
+
+	departure, err := s.localDeparture(ctx, flight)
+	if err != nil {
+		return nil, err
+	}
+	arrival, err := s.localArrival(ctx, flight)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets, err := s.ticketRepo.ListFlightTickets(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*models.Ticket, len(tickets))
+	for i := range tickets {
+		byID[tickets[i].ID] = &tickets[i]
+	}
+	checkIns, err := s.checkInRepo.ListFlightCheckIns(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &apis.Manifest{
+		FlightID:      flight.ID,
+		Airline:       flight.Airline,
+		FlightNumber:  flightNumber(flight),
+		Origin:        flight.Origin,
+		Destination:   flight.Destination,
+		DepartureTime: departure,
+		ArrivalTime:   arrival,
+		Passengers:    []apis.Passenger{},
+	}
+	for _, checkIn := range checkIns {
+		ticket := byID[checkIn.TicketID]
+		if ticket == nil || ticket.Status != models.TicketStatusConfirmed {
+			continue
+		}
+		manifest.Passengers = append(manifest.Passengers, apis.Passenger{
+			TicketID:         ticket.ID,
+			PNRCode:          ticket.PNRCode,
+			FirstName:        checkIn.FirstName,
+			LastName:         checkIn.LastName,
+			DateOfBirth:      checkIn.DateOfBirth,
+			Nationality:      checkIn.Nationality,
+			DocumentType:     string(checkIn.DocumentType),
+			DocumentNumber:   checkIn.DocumentNumber,
+			DocumentCountry:  checkIn.DocumentCountry,
+			DocumentExpiry:   checkIn.DocumentExpiry,
+			CabinClass:       ticket.CabinClass,
+			SeatNo:           ticket.SeatNo,
+			BoardingSequence: checkIn.BoardingSequence,
+			Boarded:          checkIn.BoardedAt != nil,
+		})
+	}
+
+	return manifest, nil
+}
+
+// ExportManifest encodes a flight's APIS manifest in the given format.
+// PAXLST messages are addressed with APIS_SENDER_ID and APIS_RECEIVER_ID.
+func (s *CheckInService) ExportManifest(ctx context.Context, flightID int64, format apis.Format) ([]byte, error) {
+	manifest, err := s.APISManifest(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	header := apis.Interchange{
+		Sender:    s.config.APISSender,
+		Receiver:  s.config.APISReceiver,
+		Reference: now.Format("060102150405"),
+		Prepared:  now,
+	}
+	var buf bytes.Buffer
+	if err := manifest.Write(&buf, format, header); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Passenger manifest exported",
+		zap.Int64("flight_id", flightID),
+		zap.String("format", string(format)),
+		zap.Int("passengers", len(manifest.Passengers)))
+	return buf.Bytes(), nil
+}
+
+// loadTicket returns the user's confirmed ticket with the given PNR and its
+// flight
+func (s *CheckInService) loadTicket(ctx context.Context, pnrCode, userID string) (*models.Ticket, *models.Flight, error) {
+	ticket, err := s.ticketRepo.GetTicketByPNR(ctx, strings.ToUpper(pnrCode))
+	if err != nil {
+		return nil, nil, err
+	}
+	if ticket == nil {
+		return nil, nil, ErrTicketNotFound
+	}
+	if ticket.UserID != userID {
+		return nil, nil, ErrTicketNotOwned
+	}
+	if ticket.Status != models.TicketStatusConfirmed {
+		return nil, nil, fmt.Errorf("%w: ticket is %s", ErrTicketNotActive, ticket.Status)
+	}
+
+	flight, err := s.flightRepo.GetFlight(ctx, ticket.FlightID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get flight: %w", err)
+	}
+	if flight == nil {
+		return nil, nil, fmt.Errorf("%w: %d", ErrFlightNotFound, ticket.FlightID)
+	}
+	return ticket, flight, nil
+}
+
+func (s *CheckInService) loadBoardingPass(ctx context.Context, pnrCode, userID string) (*models.BoardingPass, boardingpass.Pass, error) {
+	ticket, flight, err := s.loadTicket(ctx, pnrCode, userID)
+	if err != nil {
+		return nil, boardingpass.Pass{}, err
+	}
+
+	checkIn, err := s.checkInRepo.GetCheckInByTicket(ctx, nil, ticket.ID)
+	if err != nil {
+		return nil, boardingpass.Pass{}, err
+	}
+	if checkIn == nil {
+		return nil, boardingpass.Pass{}, ErrNotCheckedIn
+	}
+
+	return s.boardingPass(ctx, ticket, flight, checkIn)
+}
+
+// assignSeat returns the seat a ticket checks in on inside tx: the seat it
+// already has, requested if it names another one, or for a seatless ticket
+// the first free seat of its cabin. A new seat is confirmed to the ticket
+// and the inventory counters move with it.
+func (s *CheckInService) assignSeat(ctx context.Context, tx *sql.Tx, ticket *models.Ticket, requested string) (string, error) {
+	if requested == "" || requested == ticket.SeatNo {
+		if ticket.SeatNo != "" {
+			return ticket.SeatNo, nil
+		}
+		return s.assignFreeSeat(ctx, tx, ticket)
+	}
+
+	seat, err := s.seatRepo.GetSeat(ctx, ticket.FlightID, requested)
+	if err != nil {
+		return "", err
+	}
+	if seat == nil {
+		return "", fmt.Errorf("%w: seat %s does not exist", ErrSeatUnavailable, requested)
+	}
+	if seat.Class != ticket.CabinClass {
+		return "", fmt.Errorf("%w: seat %s is not in the %s cabin", ErrSeatUnavailable, requested, ticket.CabinClass)
+	}
+	sold, err := s.ticketRepo.GetTicketByFlightSeat(ctx, ticket.FlightID, requested)
+	if err != nil {
+		return "", err
+	}
+	if sold != nil {
+		return "", fmt.Errorf("%w: seat %s is taken", ErrSeatUnavailable, requested)
+	}
+
+	assigned, err := s.seatRepo.AssignSeat(ctx, tx, ticket.FlightID, requested, ticket.UserID)
+	if err != nil {
+		return "", err
+	}
+	if !assigned {
+		return "", fmt.Errorf("%w: seat %s is taken", ErrSeatUnavailable, requested)
+	}
+
+	if err := s.moveTicket(ctx, tx, ticket, requested); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
+
+// assignFreeSeat gives a seatless ticket the first free seat of its cabin
+func (s *CheckInService) assignFreeSeat(ctx context.Context, tx *sql.Tx, ticket *models.Ticket) (string, error) {
+	for attempt := 0; attempt < seatAssignAttempts; attempt++ {
+		seatNo, err := s.seatRepo.FindFreeSeat(ctx, tx, ticket.FlightID, ticket.CabinClass)
+		if err != nil {
+			return "", err
+		}
+		if seatNo == "" {
+			return "", fmt.Errorf("%w: %s", ErrNoSeatAvailable, ticket.CabinClass)
+		}
+
+		assigned, err := s.seatRepo.AssignSeat(ctx, tx, ticket.FlightID, seatNo, ticket.UserID)
+		if err != nil {
+			return "", err
+		}
+		if !assigned {
+			// Taken by a concurrent hold or check-in; look again
+			continue
+		}
+
+		if err := s.moveTicket(ctx, tx, ticket, seatNo); err != nil {
+			return "", err
+		}
+		return seatNo, nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrNoSeatAvailable, ticket.CabinClass)
+}
+
+// moveTicket moves ticket to seatNo, whose lock the caller has confirmed,
+// freeing the ticket's old seat or, for a seatless ticket, its place in the
+// cabin's overbooking allowance
+func (s *CheckInService) moveTicket(ctx context.Context, tx *sql.Tx, ticket *models.Ticket, seatNo string) error {
+	if ticket.SeatNo == "" {
+		if err := s.inventoryRepo.ReleaseOversoldSeat(ctx, tx, ticket.FlightID, ticket.CabinClass); err != nil {
+			return err
+		}
+	} else {
+		if err := s.seatRepo.DeleteLock(ctx, tx, ticket.FlightID, ticket.SeatNo); err != nil {
+			return err
+		}
+		if err := s.inventoryRepo.AdjustForSeat(ctx, tx, ticket.FlightID, ticket.SeatNo, 0, -1); err != nil {
+			return err
+		}
+	}
+
+	if err := s.inventoryRepo.AdjustForSeat(ctx, tx, ticket.FlightID, seatNo, 0, 1); err != nil {
+		return err
+	}
+	if err := s.ticketRepo.UpdateSeat(ctx, tx, ticket.ID, seatNo); err != nil {
+		return err
+	}
+
+	before := snapshotTicket(ticket, nil)
+	after := before
+	after.SeatNo = seatNo
+	event := models.AuditEvent{
+		Actor:    ticket.UserID,
+		Action:   models.AuditActionSeatChanged,
+		FlightID: ticket.FlightID,
+		SeatNo:   seatNo,
+		PNRCode:  ticket.PNRCode,
+	}
+	return s.auditService.Record(ctx, tx, event, before, after)
+}
+
+// boardingPass builds the boarding pass of a checked-in ticket, both as
+// returned by the API and as rendered
+func (s *CheckInService) boardingPass(ctx context.Context, ticket *models.Ticket, flight *models.Flight, checkIn *models.CheckIn) (*models.BoardingPass, boardingpass.Pass, error) {
+	departure, err := s.localDeparture(ctx, flight)
+	if err != nil {
+		return nil, boardingpass.Pass{}, err
+	}
+
+	items, err := s.ticketRepo.ListLineItems(ctx, nil, ticket.ID)
+	if err != nil {
+		return nil, boardingpass.Pass{}, err
+	}
+	catalog, err := s.ancillaryRepo.ListForFlight(ctx, flight)
+	if err != nil {
+		return nil, boardingpass.Pass{}, err
+	}
+
+	pass := boardingpass.Pass{
+		FirstName:     checkIn.FirstName,
+		LastName:      checkIn.LastName,
+		PNRCode:       ticket.PNRCode,
+		Origin:        flight.Origin,
+		Destination:   flight.Destination,
+		Airline:       flight.Airline,
+		FlightNumber:  flightNumber(flight),
+		DepartureTime: departure,
+		BoardingTime:  departure.Add(-boardingLeadTime),
+		CabinClass:    ticket.CabinClass,
+		SeatNo:        checkIn.SeatNo,
+		Sequence:      checkIn.BoardingSequence,
+		BoardingGroup: boardingGroup(ticket.CabinClass, items, catalog),
+	}
+
+	return &models.BoardingPass{
+		PNRCode:          pass.PNRCode,
+		FirstName:        pass.FirstName,
+		LastName:         pass.LastName,
+		FlightID:         flight.ID,
+		Airline:          pass.Airline,
+		FlightNumber:     pass.FlightNumber,
+		Origin:           pass.Origin,
+		Destination:      pass.Destination,
+		DepartureTime:    pass.DepartureTime,
+		BoardingTime:     pass.BoardingTime,
+		CabinClass:       pass.CabinClass,
+		SeatNo:           pass.SeatNo,
+		BoardingSequence: pass.Sequence,
+		BoardingGroup:    pass.BoardingGroup,
+		BCBP:             pass.BCBP(),
+		CheckedInAt:      checkIn.CheckedInAt,
+	}, pass, nil
+}
+
+// localDeparture returns the flight's departure as wall clock at the origin
+func (s *CheckInService) localDeparture(ctx context.Context, flight *models.Flight) (time.Time, error) {
+	if flight.DepartureTimeLocal != nil {
+		return *flight.DepartureTimeLocal, nil
+	}
+	return s.airportWallClock(ctx, flight.Origin, flight.DepartureTime)
+}
+
+// localArrival returns the flight's arrival as wall clock at the destination
+func (s *CheckInService) localArrival(ctx context.Context, flight *models.Flight) (time.Time, error) {
+	if flight.ArrivalTimeLocal != nil {
+		return *flight.ArrivalTimeLocal, nil
+	}
+	return s.airportWallClock(ctx, flight.Destination, flight.ArrivalTime)
+}
+
+// airportWallClock converts t to wall clock at an airport, leaving it in UTC
+// when the airport is not in the reference table
+func (s *CheckInService) airportWallClock(ctx context.Context, code string, t time.Time) (time.Time, error) {
+	airport, err := s.airportRepo.GetAirport(ctx, code)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get airport %s: %w", code, err)
+	}
+	if airport == nil {
+		return t, nil
+	}
+	loc, err := time.LoadLocation(airport.Timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load time zone for %s: %w", airport.IATACode, err)
+	}
+	return wallClock(t.In(loc)), nil
+}
+
+// isInternational reports whether a flight leaves the country it departs
+// from. Airports missing from the reference table count as domestic.
+func (s *CheckInService) isInternational(ctx context.Context, flight *models.Flight) (bool, error) {
+	var countries [2]string
+	for i, code := range []string{flight.Origin, flight.Destination} {
+		airport, err := s.airportRepo.GetAirport(ctx, code)
+		if err != nil {
+			return false, fmt.Errorf("failed to get airport %s: %w", code, err)
+		}
+		if airport == nil {
+			return false, nil
+		}
+		countries[i] = airport.Country
+	}
+	return countries[0] != countries[1], nil
+}
+
+// flightNumber returns the number printed on the boarding pass. Flights have
+// no published number yet, so it is derived from the flight ID.
+func flightNumber(flight *models.Flight) string {
+	return strconv.FormatInt(flight.ID%10000, 10)
+}
+
+// boardingGroup returns the group a passenger boards with: 1 for first and
+// business passengers and holders of a priority boarding ancillary, 2 for
+// premium economy and 3 for everyone else
+func boardingGroup(cabinClass string, items []models.FareLineItem, catalog []models.Ancillary) string {
+	switch cabinClass {
+	case "first", "business":
+		return "1"
+	}
+
+	priority := make(map[string]bool)
+	for _, ancillary := range catalog {
+		if ancillary.Category == models.AncillaryCategoryPriorityBoarding {
+			priority[ancillary.Code] = true
+		}
+	}
+	for _, item := range items {
+		if item.Component == models.FareComponentAncillary && priority[item.Code] {
+			return "1"
+		}
+	}
+
+	if cabinClass == "premium_economy" {
+		return "2"
+	}
+	return "3"
+}
+
+// checkInWindow returns an error unless now is inside the check-in window of
+// a flight departing at departure
+func checkInWindow(departure time.Time, cfg *config.CheckInConfig, now time.Time) error {
+	opens := departure.Add(-cfg.Opens)
+	if now.Before(opens) {
+		return fmt.Errorf("%w: opens at %s", ErrCheckInNotOpen, opens.Format(time.RFC3339))
+	}
+	closes := departure.Add(-cfg.Closes)
+	if !now.Before(closes) {
+		return fmt.Errorf("%w: closed at %s", ErrCheckInClosed, closes.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// passengerStatus places a confirmed ticket on the manifest of a flight
+// departing at departure. checkIn is nil if the ticket has not checked in.
+func passengerStatus(checkIn *models.CheckIn, departure time.Time, cfg *config.CheckInConfig, now time.Time) models.PassengerStatus {
+	switch {
+	case checkIn != nil && checkIn.BoardedAt != nil:
+		return models.PassengerStatusBoarded
+	case !now.Before(departure):
+		return models.PassengerStatusNoShow
+	case checkIn != nil:
+		return models.PassengerStatusCheckedIn
+	case !now.Before(departure.Add(-cfg.Closes)):
+		return models.PassengerStatusNoShow
+	}
+	return models.PassengerStatusBooked
+}
+
+// validateTravelDocument checks the passenger and document details of req
+// and returns them normalized as a check-in. The document must be valid
+// until the flight arrives, and international flights require a passport.
+func validateTravelDocument(req models.CheckInRequest, international bool, arrival, now time.Time) (models.CheckIn, error) {
+	invalid := func(format string, args ...interface{}) (models.CheckIn, error) {
+		return models.CheckIn{}, fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidTravelDocument}, args...)...)
+	}
+
+	checkIn := models.CheckIn{
+		FirstName:       strings.Join(strings.Fields(req.FirstName), " "),
+		LastName:        strings.Join(strings.Fields(req.LastName), " "),
+		Nationality:     strings.ToUpper(strings.TrimSpace(req.Nationality)),
+		DocumentType:    models.DocumentType(strings.ToLower(strings.TrimSpace(string(req.Document.Type)))),
+		DocumentNumber:  strings.ToUpper(strings.ReplaceAll(req.Document.Number, " ", "")),
+		DocumentCountry: strings.ToUpper(strings.TrimSpace(req.Document.IssuingCountry)),
+	}
+
+	if !passengerNamePattern.MatchString(checkIn.FirstName) || len(checkIn.FirstName) > 100 {
+		return invalid("first_name must contain only letters, spaces, hyphens and apostrophes")
+	}
+	if !passengerNamePattern.MatchString(checkIn.LastName) || len(checkIn.LastName) > 100 {
+		return invalid("last_name must contain only letters, spaces, hyphens and apostrophes")
+	}
+	if !countryCodePattern.MatchString(checkIn.Nationality) {
+		return invalid("nationality must be an ISO 3166-1 alpha-2 country code")
+	}
+	if !countryCodePattern.MatchString(checkIn.DocumentCountry) {
+		return invalid("document.issuing_country must be an ISO 3166-1 alpha-2 country code")
+	}
+	if !documentNumberPattern.MatchString(checkIn.DocumentNumber) {
+		return invalid("document.number must be 5 to 20 letters and digits")
+	}
+
+	switch checkIn.DocumentType {
+	case models.DocumentTypePassport:
+	case models.DocumentTypeNationalID:
+		if international {
+			return invalid("a passport is required on international flights")
+		}
+	default:
+		return invalid("document.type must be passport or national_id")
+	}
+
+	var err error
+	checkIn.DateOfBirth, err = time.Parse("2006-01-02", req.DateOfBirth)
+	if err != nil {
+		return invalid("date_of_birth must be a YYYY-MM-DD date")
+	}
+	if !checkIn.DateOfBirth.Before(now) {
+		return invalid("date_of_birth must be in the past")
+	}
+
+	checkIn.DocumentExpiry, err = time.Parse("2006-01-02", req.Document.ExpiryDate)
+	if err != nil {
+		return invalid("document.expiry_date must be a YYYY-MM-DD date")
+	}
+	// The document must still be valid on the day the flight arrives
+	if checkIn.DocumentExpiry.Before(arrival.Truncate(24 * time.Hour)) {
+		return invalid("document expires before the flight arrives")
+	}
+
+	return checkIn, nil
+}
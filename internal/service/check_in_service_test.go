@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/models"
+)
+
+func TestCheckInWindow(t *testing.T) {
+	cfg := &config.CheckInConfig{Opens: 24 * time.Hour, Closes: 45 * time.Minute}
+	departure := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want error
+	}{
+		{"a day and a minute before", departure.Add(-24*time.Hour - time.Minute), ErrCheckInNotOpen},
+		{"as it opens", departure.Add(-24 * time.Hour), nil},
+		{"an hour before", departure.Add(-time.Hour), nil},
+		{"as it closes", departure.Add(-45 * time.Minute), ErrCheckInClosed},
+		{"after departure", departure.Add(time.Minute), ErrCheckInClosed},
+	}
+
+	for _, tt := range tests {
+		if err := checkInWindow(departure, cfg, tt.now); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func validCheckInRequest() models.CheckInRequest {
+	return models.CheckInRequest{
+		FirstName:   " Ana  Maria ",
+		LastName:    "O'Neil-Souza",
+		DateOfBirth: "1990-04-12",
+		Nationality: "br",
+		Document: models.TravelDocument{
+			Type:           "passport",
+			Number:         "fx 123456",
+			IssuingCountry: "BR",
+			ExpiryDate:     "2025-06-02",
+		},
+	}
+}
+
+func TestValidateTravelDocument(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 8, 0, 0, 0, time.UTC)
+	arrival := time.Date(2025, time.June, 2, 6, 0, 0, 0, time.UTC)
+
+	checkIn, err := validateTravelDocument(validCheckInRequest(), true, arrival, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if checkIn.FirstName != "Ana Maria" || checkIn.Nationality != "BR" || checkIn.DocumentNumber != "FX123456" {
+		t.Errorf("expected normalized details, got %q %q %q", checkIn.FirstName, checkIn.Nationality, checkIn.DocumentNumber)
+	}
+	if checkIn.DocumentType != models.DocumentTypePassport {
+		t.Errorf("expected passport, got %s", checkIn.DocumentType)
+	}
+
+	tests := []struct {
+		name          string
+		mutate        func(*models.CheckInRequest)
+		international bool
+		want          string
+	}{
+		{"digits in name", func(r *models.CheckInRequest) { r.FirstName = "Ana2" }, false, "first_name"},
+		{"unborn passenger", func(r *models.CheckInRequest) { r.DateOfBirth = "2025-07-01" }, false, "date_of_birth"},
+		{"malformed birth date", func(r *models.CheckInRequest) { r.DateOfBirth = "12/04/1990" }, false, "date_of_birth"},
+		{"three letter nationality", func(r *models.CheckInRequest) { r.Nationality = "BRA" }, false, "nationality"},
+		{"short document number", func(r *models.CheckInRequest) { r.Document.Number = "1234" }, false, "document.number"},
+		{"symbols in document number", func(r *models.CheckInRequest) { r.Document.Number = "AB-12345" }, false, "document.number"},
+		{"unknown document type", func(r *models.CheckInRequest) { r.Document.Type = "visa" }, false, "document.type"},
+		{"expires before arrival", func(r *models.CheckInRequest) { r.Document.ExpiryDate = "2025-06-01" }, false, "expires"},
+		{"national id abroad", func(r *models.CheckInRequest) { r.Document.Type = models.DocumentTypeNationalID }, true, "passport is required"},
+	}
+
+	for _, tt := range tests {
+		req := validCheckInRequest()
+		tt.mutate(&req)
+		_, err := validateTravelDocument(req, tt.international, arrival, now)
+		if !errors.Is(err, ErrInvalidTravelDocument) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected invalid document mentioning %q, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	req := validCheckInRequest()
+	req.Document.Type = models.DocumentTypeNationalID
+	if _, err := validateTravelDocument(req, false, arrival, now); err != nil {
+		t.Errorf("expected national ID to be accepted on a domestic flight, got %v", err)
+	}
+}
+
+func TestBoardingGroup(t *testing.T) {
+	catalog := []models.Ancillary{
+		{Code: "PRIORITY_BOARDING", Category: models.AncillaryCategoryPriorityBoarding},
+		{Code: "CHECKED_BAG", Category: models.AncillaryCategoryCheckedBag},
+	}
+	bag := []models.FareLineItem{{Component: models.FareComponentAncillary, Code: "CHECKED_BAG"}}
+	priority := []models.FareLineItem{{Component: models.FareComponentAncillary, Code: "PRIORITY_BOARDING"}}
+
+	tests := []struct {
+		cabin string
+		items []models.FareLineItem
+		want  string
+	}{
+		{"business", nil, "1"},
+		{"economy", priority, "1"},
+		{"premium_economy", bag, "2"},
+		{"economy", bag, "3"},
+	}
+
+	for _, tt := range tests {
+		if got := boardingGroup(tt.cabin, tt.items, catalog); got != tt.want {
+			t.Errorf("%s with %v: expected group %s, got %s", tt.cabin, tt.items, tt.want, got)
+		}
+	}
+}
+
+func TestPassengerStatus(t *testing.T) {
+	cfg := &config.CheckInConfig{Opens: 24 * time.Hour, Closes: 45 * time.Minute}
+	departure := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	boardedAt := departure.Add(-30 * time.Minute)
+	checkedIn := &models.CheckIn{}
+	boarded := &models.CheckIn{BoardedAt: &boardedAt}
+
+	tests := []struct {
+		name    string
+		checkIn *models.CheckIn
+		now     time.Time
+		want    models.PassengerStatus
+	}{
+		{"not checked in, check-in open", nil, departure.Add(-2 * time.Hour), models.PassengerStatusBooked},
+		{"not checked in, check-in closed", nil, departure.Add(-30 * time.Minute), models.PassengerStatusNoShow},
+		{"checked in before departure", checkedIn, departure.Add(-10 * time.Minute), models.PassengerStatusCheckedIn},
+		{"checked in, flight departed", checkedIn, departure, models.PassengerStatusNoShow},
+		{"boarded", boarded, departure.Add(time.Hour), models.PassengerStatusBoarded},
+	}
+
+	for _, tt := range tests {
+		if got := passengerStatus(tt.checkIn, departure, cfg, tt.now); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
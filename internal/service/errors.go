@@ -0,0 +1,31 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"airline-booking/internal/repository"
+)
+
+// Errors of seats, holds and flights; the API maps these and the other
+// services' errors to status codes in one place
+var (
+	// ErrSeatHeld is returned when another user has a live hold on the seat
+	ErrSeatHeld = repository.ErrSeatHeld
+	// ErrSeatSold is returned when holding a seat that already has a ticket
+	ErrSeatSold = errors.New("seat is already sold")
+	// ErrNoValidHold is returned when confirming a seat the user does not hold
+	ErrNoValidHold = repository.ErrNoValidHold
+	// ErrFlightNotFound is returned when no flight matches an ID
+	ErrFlightNotFound = errors.New("flight not found")
+	// ErrSeatNotFound is returned when a flight has no seat with the number
+	ErrSeatNotFound = errors.New("seat not found on flight")
+	// ErrValidation is wrapped by errors for requests that are well-formed
+	// but not valid, such as a flight arriving before it departs
+	ErrValidation = errors.New("validation failed")
+)
+
+// validationError returns an ErrValidation describing what is wrong
+func validationError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrValidation, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/currency"
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+	"airline-booking/internal/repository"
+)
+
+var (
+	// ErrUnsupportedCurrency is returned when a price is requested in a
+	// currency that has no exchange rate in effect
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	// ErrInvalidExchangeRate is returned for malformed exchange rate updates
+	ErrInvalidExchangeRate = errors.New("invalid exchange rate")
+)
+
+// exchangeRateCSVHeader is the column layout of data/exchange_rates.csv.
+// effective_from is RFC3339 or YYYY-MM-DD (midnight UTC).
+var exchangeRateCSVHeader = []string{"quote_currency", "rate", "effective_from"}
+
+// exchangeRateScale is the number of decimals kept by exchange_rates.rate
+const exchangeRateScale = 8
+
+// ExchangeRateService converts fares from the base currency into the
+// customer's currency using the rate in effect at the time of sale
+type ExchangeRateService struct {
+	rateRepo *repository.ExchangeRateRepository
+	db       *db.Database
+	config   *config.CurrencyConfig
+	logger   *zap.Logger
+}
+
+func NewExchangeRateService(
+	rateRepo *repository.ExchangeRateRepository,
+	database *db.Database,
+	cfg *config.CurrencyConfig,
+	logger *zap.Logger,
+) *ExchangeRateService {
+	return &ExchangeRateService{
+		rateRepo: rateRepo,
+		db:       database,
+		config:   cfg,
+		logger:   logger,
+	}
+}
+
+// BaseCurrency returns the currency fares are defined in
+func (s *ExchangeRateService) BaseCurrency() string {
+	return s.config.Base
+}
+
+// Quote returns the rate from the base currency to code in effect at at. An
+// empty code, or the base currency itself, quotes at 1 with no rate lookup.
+func (s *ExchangeRateService) Quote(ctx context.Context, code string, at time.Time) (*models.ExchangeRate, error) {
+	if code == "" {
+		code = s.config.Base
+	}
+	code, err := currency.Normalize(code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedCurrency, err)
+	}
+
+	if code == s.config.Base {
+		return &models.ExchangeRate{
+			BaseCurrency:  s.config.Base,
+			QuoteCurrency: code,
+			Rate:          "1",
+		}, nil
+	}
+
+	rate, err := s.rateRepo.GetEffectiveRate(ctx, s.config.Base, code, at)
+	if err != nil {
+		return nil, err
+	}
+	if rate == nil {
+		return nil, fmt.Errorf("%w: no %s/%s rate in effect", ErrUnsupportedCurrency, s.config.Base, code)
+	}
+	return rate, nil
+}
+
+// Convert converts amount, in minor units of the base currency, at rate and
+// rounds it to the quote currency's precision
+func (s *ExchangeRateService) Convert(amount int64, rate *models.ExchangeRate) (int64, error) {
+	r, err := currency.ParseRate(rate.Rate)
+	if err != nil {
+		return 0, err
+	}
+	return currency.Convert(amount, rate.BaseCurrency, rate.QuoteCurrency, r), nil
+}
+
+// SetRates validates and stores rates against the base currency in one
+// transaction; either every rate is stored or none is
+func (s *ExchangeRateService) SetRates(ctx context.Context, inputs []models.ExchangeRateInput, source string) ([]models.ExchangeRate, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	rates := make([]models.ExchangeRate, 0, len(inputs))
+	for i, input := range inputs {
+		rate, err := s.toExchangeRate(input, source, now)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rate %d: %v", ErrInvalidExchangeRate, i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, rate := range rates {
+		if err := s.rateRepo.UpsertRate(ctx, tx, rate); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Exchange rates updated",
+		zap.String("base_currency", s.config.Base),
+		zap.String("source", source),
+		zap.Int("rates", len(rates)))
+
+	return rates, nil
+}
+
+// ListRates returns the full rate history, including scheduled rates
+func (s *ExchangeRateService) ListRates(ctx context.Context) (*models.ExchangeRatesResponse, error) {
+	rates, err := s.rateRepo.ListRates(ctx, s.config.Base)
+	if err != nil {
+		return nil, err
+	}
+	return &models.ExchangeRatesResponse{
+		BaseCurrency: s.config.Base,
+		Rates:        rates,
+	}, nil
+}
+
+func (s *ExchangeRateService) toExchangeRate(input models.ExchangeRateInput, source string, now time.Time) (models.ExchangeRate, error) {
+	quote, err := currency.Normalize(input.QuoteCurrency)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+	if quote == s.config.Base {
+		return models.ExchangeRate{}, fmt.Errorf("%s is the base currency", quote)
+	}
+
+	r, err := currency.ParseRate(input.Rate.String())
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+	// Rounded to the column's scale so the stored rate is the one validated
+	rate := r.FloatString(exchangeRateScale)
+	if _, err := currency.ParseRate(rate); err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	effectiveFrom := now
+	if input.EffectiveFrom != nil {
+		effectiveFrom = input.EffectiveFrom.UTC()
+	}
+
+	return models.ExchangeRate{
+		BaseCurrency:  s.config.Base,
+		QuoteCurrency: quote,
+		Rate:          rate,
+		EffectiveFrom: effectiveFrom,
+		Source:        source,
+	}, nil
+}
+
+// ParseExchangeRatesCSV reads rates in the data/exchange_rates.csv layout
+func ParseExchangeRatesCSV(r io.Reader) ([]models.ExchangeRateInput, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates header: %w", err)
+	}
+	if strings.Join(header, ",") != strings.Join(exchangeRateCSVHeader, ",") {
+		return nil, fmt.Errorf("unexpected exchange rates header %v, want %v", header, exchangeRateCSVHeader)
+	}
+
+	var rates []models.ExchangeRateInput
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		effectiveFrom, err := parseEffectiveFrom(record[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid effective_from %q", line, record[2])
+		}
+
+		rates = append(rates, models.ExchangeRateInput{
+			QuoteCurrency: record[0],
+			Rate:          json.Number(record[1]),
+			EffectiveFrom: &effectiveFrom,
+		})
+	}
+
+	return rates, nil
+}
+
+func parseEffectiveFrom(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/models"
+)
+
+func TestParseExchangeRatesCSV(t *testing.T) {
+	input := `quote_currency,rate,effective_from
+eur,0.92,2024-01-01
+JPY,148.5,2024-06-01T12:00:00Z
+`
+	rates, err := ParseExchangeRatesCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("expected 2 rates, got %d", len(rates))
+	}
+
+	if rates[0].Rate.String() != "0.92" || !rates[0].EffectiveFrom.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected first rate: %+v", rates[0])
+	}
+
+	for name, input := range map[string]string{
+		"bad header":         "currency,rate,effective_from\n",
+		"bad effective_from": "quote_currency,rate,effective_from\nEUR,0.92,yesterday\n",
+	} {
+		if _, err := ParseExchangeRatesCSV(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestToExchangeRate(t *testing.T) {
+	s := &ExchangeRateService{config: &config.CurrencyConfig{Base: "USD"}}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rate, err := s.toExchangeRate(models.ExchangeRateInput{QuoteCurrency: "jpy", Rate: "148.123456789"}, "admin", now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rate.BaseCurrency != "USD" || rate.QuoteCurrency != "JPY" || rate.Rate != "148.12345679" || !rate.EffectiveFrom.Equal(now) {
+		t.Errorf("unexpected rate: %+v", rate)
+	}
+
+	for name, input := range map[string]models.ExchangeRateInput{
+		"base currency":  {QuoteCurrency: "USD", Rate: "1"},
+		"bad code":       {QuoteCurrency: "E1R", Rate: "0.92"},
+		"negative rate":  {QuoteCurrency: "EUR", Rate: "-0.92"},
+		"rounds to zero": {QuoteCurrency: "EUR", Rate: "0.000000001"},
+		"not a number":   {QuoteCurrency: "EUR", Rate: "abc"},
+	} {
+		if _, err := s.toExchangeRate(input, "admin", now); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBundledExchangeRatesCSV(t *testing.T) {
+	f, err := os.Open("../../data/exchange_rates.csv")
+	if err != nil {
+		t.Fatalf("failed to open bundled exchange rates file: %v", err)
+	}
+	defer f.Close()
+
+	rates, err := ParseExchangeRatesCSV(f)
+	if err != nil {
+		t.Fatalf("bundled exchange rates file is invalid: %v", err)
+	}
+
+	s := &ExchangeRateService{config: &config.CurrencyConfig{Base: "USD"}}
+	for _, input := range rates {
+		if _, err := s.toExchangeRate(input, "file", time.Now()); err != nil {
+			t.Errorf("invalid bundled rate %s: %v", input.QuoteCurrency, err)
+		}
+	}
+}
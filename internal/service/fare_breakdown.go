@@ -0,0 +1,61 @@
+package service
+
+import (
+	"airline-booking/internal/models"
+)
+
+// fareRoute is what fare rules are matched against
+type fareRoute struct {
+	OriginCountry      string
+	DestinationCountry string
+	CabinClass         string
+	// Seated is false for seatless tickets, which pay no seat fee
+	Seated bool
+}
+
+// buildFareBreakdown returns the base fare followed by every tax and fee
+// whose rule matches route, in minor units of the base currency. Amounts are
+// left for the caller to convert.
+func buildFareBreakdown(baseFare int64, rules []models.FareRule, route fareRoute) []models.FareLineItem {
+	items := []models.FareLineItem{{
+		Component:   models.FareComponentBaseFare,
+		Code:        "BASE_FARE",
+		Description: "Base fare",
+		BaseAmount:  baseFare,
+	}}
+
+	for _, rule := range rules {
+		if !fareRuleMatches(rule, route) {
+			continue
+		}
+		// Percentages are of the base fare, rounded half up
+		amount := rule.FixedAmount + (baseFare*int64(rule.PercentBps)+5000)/10000
+		if amount == 0 {
+			continue
+		}
+		items = append(items, models.FareLineItem{
+			Component:   rule.Component,
+			Code:        rule.Code,
+			Description: rule.Description,
+			BaseAmount:  amount,
+		})
+	}
+
+	return items
+}
+
+func fareRuleMatches(rule models.FareRule, route fareRoute) bool {
+	if rule.OriginCountry != "" && rule.OriginCountry != route.OriginCountry {
+		return false
+	}
+	if rule.DestinationCountry != "" && rule.DestinationCountry != route.DestinationCountry {
+		return false
+	}
+	if rule.CabinClass != "" && rule.CabinClass != route.CabinClass {
+		return false
+	}
+	if rule.Component == models.FareComponentSeatFee && !route.Seated {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"airline-booking/internal/models"
+)
+
+func TestBuildFareBreakdown(t *testing.T) {
+	rules := []models.FareRule{
+		{Code: "BR_DEPARTURE_TAX", Component: models.FareComponentAirportTax, OriginCountry: "BR", FixedAmount: 3500},
+		{Code: "US_ARRIVAL_TAX", Component: models.FareComponentAirportTax, DestinationCountry: "US", FixedAmount: 2150},
+		{Code: "FUEL_SURCHARGE", Component: models.FareComponentFuelSurcharge, PercentBps: 1000},
+		{Code: "SEAT_FEE_BUSINESS", Component: models.FareComponentSeatFee, CabinClass: "business", FixedAmount: 5000},
+		{Code: "SERVICE_FEE", Component: models.FareComponentServiceFee, FixedAmount: 1500},
+	}
+
+	tests := []struct {
+		name  string
+		route fareRoute
+		want  map[string]int64
+	}{
+		{
+			name:  "domestic economy",
+			route: fareRoute{OriginCountry: "BR", DestinationCountry: "BR", CabinClass: "economy", Seated: true},
+			want:  map[string]int64{"BASE_FARE": 29900, "BR_DEPARTURE_TAX": 3500, "FUEL_SURCHARGE": 2990, "SERVICE_FEE": 1500},
+		},
+		{
+			name:  "international business",
+			route: fareRoute{OriginCountry: "BR", DestinationCountry: "US", CabinClass: "business", Seated: true},
+			want: map[string]int64{"BASE_FARE": 29900, "BR_DEPARTURE_TAX": 3500, "US_ARRIVAL_TAX": 2150,
+				"FUEL_SURCHARGE": 2990, "SEAT_FEE_BUSINESS": 5000, "SERVICE_FEE": 1500},
+		},
+		{
+			name:  "seatless business pays no seat fee",
+			route: fareRoute{OriginCountry: "US", DestinationCountry: "GB", CabinClass: "business"},
+			want:  map[string]int64{"BASE_FARE": 29900, "FUEL_SURCHARGE": 2990, "SERVICE_FEE": 1500},
+		},
+	}
+
+	for _, tt := range tests {
+		items := buildFareBreakdown(29900, rules, tt.route)
+		if items[0].Component != models.FareComponentBaseFare {
+			t.Errorf("%s: expected the base fare first, got %s", tt.name, items[0].Component)
+		}
+
+		got := make(map[string]int64)
+		for _, item := range items {
+			got[item.Code] = item.BaseAmount
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got items %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for code, amount := range tt.want {
+			if got[code] != amount {
+				t.Errorf("%s: %s = %d, want %d", tt.name, code, got[code], amount)
+			}
+		}
+	}
+}
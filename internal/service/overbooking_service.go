@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/repository"
+)
+
+// ErrCabinNotFound is returned when a flight has no inventory for a cabin
+var ErrCabinNotFound = errors.New("cabin not found")
+
+// OverbookingService manages overbooking limits and the reports used by
+// airport staff when a flight has more passengers than seats
+type OverbookingService struct {
+	inventoryRepo *repository.InventoryRepository
+	ticketRepo    *repository.TicketRepository
+	logger        *zap.Logger
+}
+
+func NewOverbookingService(
+	inventoryRepo *repository.InventoryRepository,
+	ticketRepo *repository.TicketRepository,
+	logger *zap.Logger,
+) *OverbookingService {
+	return &OverbookingService{
+		inventoryRepo: inventoryRepo,
+		ticketRepo:    ticketRepo,
+		logger:        logger,
+	}
+}
+
+// SetOverbookingLimit sets how many seatless tickets a cabin may sell beyond
+// its capacity
+func (s *OverbookingService) SetOverbookingLimit(ctx context.Context, flightID int64, cabinClass string, limit int) error {
+	found, err := s.inventoryRepo.SetOverbookingLimit(ctx, flightID, strings.ToLower(cabinClass), limit)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("%w: flight %d has no %s cabin", ErrCabinNotFound, flightID, cabinClass)
+	}
+	return nil
+}
+
+// FlightsAtRisk lists upcoming cabins where ticketed passengers and active
+// holds outnumber the seats that can be boarded
+func (s *OverbookingService) FlightsAtRisk(ctx context.Context) (*models.FlightsAtRiskResponse, error) {
+	flights, err := s.inventoryRepo.ListFlightsAtRisk(ctx, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return &models.FlightsAtRiskResponse{Flights: flights}, nil
+}
+
+// DeniedBoardingList ranks the active tickets of a flight, optionally limited
+// to one cabin, in the order passengers should be asked to volunteer: lowest
+// fare first and, among equal fares, the most recent booking first
+func (s *OverbookingService) DeniedBoardingList(ctx context.Context, flightID int64, cabinClass string) (*models.DeniedBoardingListResponse, error) {
+	cabinClass = strings.ToLower(cabinClass)
+
+	inventory, err := s.inventoryRepo.GetFlightInventory(ctx, flightID)
+	if err != nil {
+		return nil, err
+	}
+
+	shortfall := 0
+	found := cabinClass == ""
+	for _, cabin := range inventory {
+		if cabinClass == "" || cabin.CabinClass == cabinClass {
+			shortfall += cabin.Shortfall()
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: flight %d has no %s cabin", ErrCabinNotFound, flightID, cabinClass)
+	}
+
+	tickets, err := s.ticketRepo.ListDeniedBoardingCandidates(ctx, flightID, cabinClass)
+	if err != nil {
+		return nil, err
+	}
+
+	volunteers := make([]models.DeniedBoardingVolunteer, len(tickets))
+	for i, ticket := range tickets {
+		volunteers[i] = models.DeniedBoardingVolunteer{
+			Rank:        i + 1,
+			TicketID:    ticket.ID,
+			PNRCode:     ticket.PNRCode,
+			UserID:      ticket.UserID,
+			CabinClass:  ticket.CabinClass,
+			SeatNo:      ticket.SeatNo,
+			PriceAmount: ticket.PriceAmount,
+			Currency:    ticket.Currency,
+			BookedAt:    ticket.CreatedAt,
+		}
+	}
+
+	return &models.DeniedBoardingListResponse{
+		FlightID:   flightID,
+		CabinClass: cabinClass,
+		Shortfall:  shortfall,
+		Volunteers: volunteers,
+	}, nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+	"airline-booking/internal/db"
+	"airline-booking/internal/models"
+	"airline-booking/internal/payment"
+	"airline-booking/internal/repository"
+)
+
+// PaymentWebhookService ingests asynchronous payment results. Every verified
+// event is stored once per provider event ID and applied to the ticket paid
+// with its authorization in the same transaction. Events that cannot be
+// applied yet, e.g. because the ticket is still being issued, stay pending
+// and can be replayed.
+type PaymentWebhookService struct {
+	eventRepo    *repository.PaymentEventRepository
+	ticketRepo   *repository.TicketRepository
+	auditService *AuditService
+	db           *db.Database
+	config       *config.PaymentConfig
+	logger       *zap.Logger
+}
+
+func NewPaymentWebhookService(
+	eventRepo *repository.PaymentEventRepository,
+	ticketRepo *repository.TicketRepository,
+	auditService *AuditService,
+	database *db.Database,
+	cfg *config.PaymentConfig,
+	logger *zap.Logger,
+) *PaymentWebhookService {
+	return &PaymentWebhookService{
+		eventRepo:    eventRepo,
+		ticketRepo:   ticketRepo,
+		auditService: auditService,
+		db:           database,
+		config:       cfg,
+		logger:       logger,
+	}
+}
+
+// HandleWebhook verifies, stores and applies a webhook delivery. It returns
+// payment.ErrInvalidSignature or payment.ErrInvalidEvent for deliveries that
+// must be rejected.
+func (s *PaymentWebhookService) HandleWebhook(ctx context.Context, body []byte, signature string) (*models.PaymentWebhookResponse, error) {
+	if err := payment.VerifyWebhook(body, signature, s.config.WebhookSecret, s.config.WebhookTolerance, time.Now()); err != nil {
+		return nil, err
+	}
+
+	event, err := payment.ParseWebhookEvent(body)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stored, created, err := s.eventRepo.RecordEvent(ctx, tx, models.PaymentWebhookEvent{
+		Provider:        s.config.Provider,
+		EventID:         event.ID,
+		EventType:       string(event.Type),
+		AuthorizationID: event.AuthorizationID,
+		Payload:         body,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		s.logger.Info("Duplicate payment webhook ignored", zap.String("event_id", event.ID))
+
+		existing, err := s.eventRepo.GetEvent(ctx, s.config.Provider, event.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.PaymentWebhookResponse{
+			EventID:   event.ID,
+			Duplicate: true,
+			Processed: existing != nil && existing.ProcessedAt != nil,
+		}, nil
+	}
+
+	processed, err := s.process(ctx, tx, stored.ID, event)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &models.PaymentWebhookResponse{
+		EventID:   event.ID,
+		Processed: processed,
+	}, nil
+}
+
+// ReplayEvent re-applies a stored event, whether or not it was processed
+// before. Transitions are idempotent, so replaying a processed event is safe.
+func (s *PaymentWebhookService) ReplayEvent(ctx context.Context, eventID string) (bool, error) {
+	stored, err := s.eventRepo.GetEvent(ctx, s.config.Provider, eventID)
+	if err != nil {
+		return false, err
+	}
+	if stored == nil {
+		return false, fmt.Errorf("payment event %s not found", eventID)
+	}
+	return s.replay(ctx, stored.ID)
+}
+
+// ReplayPending re-applies every stored event that has not been processed and
+// returns how many were applied
+func (s *PaymentWebhookService) ReplayPending(ctx context.Context) (int, error) {
+	events, err := s.eventRepo.ListUnprocessedEvents(ctx, s.config.Provider)
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, event := range events {
+		processed, err := s.replay(ctx, event.ID)
+		if err != nil {
+			return applied, err
+		}
+		if processed {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+func (s *PaymentWebhookService) replay(ctx context.Context, id int64) (bool, error) {
+	tx, err := s.db.BeginTx()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stored, err := s.eventRepo.LockEvent(ctx, tx, id)
+	if err != nil {
+		return false, err
+	}
+	if stored == nil {
+		return false, fmt.Errorf("payment event %d not found", id)
+	}
+
+	event, err := payment.ParseWebhookEvent(stored.Payload)
+	if err != nil {
+		return false, err
+	}
+
+	processed, err := s.process(ctx, tx, stored.ID, event)
+	if err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return processed, nil
+}
+
+// process applies event to its ticket and records the outcome on the stored
+// event. It reports false when the event was left pending.
+func (s *PaymentWebhookService) process(ctx context.Context, tx *sql.Tx, storedID int64, event *payment.WebhookEvent) (bool, error) {
+	processingError, err := s.apply(ctx, tx, event)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.eventRepo.MarkProcessed(ctx, tx, storedID, processingError); err != nil {
+		return false, err
+	}
+
+	if processingError != "" {
+		s.logger.Warn("Payment webhook left pending",
+			zap.String("event_id", event.ID),
+			zap.String("event_type", string(event.Type)),
+			zap.String("reason", processingError))
+		return false, nil
+	}
+	return true, nil
+}
+
+// apply returns a non-empty reason when the event cannot be applied yet
+func (s *PaymentWebhookService) apply(ctx context.Context, tx *sql.Tx, event *payment.WebhookEvent) (string, error) {
+	if event.AuthorizationID == "" {
+		return "event has no authorization_id", nil
+	}
+
+	ticket, err := s.ticketRepo.GetTicketByPaymentAuthorizationForUpdate(ctx, tx, event.AuthorizationID)
+	if err != nil {
+		return "", err
+	}
+	if ticket == nil {
+		return fmt.Sprintf("no ticket for authorization %s", event.AuthorizationID), nil
+	}
+
+	paymentStatus, status := paymentEventTransition(ticket.PaymentStatus, ticket.Status, event.Type)
+	if paymentStatus == ticket.PaymentStatus && status == ticket.Status {
+		return "", nil
+	}
+
+	if err := s.ticketRepo.UpdatePaymentState(ctx, tx, ticket.ID, paymentStatus, status); err != nil {
+		return "", err
+	}
+
+	before := snapshotTicket(ticket, nil)
+	after := before
+	after.PaymentStatus = paymentStatus
+	after.Status = status
+	auditEvent := models.AuditEvent{
+		Actor:    s.config.Provider,
+		Action:   paymentAuditAction(ticket.Status, status),
+		FlightID: ticket.FlightID,
+		SeatNo:   ticket.SeatNo,
+		PNRCode:  ticket.PNRCode,
+	}
+	if err := s.auditService.Record(ctx, tx, auditEvent, before, after); err != nil {
+		return "", err
+	}
+
+	s.logger.Info("Ticket payment state updated from webhook",
+		zap.String("event_id", event.ID),
+		zap.String("pnr_code", ticket.PNRCode),
+		zap.String("payment_status", paymentStatus),
+		zap.String("status", string(status)))
+
+	return "", nil
+}
+
+// paymentAuditAction is how a webhook's change to a ticket is audited:
+// chargebacks suspend it, their reversal reinstates it, and other events
+// only update its payment status
+func paymentAuditAction(before, after models.TicketStatus) models.AuditAction {
+	switch {
+	case before != after && after == models.TicketStatusSuspended:
+		return models.AuditActionTicketSuspended
+	case before == models.TicketStatusSuspended && after == models.TicketStatusConfirmed:
+		return models.AuditActionTicketReinstated
+	}
+	return models.AuditActionPaymentUpdated
+}
+
+// paymentEventTransition returns a ticket's payment status and status after
+// an event. Unknown events and events that don't apply to the current state
+// leave the ticket unchanged.
+func paymentEventTransition(paymentStatus string, status models.TicketStatus, eventType payment.EventType) (string, models.TicketStatus) {
+	switch eventType {
+	case payment.EventCaptured:
+		if paymentStatus == string(payment.StatusAuthorized) {
+			paymentStatus = string(payment.StatusCaptured)
+		}
+	case payment.EventVoided:
+		if paymentStatus == string(payment.StatusAuthorized) {
+			paymentStatus = string(payment.StatusVoided)
+		}
+	case payment.EventRefunded:
+		paymentStatus = string(payment.StatusRefunded)
+	case payment.EventChargeback:
+		paymentStatus = string(payment.StatusChargeback)
+		if status == models.TicketStatusConfirmed {
+			status = models.TicketStatusSuspended
+		}
+	case payment.EventChargebackReversed:
+		if paymentStatus == string(payment.StatusChargeback) {
+			paymentStatus = string(payment.StatusCaptured)
+		}
+		if status == models.TicketStatusSuspended {
+			status = models.TicketStatusConfirmed
+		}
+	}
+	return paymentStatus, status
+}
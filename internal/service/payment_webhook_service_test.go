@@ -0,0 +1,54 @@
+package service
+
+import (
+	"testing"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/payment"
+)
+
+func TestPaymentEventTransition(t *testing.T) {
+	tests := []struct {
+		name              string
+		paymentStatus     string
+		status            models.TicketStatus
+		event             payment.EventType
+		wantPaymentStatus string
+		wantStatus        models.TicketStatus
+	}{
+		{"delayed capture", "authorized", models.TicketStatusConfirmed, payment.EventCaptured, "captured", models.TicketStatusConfirmed},
+		{"capture after refund is ignored", "refunded", models.TicketStatusCancelled, payment.EventCaptured, "refunded", models.TicketStatusCancelled},
+		{"void", "authorized", models.TicketStatusConfirmed, payment.EventVoided, "voided", models.TicketStatusConfirmed},
+		{"void after capture is ignored", "captured", models.TicketStatusConfirmed, payment.EventVoided, "captured", models.TicketStatusConfirmed},
+		{"refund", "captured", models.TicketStatusCancelled, payment.EventRefunded, "refunded", models.TicketStatusCancelled},
+		{"chargeback suspends", "captured", models.TicketStatusConfirmed, payment.EventChargeback, "chargeback", models.TicketStatusSuspended},
+		{"chargeback on cancelled ticket", "refunded", models.TicketStatusCancelled, payment.EventChargeback, "chargeback", models.TicketStatusCancelled},
+		{"chargeback reversed", "chargeback", models.TicketStatusSuspended, payment.EventChargebackReversed, "captured", models.TicketStatusConfirmed},
+		{"unknown event", "captured", models.TicketStatusConfirmed, payment.EventType("payment.unknown"), "captured", models.TicketStatusConfirmed},
+	}
+
+	for _, tt := range tests {
+		paymentStatus, status := paymentEventTransition(tt.paymentStatus, tt.status, tt.event)
+		if paymentStatus != tt.wantPaymentStatus || status != tt.wantStatus {
+			t.Errorf("%s: got (%s, %s), want (%s, %s)", tt.name, paymentStatus, status, tt.wantPaymentStatus, tt.wantStatus)
+		}
+	}
+}
+
+func TestPaymentAuditAction(t *testing.T) {
+	tests := []struct {
+		before, after models.TicketStatus
+		want          models.AuditAction
+	}{
+		{models.TicketStatusConfirmed, models.TicketStatusSuspended, models.AuditActionTicketSuspended},
+		{models.TicketStatusSuspended, models.TicketStatusConfirmed, models.AuditActionTicketReinstated},
+		{models.TicketStatusConfirmed, models.TicketStatusConfirmed, models.AuditActionPaymentUpdated},
+		{models.TicketStatusCancelled, models.TicketStatusCancelled, models.AuditActionPaymentUpdated},
+	}
+
+	for _, tt := range tests {
+		if got := paymentAuditAction(tt.before, tt.after); got != tt.want {
+			t.Errorf("%s -> %s: got %s, want %s", tt.before, tt.after, got, tt.want)
+		}
+	}
+}
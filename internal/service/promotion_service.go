@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"airline-booking/internal/models"
+	"airline-booking/internal/repository"
+)
+
+var (
+	// ErrPromoCodeRejected is wrapped by every PromoCodeError
+	ErrPromoCodeRejected = errors.New("promo code rejected")
+	// ErrInvalidPromotion is returned when creating a malformed promotion
+	ErrInvalidPromotion = errors.New("invalid promotion")
+)
+
+// Reasons a promo code is rejected
+const (
+	PromoReasonNotFound          = "not_found"
+	PromoReasonInactive          = "inactive"
+	PromoReasonNotYetValid       = "not_yet_valid"
+	PromoReasonExpired           = "expired"
+	PromoReasonRoute             = "route_not_eligible"
+	PromoReasonAirline           = "airline_not_eligible"
+	PromoReasonFareClass         = "fare_class_not_eligible"
+	PromoReasonTravelDate        = "travel_date_not_eligible"
+	PromoReasonNotStackable      = "not_stackable"
+	PromoReasonDuplicate         = "duplicate"
+	PromoReasonUsageLimitReached = "usage_limit_reached"
+	PromoReasonUserLimitReached  = "user_limit_reached"
+)
+
+// PromoCodeError reports why a promo code cannot be used
+type PromoCodeError struct {
+	Code   string
+	Reason string
+}
+
+func (e *PromoCodeError) Error() string {
+	return fmt.Sprintf("promo code %s rejected: %s", e.Code, e.Reason)
+}
+
+func (e *PromoCodeError) Unwrap() error {
+	return ErrPromoCodeRejected
+}
+
+// Exhausted reports whether the code was valid but has no uses left
+func (e *PromoCodeError) Exhausted() bool {
+	return e.Reason == PromoReasonUsageLimitReached || e.Reason == PromoReasonUserLimitReached
+}
+
+// PromotionService validates promo codes against bookings and redeems them
+// when tickets are issued. Codes are checked without locks when a hold is
+// created or a ticket priced, and again under the promotion's row lock in the
+// ticket's transaction, so a limited code can't be oversold.
+type PromotionService struct {
+	promoRepo *repository.PromotionRepository
+	logger    *zap.Logger
+}
+
+func NewPromotionService(promoRepo *repository.PromotionRepository, logger *zap.Logger) *PromotionService {
+	return &PromotionService{
+		promoRepo: promoRepo,
+		logger:    logger,
+	}
+}
+
+// CreatePromotion validates and stores a promotion
+func (s *PromotionService) CreatePromotion(ctx context.Context, req models.CreatePromotionRequest) (*models.Promotion, error) {
+	promotion := models.Promotion{
+		Code:                  strings.ToUpper(strings.TrimSpace(req.Code)),
+		Description:           req.Description,
+		DiscountType:          req.DiscountType,
+		DiscountValue:         req.DiscountValue,
+		MaxDiscountAmount:     req.MaxDiscountAmount,
+		Origin:                strings.ToUpper(req.Origin),
+		Destination:           strings.ToUpper(req.Destination),
+		Airline:               req.Airline,
+		FareClass:             req.FareClass,
+		TravelFrom:            req.TravelFrom,
+		TravelUntil:           req.TravelUntil,
+		ValidFrom:             time.Now().UTC().Truncate(time.Second),
+		ValidUntil:            req.ValidUntil,
+		MaxRedemptions:        req.MaxRedemptions,
+		MaxRedemptionsPerUser: req.MaxRedemptionsPerUser,
+		Stackable:             req.Stackable,
+	}
+	if req.ValidFrom != nil {
+		promotion.ValidFrom = req.ValidFrom.UTC()
+	}
+
+	switch {
+	case promotion.Code == "":
+		return nil, fmt.Errorf("%w: code is required", ErrInvalidPromotion)
+	case promotion.DiscountType == models.DiscountTypePercent && promotion.DiscountValue > 10000:
+		return nil, fmt.Errorf("%w: a percent discount is at most 10000 basis points", ErrInvalidPromotion)
+	case promotion.ValidUntil != nil && !promotion.ValidUntil.After(promotion.ValidFrom):
+		return nil, fmt.Errorf("%w: valid_until must be after valid_from", ErrInvalidPromotion)
+	case promotion.TravelFrom != nil && promotion.TravelUntil != nil && promotion.TravelUntil.Before(*promotion.TravelFrom):
+		return nil, fmt.Errorf("%w: travel_until must not be before travel_from", ErrInvalidPromotion)
+	}
+
+	existing, err := s.promoRepo.GetPromotionByCode(ctx, promotion.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w: code %s already exists", ErrInvalidPromotion, promotion.Code)
+	}
+
+	created, err := s.promoRepo.CreatePromotion(ctx, promotion)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Promotion created",
+		zap.String("code", created.Code),
+		zap.String("discount_type", string(created.DiscountType)),
+		zap.Int64("discount_value", created.DiscountValue))
+
+	return created, nil
+}
+
+// ListPromotions returns every promotion, newest first
+func (s *PromotionService) ListPromotions(ctx context.Context) (*models.PromotionsResponse, error) {
+	promotions, err := s.promoRepo.ListPromotions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PromotionsResponse{Promotions: promotions}, nil
+}
+
+// Validate looks up codes and checks that each can be used by userID on
+// flight at now and that they may be combined. It returns the promotions in
+// the order given, or a *PromoCodeError for the first code rejected.
+func (s *PromotionService) Validate(ctx context.Context, codes []string, flight *models.Flight, userID string, now time.Time) ([]models.Promotion, error) {
+	promotions := make([]models.Promotion, 0, len(codes))
+	seen := make(map[string]bool)
+
+	for _, code := range codes {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if seen[code] {
+			return nil, &PromoCodeError{Code: code, Reason: PromoReasonDuplicate}
+		}
+		seen[code] = true
+
+		promotion, err := s.promoRepo.GetPromotionByCode(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if promotion == nil {
+			return nil, &PromoCodeError{Code: code, Reason: PromoReasonNotFound}
+		}
+
+		if reason := promotionIneligibility(*promotion, flight, now); reason != "" {
+			return nil, &PromoCodeError{Code: code, Reason: reason}
+		}
+
+		used, err := s.promoRepo.CountUserRedemptions(ctx, nil, promotion.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if reason := promotionLimitReason(*promotion, used); reason != "" {
+			return nil, &PromoCodeError{Code: code, Reason: reason}
+		}
+
+		promotions = append(promotions, *promotion)
+	}
+
+	if err := checkPromotionStacking(promotions); err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// HoldPromotions returns the promotions applied to the holder's hold,
+// re-validated for flight at now
+func (s *PromotionService) HoldPromotions(ctx context.Context, flight *models.Flight, seatNo, holderID string, now time.Time) ([]models.Promotion, error) {
+	codes, err := s.promoRepo.ListHoldPromotionCodes(ctx, flight.ID, seatNo, holderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Validate(ctx, codes, flight, holderID, now)
+}
+
+// AttachToHold records the promotions applied to a hold inside tx,
+// replacing those of any earlier hold on the seat
+func (s *PromotionService) AttachToHold(ctx context.Context, tx *sql.Tx, flightID int64, seatNo, holderID string, promotions []models.Promotion) error {
+	ids := make([]int64, len(promotions))
+	for i, promotion := range promotions {
+		ids[i] = promotion.ID
+	}
+	return s.promoRepo.ReplaceHoldPromotions(ctx, tx, flightID, seatNo, holderID, ids)
+}
+
+// Redeem records the use of promotions on ticket inside tx. Each promotion
+// is locked and its limits checked again, so concurrent bookings can't
+// redeem a code past its limits; a *PromoCodeError means the transaction
+// must be rolled back.
+func (s *PromotionService) Redeem(ctx context.Context, tx *sql.Tx, promotions []models.Promotion, ticket *models.Ticket) error {
+	for _, promotion := range promotions {
+		locked, err := s.promoRepo.LockPromotion(ctx, tx, promotion.ID)
+		if err != nil {
+			return err
+		}
+		if locked == nil {
+			return &PromoCodeError{Code: promotion.Code, Reason: PromoReasonNotFound}
+		}
+		if !locked.Active {
+			return &PromoCodeError{Code: promotion.Code, Reason: PromoReasonInactive}
+		}
+
+		used, err := s.promoRepo.CountUserRedemptions(ctx, tx, locked.ID, ticket.UserID)
+		if err != nil {
+			return err
+		}
+		if reason := promotionLimitReason(*locked, used); reason != "" {
+			return &PromoCodeError{Code: promotion.Code, Reason: reason}
+		}
+
+		var discount int64
+		for _, item := range ticket.LineItems {
+			if item.Component == models.FareComponentDiscount && item.Code == locked.Code {
+				discount = -item.BaseAmount
+			}
+		}
+
+		if err := s.promoRepo.RecordRedemption(ctx, tx, locked.ID, ticket.UserID, ticket.ID, discount); err != nil {
+			return err
+		}
+	}
+
+	if ticket.SeatNo != "" {
+		return s.promoRepo.ClearHoldPromotions(ctx, tx, ticket.FlightID, ticket.SeatNo)
+	}
+	return nil
+}
+
+// promotionIneligibility returns why promotion can't be used on flight at
+// now, or "" if it can. Usage limits are checked separately.
+func promotionIneligibility(promotion models.Promotion, flight *models.Flight, now time.Time) string {
+	switch {
+	case !promotion.Active:
+		return PromoReasonInactive
+	case now.Before(promotion.ValidFrom):
+		return PromoReasonNotYetValid
+	case promotion.ValidUntil != nil && !now.Before(*promotion.ValidUntil):
+		return PromoReasonExpired
+	case promotion.Origin != "" && !strings.EqualFold(promotion.Origin, flight.Origin),
+		promotion.Destination != "" && !strings.EqualFold(promotion.Destination, flight.Destination):
+		return PromoReasonRoute
+	case promotion.Airline != "" && !strings.EqualFold(promotion.Airline, flight.Airline):
+		return PromoReasonAirline
+	case promotion.FareClass != "" && !strings.EqualFold(promotion.FareClass, flight.FareClass):
+		return PromoReasonFareClass
+	case promotion.TravelFrom != nil && flight.DepartureTime.Before(*promotion.TravelFrom),
+		promotion.TravelUntil != nil && flight.DepartureTime.After(*promotion.TravelUntil):
+		return PromoReasonTravelDate
+	}
+	return ""
+}
+
+// promotionLimitReason returns why promotion has no uses left for a user who
+// has redeemed it used times, or "" if it can still be redeemed
+func promotionLimitReason(promotion models.Promotion, used int) string {
+	if promotion.MaxRedemptions != nil && promotion.RedemptionCount >= *promotion.MaxRedemptions {
+		return PromoReasonUsageLimitReached
+	}
+	if promotion.MaxRedemptionsPerUser != nil && used >= *promotion.MaxRedemptionsPerUser {
+		return PromoReasonUserLimitReached
+	}
+	return ""
+}
+
+// checkPromotionStacking allows several codes only when all are stackable
+func checkPromotionStacking(promotions []models.Promotion) error {
+	if len(promotions) < 2 {
+		return nil
+	}
+	for _, promotion := range promotions {
+		if !promotion.Stackable {
+			return &PromoCodeError{Code: promotion.Code, Reason: PromoReasonNotStackable}
+		}
+	}
+	return nil
+}
+
+// promotionLineItems returns a discount line, with a negative amount in the
+// base currency, for each promotion. Discounts apply to the base fare only,
+// never to taxes and fees, and together never exceed it.
+func promotionLineItems(baseFare int64, promotions []models.Promotion) []models.FareLineItem {
+	items := make([]models.FareLineItem, 0, len(promotions))
+	remaining := baseFare
+
+	for _, promotion := range promotions {
+		var discount int64
+		switch promotion.DiscountType {
+		case models.DiscountTypePercent:
+			discount = (baseFare*promotion.DiscountValue + 5000) / 10000
+		case models.DiscountTypeFixed:
+			discount = promotion.DiscountValue
+		}
+		if promotion.MaxDiscountAmount != nil && discount > *promotion.MaxDiscountAmount {
+			discount = *promotion.MaxDiscountAmount
+		}
+		if discount > remaining {
+			discount = remaining
+		}
+		remaining -= discount
+
+		items = append(items, models.FareLineItem{
+			Component:   models.FareComponentDiscount,
+			Code:        promotion.Code,
+			Description: promotion.Description,
+			BaseAmount:  -discount,
+		})
+	}
+
+	return items
+}
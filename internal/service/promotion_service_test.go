@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"airline-booking/internal/models"
+)
+
+func TestPromotionIneligibility(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(24 * time.Hour)
+	earlier := now.Add(-24 * time.Hour)
+	flight := &models.Flight{
+		Origin:        "GRU",
+		Destination:   "JFK",
+		Airline:       "LATAM",
+		FareClass:     "economy",
+		DepartureTime: now.Add(30 * 24 * time.Hour),
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *models.Promotion)
+		want   string
+	}{
+		{"unrestricted", func(p *models.Promotion) {}, ""},
+		{"matching restrictions", func(p *models.Promotion) {
+			p.Origin, p.Destination, p.Airline, p.FareClass = "gru", "JFK", "LATAM", "economy"
+		}, ""},
+		{"inactive", func(p *models.Promotion) { p.Active = false }, PromoReasonInactive},
+		{"not yet valid", func(p *models.Promotion) { p.ValidFrom = later }, PromoReasonNotYetValid},
+		{"expired", func(p *models.Promotion) { p.ValidUntil = &now }, PromoReasonExpired},
+		{"other origin", func(p *models.Promotion) { p.Origin = "GIG" }, PromoReasonRoute},
+		{"other destination", func(p *models.Promotion) { p.Destination = "MIA" }, PromoReasonRoute},
+		{"other airline", func(p *models.Promotion) { p.Airline = "GOL" }, PromoReasonAirline},
+		{"other fare class", func(p *models.Promotion) { p.FareClass = "business" }, PromoReasonFareClass},
+		{"departs before travel window", func(p *models.Promotion) {
+			from := now.Add(60 * 24 * time.Hour)
+			p.TravelFrom = &from
+		}, PromoReasonTravelDate},
+		{"departs after travel window", func(p *models.Promotion) { p.TravelUntil = &later }, PromoReasonTravelDate},
+	}
+
+	for _, tt := range tests {
+		promotion := models.Promotion{Code: "SAVE10", Active: true, ValidFrom: earlier}
+		tt.modify(&promotion)
+		if got := promotionIneligibility(promotion, flight, now); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPromotionLimitReason(t *testing.T) {
+	two := 2
+	one := 1
+
+	if got := promotionLimitReason(models.Promotion{RedemptionCount: 100}, 5); got != "" {
+		t.Errorf("unlimited: got %q", got)
+	}
+	if got := promotionLimitReason(models.Promotion{MaxRedemptions: &two, RedemptionCount: 2}, 0); got != PromoReasonUsageLimitReached {
+		t.Errorf("global limit: got %q", got)
+	}
+	if got := promotionLimitReason(models.Promotion{MaxRedemptions: &two, RedemptionCount: 1}, 0); got != "" {
+		t.Errorf("below global limit: got %q", got)
+	}
+	if got := promotionLimitReason(models.Promotion{MaxRedemptionsPerUser: &one}, 1); got != PromoReasonUserLimitReached {
+		t.Errorf("per-user limit: got %q", got)
+	}
+}
+
+func TestCheckPromotionStacking(t *testing.T) {
+	stackable := models.Promotion{Code: "A", Stackable: true}
+	exclusive := models.Promotion{Code: "B"}
+
+	if err := checkPromotionStacking([]models.Promotion{exclusive}); err != nil {
+		t.Errorf("single exclusive code: unexpected error %v", err)
+	}
+	if err := checkPromotionStacking([]models.Promotion{stackable, stackable}); err != nil {
+		t.Errorf("stackable codes: unexpected error %v", err)
+	}
+
+	err := checkPromotionStacking([]models.Promotion{stackable, exclusive})
+	var promoErr *PromoCodeError
+	if !errors.As(err, &promoErr) || promoErr.Code != "B" || promoErr.Reason != PromoReasonNotStackable {
+		t.Fatalf("expected B to be rejected as not stackable, got %v", err)
+	}
+	if !errors.Is(err, ErrPromoCodeRejected) || promoErr.Exhausted() {
+		t.Errorf("expected a non-exhausted ErrPromoCodeRejected, got %v", err)
+	}
+}
+
+func TestPromotionLineItems(t *testing.T) {
+	maxDiscount := int64(2000)
+	promotions := []models.Promotion{
+		{Code: "TENPCT", DiscountType: models.DiscountTypePercent, DiscountValue: 1000},
+		{Code: "CAPPED", DiscountType: models.DiscountTypePercent, DiscountValue: 5000, MaxDiscountAmount: &maxDiscount},
+		{Code: "FIXED", DiscountType: models.DiscountTypeFixed, DiscountValue: 50000},
+	}
+
+	items := promotionLineItems(29900, promotions)
+
+	want := []int64{-2990, -2000, -24910}
+	var total int64
+	for i, item := range items {
+		if item.Component != models.FareComponentDiscount || item.Code != promotions[i].Code {
+			t.Errorf("item %d: got %s %s", i, item.Component, item.Code)
+		}
+		if item.BaseAmount != want[i] {
+			t.Errorf("%s: got %d, want %d", item.Code, item.BaseAmount, want[i])
+		}
+		total += item.BaseAmount
+	}
+	if total != -29900 {
+		t.Errorf("expected discounts capped at the base fare, got %d", total)
+	}
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+
+	"airline-booking/internal/config"
+)
+
+func TestTraceIDAndLogFields(t *testing.T) {
+	assert.Empty(t, TraceID(context.Background()))
+	assert.Nil(t, LogFields(context.Background()))
+
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+
+	traceID := span.SpanContext().TraceID().String()
+	assert.Equal(t, traceID, TraceID(ctx))
+	assert.Equal(t, []zap.Field{
+		zap.String("trace_id", traceID),
+		zap.String("span_id", span.SpanContext().SpanID().String()),
+	}, LogFields(ctx))
+}
+
+func TestSetup(t *testing.T) {
+	for _, exporter := range []string{"none", "stdout", "otlp"} {
+		shutdown, err := Setup(context.Background(), &config.TracingConfig{
+			Exporter:    exporter,
+			Endpoint:    "http://localhost:4318",
+			ServiceName: "airline-booking-test",
+			SampleRatio: 1,
+		}, zap.NewNop())
+		require.NoError(t, err, exporter)
+		require.NoError(t, shutdown(context.Background()), exporter)
+	}
+
+	_, err := Setup(context.Background(), &config.TracingConfig{Exporter: "jaeger"}, zap.NewNop())
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+CREATE TABLE airports (
+    iata_code CHAR(3) NOT NULL PRIMARY KEY,
+    name VARCHAR(120) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    country CHAR(2) NOT NULL,
+    timezone VARCHAR(64) NOT NULL,
+    latitude DECIMAL(9,6) NOT NULL,
+    longitude DECIMAL(9,6) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_airports_city (city),
+    INDEX idx_airports_country (country)
+);
+
+-- Reference data for the airports served by the demo schedule
+INSERT INTO airports (iata_code, name, city, country, timezone, latitude, longitude) VALUES
+('ATL', 'Hartsfield-Jackson Atlanta International Airport', 'Atlanta', 'US', 'America/New_York', 33.636700, -84.428101),
+('BOS', 'Logan International Airport', 'Boston', 'US', 'America/New_York', 42.364300, -71.005203),
+('DFW', 'Dallas/Fort Worth International Airport', 'Dallas', 'US', 'America/Chicago', 32.896801, -97.038002),
+('JFK', 'John F. Kennedy International Airport', 'New York', 'US', 'America/New_York', 40.639801, -73.778900),
+('LAS', 'Harry Reid International Airport', 'Las Vegas', 'US', 'America/Los_Angeles', 36.080101, -115.152000),
+('LAX', 'Los Angeles International Airport', 'Los Angeles', 'US', 'America/Los_Angeles', 33.942501, -118.407997),
+('MIA', 'Miami International Airport', 'Miami', 'US', 'America/New_York', 25.793200, -80.290604),
+('ORD', 'O''Hare International Airport', 'Chicago', 'US', 'America/Chicago', 41.978600, -87.904800),
+('SEA', 'Seattle-Tacoma International Airport', 'Seattle', 'US', 'America/Los_Angeles', 47.449001, -122.308998),
+('SFO', 'San Francisco International Airport', 'San Francisco', 'US', 'America/Los_Angeles', 37.618999, -122.375000),
+('LHR', 'Heathrow Airport', 'London', 'GB', 'Europe/London', 51.470600, -0.461941),
+('CDG', 'Charles de Gaulle International Airport', 'Paris', 'FR', 'Europe/Paris', 49.012798, 2.550000),
+('NRT', 'Narita International Airport', 'Tokyo', 'JP', 'Asia/Tokyo', 35.764702, 140.386002),
+('GRU', 'São Paulo/Guarulhos International Airport', 'São Paulo', 'BR', 'America/Sao_Paulo', -23.435556, -46.473056),
+('GIG', 'Rio de Janeiro/Galeão International Airport', 'Rio de Janeiro', 'BR', 'America/Sao_Paulo', -22.809999, -43.250557);
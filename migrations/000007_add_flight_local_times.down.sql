@@ -0,0 +1,3 @@
+ALTER TABLE flights
+    DROP COLUMN arrival_time_local,
+    DROP COLUMN departure_time_local;
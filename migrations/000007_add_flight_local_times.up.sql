@@ -0,0 +1,13 @@
+-- departure_time/arrival_time hold UTC; the *_local columns keep the wall-clock
+-- time at the origin/destination airport for display and reporting.
+ALTER TABLE flights
+    ADD COLUMN departure_time_local DATETIME NULL AFTER arrival_time,
+    ADD COLUMN arrival_time_local DATETIME NULL AFTER departure_time_local;
+
+-- CONVERT_TZ yields NULL when the server has no time zone tables loaded;
+-- the API falls back to converting the UTC columns in that case.
+UPDATE flights f
+JOIN airports o ON o.iata_code = f.origin
+JOIN airports d ON d.iata_code = f.destination
+SET f.departure_time_local = CONVERT_TZ(f.departure_time, '+00:00', o.timezone),
+    f.arrival_time_local = CONVERT_TZ(f.arrival_time, '+00:00', d.timezone);
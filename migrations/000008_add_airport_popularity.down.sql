@@ -0,0 +1,3 @@
+ALTER TABLE airports
+    DROP INDEX idx_airports_popularity,
+    DROP COLUMN popularity;
@@ -0,0 +1,4 @@
+-- Popularity (annual passengers, thousands) ranks autocomplete suggestions
+ALTER TABLE airports
+    ADD COLUMN popularity INT NOT NULL DEFAULT 0 AFTER longitude,
+    ADD INDEX idx_airports_popularity (popularity);
@@ -0,0 +1,30 @@
+-- Per-cabin seat counters, maintained in the same transaction as the hold,
+-- release, expiry, confirmation and cancellation that changes them.
+-- Available seats = capacity - held - sold - blocked.
+CREATE TABLE flight_inventory (
+    flight_id BIGINT NOT NULL,
+    cabin_class VARCHAR(20) NOT NULL,
+    capacity INT NOT NULL DEFAULT 0,
+    held INT NOT NULL DEFAULT 0,
+    sold INT NOT NULL DEFAULT 0,
+    blocked INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (flight_id, cabin_class),
+    FOREIGN KEY (flight_id) REFERENCES flights(id) ON DELETE CASCADE,
+    CONSTRAINT chk_flight_inventory_counts CHECK (held >= 0 AND sold >= 0 AND blocked >= 0)
+);
+
+-- Backfill from the current seats, holds and tickets. Expired locks that
+-- the cleanup job has not removed yet still count as held.
+INSERT INTO flight_inventory (flight_id, cabin_class, capacity, held, sold)
+SELECT s.flight_id,
+       s.class,
+       COUNT(*),
+       SUM(t.id IS NULL AND l.flight_id IS NOT NULL),
+       SUM(t.id IS NOT NULL)
+FROM seats s
+LEFT JOIN tickets t ON t.flight_id = s.flight_id AND t.seat_no = s.seat_no
+LEFT JOIN seat_locks l ON l.flight_id = s.flight_id AND l.seat_no = s.seat_no
+GROUP BY s.flight_id, s.class;
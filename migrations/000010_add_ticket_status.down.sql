@@ -0,0 +1,7 @@
+ALTER TABLE tickets
+    DROP INDEX idx_tickets_flight_seat,
+    DROP INDEX uk_flight_active_seat_ticket,
+    ADD UNIQUE KEY uk_flight_seat_ticket (flight_id, seat_no),
+    DROP COLUMN active_seat_no,
+    DROP COLUMN cancelled_at,
+    DROP COLUMN status;
@@ -0,0 +1,8 @@
+ALTER TABLE tickets
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'confirmed' AFTER payment_ref,
+    ADD COLUMN cancelled_at DATETIME NULL AFTER status,
+    -- NULL for cancelled tickets so the seat can be sold again
+    ADD COLUMN active_seat_no VARCHAR(10) AS (IF(status = 'cancelled', NULL, seat_no)) STORED,
+    DROP INDEX uk_flight_seat_ticket,
+    ADD UNIQUE KEY uk_flight_active_seat_ticket (flight_id, active_seat_no),
+    ADD INDEX idx_tickets_flight_seat (flight_id, seat_no);
@@ -0,0 +1,17 @@
+DELETE FROM tickets WHERE seat_no IS NULL;
+
+ALTER TABLE tickets
+    DROP INDEX idx_tickets_flight_cabin,
+    DROP INDEX uk_flight_active_seat_ticket,
+    DROP COLUMN active_seat_no,
+    DROP COLUMN cabin_class;
+
+ALTER TABLE tickets
+    MODIFY COLUMN seat_no VARCHAR(10) NOT NULL,
+    ADD COLUMN active_seat_no VARCHAR(10) AS (IF(status = 'cancelled', NULL, seat_no)) STORED,
+    ADD UNIQUE KEY uk_flight_active_seat_ticket (flight_id, active_seat_no);
+
+ALTER TABLE flight_inventory
+    DROP CHECK chk_flight_inventory_overbooking,
+    DROP COLUMN oversold,
+    DROP COLUMN overbooking_limit;
@@ -0,0 +1,23 @@
+-- Seats that may be sold beyond capacity, and how many such seatless
+-- tickets have been issued
+ALTER TABLE flight_inventory
+    ADD COLUMN overbooking_limit INT NOT NULL DEFAULT 0 AFTER blocked,
+    ADD COLUMN oversold INT NOT NULL DEFAULT 0 AFTER overbooking_limit,
+    ADD CONSTRAINT chk_flight_inventory_overbooking CHECK (overbooking_limit >= 0 AND oversold >= 0);
+
+-- Seatless tickets have no seat_no until check-in, so the cabin is stored on
+-- the ticket itself
+ALTER TABLE tickets
+    DROP INDEX uk_flight_active_seat_ticket,
+    DROP COLUMN active_seat_no;
+
+ALTER TABLE tickets
+    MODIFY COLUMN seat_no VARCHAR(10) NULL,
+    ADD COLUMN cabin_class VARCHAR(20) NULL AFTER seat_no,
+    ADD COLUMN active_seat_no VARCHAR(10) AS (IF(status = 'cancelled', NULL, seat_no)) STORED,
+    ADD UNIQUE KEY uk_flight_active_seat_ticket (flight_id, active_seat_no),
+    ADD INDEX idx_tickets_flight_cabin (flight_id, cabin_class);
+
+UPDATE tickets t
+JOIN seats s ON s.flight_id = t.flight_id AND s.seat_no = t.seat_no
+SET t.cabin_class = s.class;
@@ -0,0 +1,4 @@
+ALTER TABLE tickets
+    DROP INDEX idx_tickets_payment_authorization,
+    DROP COLUMN payment_status,
+    DROP COLUMN payment_authorization_id;
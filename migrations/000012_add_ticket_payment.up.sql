@@ -0,0 +1,5 @@
+-- Tickets issued before the payment gateway integration were paid out of band
+ALTER TABLE tickets
+    ADD COLUMN payment_authorization_id VARCHAR(100) NULL AFTER payment_ref,
+    ADD COLUMN payment_status VARCHAR(20) NOT NULL DEFAULT 'captured' AFTER payment_authorization_id,
+    ADD INDEX idx_tickets_payment_authorization (payment_authorization_id);
@@ -0,0 +1,16 @@
+CREATE TABLE payment_webhook_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    authorization_id VARCHAR(100) NULL,
+    payload JSON NOT NULL,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL until the event has been applied to its ticket
+    processed_at TIMESTAMP NULL,
+    processing_error TEXT NULL,
+
+    UNIQUE KEY uk_payment_webhook_event (provider, event_id),
+    INDEX idx_payment_webhook_authorization (authorization_id),
+    INDEX idx_payment_webhook_unprocessed (processed_at, received_at)
+);
@@ -0,0 +1,12 @@
+DELETE FROM seat_locks WHERE status IN ('expired', 'released');
+
+UPDATE seat_locks SET expires_at = '2038-01-01 00:00:00' WHERE status = 'confirmed';
+UPDATE seat_locks SET expires_at = hold_expires_at
+WHERE status = 'payment_pending' AND hold_expires_at IS NOT NULL;
+
+ALTER TABLE seat_locks
+    DROP INDEX idx_seat_locks_status_expires,
+    DROP CHECK chk_seat_locks_status,
+    DROP COLUMN payment_pending_at,
+    DROP COLUMN hold_expires_at,
+    DROP COLUMN status;
@@ -0,0 +1,14 @@
+-- Holds move active -> payment_pending -> confirmed, or end as expired or
+-- released. Rows in a terminal state are kept until the seat is held again.
+ALTER TABLE seat_locks
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active' AFTER holder_id,
+    -- Expiry of the active hold, restored if payment_pending rolls back
+    ADD COLUMN hold_expires_at DATETIME NULL AFTER expires_at,
+    ADD COLUMN payment_pending_at DATETIME NULL AFTER hold_expires_at,
+    ADD CONSTRAINT chk_seat_locks_status
+        CHECK (status IN ('active', 'payment_pending', 'confirmed', 'expired', 'released')),
+    ADD INDEX idx_seat_locks_status_expires (status, expires_at);
+
+-- Confirmed locks were marked by a far-future expiry
+UPDATE seat_locks SET status = 'confirmed', expires_at = NULL
+WHERE expires_at = '2038-01-01 00:00:00';
@@ -0,0 +1,7 @@
+ALTER TABLE tickets
+    DROP COLUMN exchange_rate_effective_from,
+    DROP COLUMN exchange_rate,
+    DROP COLUMN base_currency,
+    DROP COLUMN base_price_amount;
+
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,29 @@
+-- One row per rate change; the rate in effect at a time is the latest row
+-- with effective_from at or before it. rate is units of quote_currency per
+-- unit of base_currency.
+CREATE TABLE exchange_rates (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL,
+    effective_from DATETIME NOT NULL,
+    source VARCHAR(50) NOT NULL DEFAULT 'admin',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_exchange_rate (base_currency, quote_currency, effective_from),
+    CONSTRAINT chk_exchange_rates_rate CHECK (rate > 0)
+);
+
+-- Tickets snapshot the fare in the base currency and the rate it was sold at;
+-- earlier tickets were all sold in the base currency
+ALTER TABLE tickets
+    ADD COLUMN base_price_amount BIGINT NULL AFTER currency,
+    ADD COLUMN base_currency CHAR(3) NULL AFTER base_price_amount,
+    ADD COLUMN exchange_rate DECIMAL(18,8) NOT NULL DEFAULT 1 AFTER base_currency,
+    ADD COLUMN exchange_rate_effective_from DATETIME NULL AFTER exchange_rate;
+
+UPDATE tickets SET base_price_amount = price_amount, base_currency = currency;
+
+ALTER TABLE tickets
+    MODIFY COLUMN base_price_amount BIGINT NOT NULL,
+    MODIFY COLUMN base_currency CHAR(3) NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE flights
+    DROP CHECK chk_flights_base_price,
+    DROP COLUMN base_price;
@@ -0,0 +1,6 @@
+-- The listed fare of each flight in minor units of the base currency, which
+-- search quotes and tickets are priced from. Flights created before it was
+-- stored keep the flat fare they were sold at.
+ALTER TABLE flights
+    ADD COLUMN base_price BIGINT NOT NULL DEFAULT 29900 AFTER fare_class,
+    ADD CONSTRAINT chk_flights_base_price CHECK (base_price > 0);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ticket_line_items;
+DROP TABLE IF EXISTS fare_rules;
@@ -0,0 +1,48 @@
+-- Taxes and fees added on top of the base fare. A NULL origin_country,
+-- destination_country or cabin_class matches any value; every matching rule
+-- applies. The charge is fixed_amount (minor units of the base currency) plus
+-- percent_bps basis points of the base fare.
+CREATE TABLE fare_rules (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    component VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    origin_country CHAR(2) NULL,
+    destination_country CHAR(2) NULL,
+    cabin_class VARCHAR(20) NULL,
+    fixed_amount BIGINT NOT NULL DEFAULT 0,
+    percent_bps INT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_fare_rules_code (code),
+    INDEX idx_fare_rules_route (origin_country, destination_country),
+    CONSTRAINT chk_fare_rules_component CHECK (component IN ('airport_tax', 'fuel_surcharge', 'seat_fee', 'service_fee')),
+    CONSTRAINT chk_fare_rules_amounts CHECK (fixed_amount >= 0 AND percent_bps >= 0)
+);
+
+INSERT INTO fare_rules (code, component, description, origin_country, destination_country, cabin_class, fixed_amount, percent_bps) VALUES
+('BR_DEPARTURE_TAX', 'airport_tax', 'Brazil boarding fee', 'BR', NULL, NULL, 3500, 0),
+('US_DEPARTURE_TAX', 'airport_tax', 'US passenger facility charge', 'US', NULL, NULL, 560, 0),
+('US_ARRIVAL_TAX', 'airport_tax', 'US customs and immigration fee', NULL, 'US', NULL, 2150, 0),
+('FUEL_SURCHARGE', 'fuel_surcharge', 'Fuel surcharge', NULL, NULL, NULL, 0, 1000),
+('SEAT_FEE_BUSINESS', 'seat_fee', 'Business seat selection', NULL, NULL, 'business', 5000, 0),
+('SEAT_FEE_FIRST', 'seat_fee', 'First class seat selection', NULL, NULL, 'first', 9000, 0),
+('SERVICE_FEE', 'service_fee', 'Booking service fee', NULL, NULL, NULL, 1500, 0);
+
+-- The fare breakdown of each ticket. amount is in the ticket's currency and
+-- the items sum to tickets.price_amount; base_amount is before conversion.
+CREATE TABLE ticket_line_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    ticket_id BIGINT NOT NULL,
+    component VARCHAR(20) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    base_amount BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    INDEX idx_ticket_line_items_ticket (ticket_id)
+);
@@ -0,0 +1,62 @@
+-- Promo codes. discount_value is basis points of the base fare for percent
+-- discounts and minor units of the base currency for fixed ones. NULL
+-- restrictions and limits mean unrestricted.
+CREATE TABLE promotions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(40) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    discount_type VARCHAR(10) NOT NULL,
+    discount_value BIGINT NOT NULL,
+    max_discount_amount BIGINT NULL,
+    origin CHAR(3) NULL,
+    destination CHAR(3) NULL,
+    airline VARCHAR(10) NULL,
+    fare_class VARCHAR(20) NULL,
+    travel_from DATETIME NULL,
+    travel_until DATETIME NULL,
+    valid_from DATETIME NOT NULL,
+    valid_until DATETIME NULL,
+    max_redemptions INT NULL,
+    max_redemptions_per_user INT NULL,
+    redemption_count INT NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_promotions_code (code),
+    CONSTRAINT chk_promotions_discount CHECK (
+        (discount_type = 'percent' AND discount_value BETWEEN 1 AND 10000)
+        OR (discount_type = 'fixed' AND discount_value > 0)
+    ),
+    CONSTRAINT chk_promotions_redemptions CHECK (max_redemptions IS NULL OR redemption_count <= max_redemptions)
+);
+
+-- One row per promotion used on a ticket; redemption_count is kept in step in
+-- the same transaction
+CREATE TABLE promotion_redemptions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    promotion_id BIGINT NOT NULL,
+    user_id VARCHAR(100) NOT NULL,
+    ticket_id BIGINT NOT NULL,
+    discount_amount BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id),
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    UNIQUE KEY uk_promotion_redemptions_ticket (promotion_id, ticket_id),
+    INDEX idx_promotion_redemptions_user (promotion_id, user_id)
+);
+
+-- Codes applied to a hold, redeemed when the hold is confirmed. Replaced
+-- whenever the seat is held again.
+CREATE TABLE hold_promotions (
+    flight_id BIGINT NOT NULL,
+    seat_no VARCHAR(10) NOT NULL,
+    holder_id VARCHAR(100) NOT NULL,
+    promotion_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (flight_id, seat_no, promotion_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE CASCADE
+);
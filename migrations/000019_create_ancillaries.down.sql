@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ancillary_inventory;
+DROP TABLE IF EXISTS ancillaries;
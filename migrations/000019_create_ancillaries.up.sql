@@ -0,0 +1,46 @@
+-- Extras sold with a ticket. A NULL flight_id, origin or destination matches
+-- any flight; price_amount is in minor units of the base currency.
+-- capacity_per_flight limits how many units of an ancillary each flight can
+-- sell (NULL means unlimited); max_per_ticket limits each passenger.
+CREATE TABLE ancillaries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    category VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    price_amount BIGINT NOT NULL,
+    flight_id BIGINT NULL,
+    origin CHAR(3) NULL,
+    destination CHAR(3) NULL,
+    capacity_per_flight INT NULL,
+    max_per_ticket INT NOT NULL DEFAULT 1,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_ancillaries_code (code),
+    FOREIGN KEY (flight_id) REFERENCES flights(id) ON DELETE CASCADE,
+    CONSTRAINT chk_ancillaries_category CHECK (category IN ('checked_bag', 'extra_legroom', 'meal', 'priority_boarding', 'lounge_access')),
+    CONSTRAINT chk_ancillaries_amounts CHECK (price_amount >= 0 AND max_per_ticket > 0 AND (capacity_per_flight IS NULL OR capacity_per_flight >= 0))
+);
+
+INSERT INTO ancillaries (code, category, name, price_amount, capacity_per_flight, max_per_ticket) VALUES
+('CHECKED_BAG', 'checked_bag', 'Checked bag up to 23 kg', 4500, NULL, 3),
+('EXTRA_LEGROOM', 'extra_legroom', 'Extra legroom', 3900, 12, 1),
+('MEAL_STANDARD', 'meal', 'Hot meal', 1500, NULL, 2),
+('MEAL_VEGETARIAN', 'meal', 'Vegetarian hot meal', 1500, 20, 2),
+('PRIORITY_BOARDING', 'priority_boarding', 'Priority boarding', 2000, 30, 1),
+('LOUNGE_ACCESS', 'lounge_access', 'Departure lounge access', 3500, 40, 1);
+
+-- Units sold of each limited ancillary per flight. Rows are created on the
+-- first sale; sold never exceeds the ancillary's capacity_per_flight.
+CREATE TABLE ancillary_inventory (
+    flight_id BIGINT NOT NULL,
+    ancillary_id BIGINT NOT NULL,
+    sold INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (flight_id, ancillary_id),
+    FOREIGN KEY (flight_id) REFERENCES flights(id) ON DELETE CASCADE,
+    FOREIGN KEY (ancillary_id) REFERENCES ancillaries(id) ON DELETE CASCADE,
+    CONSTRAINT chk_ancillary_inventory_sold CHECK (sold >= 0)
+);
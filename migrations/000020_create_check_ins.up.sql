@@ -0,0 +1,25 @@
+-- Online check-in of a ticket: the travel document the passenger presented
+-- and the boarding sequence number printed on the boarding pass. A ticket is
+-- checked in at most once; sequence numbers count up from 1 per flight.
+CREATE TABLE check_ins (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    ticket_id BIGINT NOT NULL,
+    flight_id BIGINT NOT NULL,
+    seat_no VARCHAR(10) NOT NULL,
+    boarding_sequence INT NOT NULL,
+    first_name VARCHAR(100) NOT NULL,
+    last_name VARCHAR(100) NOT NULL,
+    date_of_birth DATE NOT NULL,
+    nationality CHAR(2) NOT NULL,
+    document_type VARCHAR(20) NOT NULL,
+    document_number VARCHAR(20) NOT NULL,
+    document_country CHAR(2) NOT NULL,
+    document_expiry DATE NOT NULL,
+    checked_in_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_check_ins_ticket (ticket_id),
+    UNIQUE KEY uk_check_ins_flight_sequence (flight_id, boarding_sequence),
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE CASCADE,
+    FOREIGN KEY (flight_id) REFERENCES flights(id) ON DELETE CASCADE,
+    CONSTRAINT chk_check_ins_document_type CHECK (document_type IN ('passport', 'national_id'))
+);
@@ -0,0 +1,3 @@
+ALTER TABLE check_ins
+    DROP INDEX idx_check_ins_flight_boarded,
+    DROP COLUMN boarded_at;
@@ -0,0 +1,5 @@
+-- Set when the boarding pass is scanned at the gate; a second scan of the
+-- same pass is rejected as a duplicate
+ALTER TABLE check_ins
+    ADD COLUMN boarded_at DATETIME NULL AFTER checked_in_at,
+    ADD INDEX idx_check_ins_flight_boarded (flight_id, boarded_at);
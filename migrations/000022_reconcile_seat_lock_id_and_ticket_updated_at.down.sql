@@ -0,0 +1,4 @@
+ALTER TABLE tickets DROP COLUMN updated_at;
+
+-- Drops uk_seat_locks_id with it
+ALTER TABLE seat_locks DROP COLUMN id;
@@ -0,0 +1,9 @@
+-- Holds and tickets are read with seat_locks.id and tickets.updated_at,
+-- which only the retired scripts/mysql_init.sql schema created. The
+-- (flight_id, seat_no) primary key still serialises holds on a seat.
+ALTER TABLE seat_locks
+    ADD COLUMN id BIGINT NOT NULL AUTO_INCREMENT FIRST,
+    ADD UNIQUE KEY uk_seat_locks_id (id);
+
+ALTER TABLE tickets
+    ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP AFTER created_at;
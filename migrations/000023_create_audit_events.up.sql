@@ -0,0 +1,21 @@
+-- Append-only record of booking state changes, written in the same
+-- transaction as the change. Rows are never updated or deleted.
+CREATE TABLE audit_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    occurred_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    -- User-ID of the caller, or "system" for background jobs
+    actor VARCHAR(100) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    flight_id BIGINT NULL,
+    seat_no VARCHAR(10) NULL,
+    pnr_code VARCHAR(10) NULL,
+    before_state JSON NULL,
+    after_state JSON NULL,
+    request_id VARCHAR(100) NULL,
+    ip_address VARCHAR(45) NULL,
+
+    INDEX idx_audit_events_occurred_at (occurred_at),
+    INDEX idx_audit_events_pnr (pnr_code, occurred_at),
+    INDEX idx_audit_events_actor (actor, occurred_at),
+    INDEX idx_audit_events_flight (flight_id, occurred_at)
+);
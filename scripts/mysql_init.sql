@@ -1,70 +1,9 @@
--- Script de inicialização para MySQL
-CREATE DATABASE IF NOT EXISTS airline_booking;
-USE airline_booking;
+-- Init script for the MySQL docker container, mounted to
+-- /docker-entrypoint-initdb.d/. It only sets up access: the schema comes
+-- from migrations/, applied by the API with MIGRATE_ON_START=true or by
+-- `make migrate-up`.
+
+SET time_zone = '+00:00';
 
--- Garantir que o usuário tenha as permissões corretas
 GRANT ALL PRIVILEGES ON airline_booking.* TO 'airline_user'@'%';
 FLUSH PRIVILEGES;
-
--- Criar as tabelas necessárias
-CREATE TABLE IF NOT EXISTS flights (
-    id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    origin VARCHAR(10) NOT NULL,
-    destination VARCHAR(10) NOT NULL,
-    departure_time DATETIME NOT NULL,
-    arrival_time DATETIME NOT NULL,
-    airline VARCHAR(10) NOT NULL,
-    aircraft VARCHAR(20) NOT NULL,
-    fare_class VARCHAR(20) NOT NULL DEFAULT 'economy',
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
-);
-
-CREATE TABLE IF NOT EXISTS seats (
-    id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    flight_id BIGINT NOT NULL,
-    seat_no VARCHAR(10) NOT NULL,
-    class ENUM('economy', 'business', 'first') NOT NULL DEFAULT 'economy',
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (flight_id) REFERENCES flights(id) ON DELETE CASCADE,
-    UNIQUE KEY unique_flight_seat (flight_id, seat_no)
-);
-
-CREATE TABLE IF NOT EXISTS seat_locks (
-    id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    flight_id BIGINT NOT NULL,
-    seat_no VARCHAR(10) NOT NULL,
-    holder_id VARCHAR(255) NOT NULL,
-    expires_at TIMESTAMP NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (flight_id) REFERENCES flights(id) ON DELETE CASCADE,
-    UNIQUE KEY unique_flight_seat_lock (flight_id, seat_no)
-);
-
-CREATE TABLE IF NOT EXISTS tickets (
-    id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    flight_id BIGINT NOT NULL,
-    seat_no VARCHAR(10) NOT NULL,
-    user_id VARCHAR(255) NOT NULL,
-    price_amount BIGINT NOT NULL,
-    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
-    pnr_code VARCHAR(20) NOT NULL UNIQUE,
-    payment_ref VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    FOREIGN KEY (flight_id) REFERENCES flights(id) ON DELETE CASCADE,
-    UNIQUE KEY unique_flight_seat_ticket (flight_id, seat_no)
-);
-
-CREATE TABLE IF NOT EXISTS idempotency_keys (
-    id BIGINT AUTO_INCREMENT PRIMARY KEY,
-    request_id VARCHAR(255) NOT NULL,
-    route VARCHAR(255) NOT NULL,
-    user_id VARCHAR(255) NOT NULL,
-    response_hash VARCHAR(255) NOT NULL,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
-    UNIQUE KEY unique_request (request_id, route, user_id)
-);
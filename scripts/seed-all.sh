@@ -58,7 +58,7 @@ curl -s -X PUT "http://localhost:9200/flights" \
         "airline": {"type": "keyword"},
         "aircraft": {"type": "text"},
         "fare_class": {"type": "keyword"},
-        "base_price": {"type": "float"}
+        "base_price": {"type": "long"}
       }
     }
   }' > /dev/null
@@ -117,13 +117,27 @@ INSERT INTO seat_locks (flight_id, seat_no, holder_id, expires_at) VALUES
 (3, '2A', 'user111', DATE_ADD(NOW(), INTERVAL 8 MINUTE));
 
 -- Add some sample confirmed tickets
-INSERT INTO tickets (flight_id, seat_no, user_id, price_amount, currency, pnr_code, payment_ref) VALUES
-(1, '10A', 'customer001', 29900, 'USD', 'ABC001', 'pay_001_12345'),
-(1, '10B', 'customer002', 29900, 'USD', 'ABC002', 'pay_002_12346'),
-(1, '1A', 'customer003', 149900, 'USD', 'ABC003', 'pay_003_12347'),
-(2, '5A', 'customer004', 31900, 'USD', 'DEF001', 'pay_004_12348'),
-(2, '5B', 'customer005', 31900, 'USD', 'DEF002', 'pay_005_12349'),
-(3, '1A', 'customer006', 89900, 'USD', 'GHI001', 'pay_006_12350'),
-(3, '10A', 'customer007', 49900, 'USD', 'GHI002', 'pay_007_12351'),
-(4, '15A', 'customer008', 27900, 'USD', 'JKL001', 'pay_008_12352'),
-(5, '5A', 'customer009', 19900, 'USD', 'MNO001', 'pay_009_12353');
+INSERT INTO tickets (flight_id, seat_no, user_id, price_amount, currency, base_price_amount, base_currency, pnr_code, payment_ref) VALUES
+(1, '10A', 'customer001', 29900, 'USD', 29900, 'USD', 'ABC001', 'pay_001_12345'),
+(1, '10B', 'customer002', 29900, 'USD', 29900, 'USD', 'ABC002', 'pay_002_12346'),
+(1, '1A', 'customer003', 149900, 'USD', 149900, 'USD', 'ABC003', 'pay_003_12347'),
+(2, '5A', 'customer004', 31900, 'USD', 31900, 'USD', 'DEF001', 'pay_004_12348'),
+(2, '5B', 'customer005', 31900, 'USD', 31900, 'USD', 'DEF002', 'pay_005_12349'),
+(3, '1A', 'customer006', 89900, 'USD', 89900, 'USD', 'GHI001', 'pay_006_12350'),
+(3, '10A', 'customer007', 49900, 'USD', 49900, 'USD', 'GHI002', 'pay_007_12351'),
+(4, '15A', 'customer008', 27900, 'USD', 27900, 'USD', 'JKL001', 'pay_008_12352'),
+(5, '5A', 'customer009', 19900, 'USD', 19900, 'USD', 'MNO001', 'pay_009_12353');
+
+-- Rebuild the per-cabin counters from the seats, holds and tickets above
+DELETE FROM flight_inventory;
+INSERT INTO flight_inventory (flight_id, cabin_class, capacity, held, sold)
+SELECT s.flight_id,
+       s.class,
+       COUNT(*),
+       SUM(t.id IS NULL AND l.flight_id IS NOT NULL),
+       SUM(t.id IS NOT NULL)
+FROM seats s
+LEFT JOIN tickets t ON t.flight_id = s.flight_id AND t.seat_no = s.seat_no AND t.status <> 'cancelled'
+LEFT JOIN seat_locks l ON l.flight_id = s.flight_id AND l.seat_no = s.seat_no
+    AND l.status IN ('active', 'payment_pending')
+GROUP BY s.flight_id, s.class;
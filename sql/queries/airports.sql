@@ -0,0 +1,18 @@
+-- name: GetAirport :one
+SELECT * FROM airports WHERE iata_code = ?;
+
+-- name: ListAirports :many
+SELECT * FROM airports ORDER BY iata_code;
+
+-- name: UpsertAirport :exec
+INSERT INTO airports (iata_code, name, city, country, timezone, latitude, longitude, popularity)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    name = VALUES(name),
+    city = VALUES(city),
+    country = VALUES(country),
+    timezone = VALUES(timezone),
+    latitude = VALUES(latitude),
+    longitude = VALUES(longitude),
+    popularity = VALUES(popularity),
+    updated_at = CURRENT_TIMESTAMP;
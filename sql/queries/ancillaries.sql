@@ -0,0 +1,33 @@
+-- name: ListFlightAncillaries :many
+SELECT a.*, COALESCE(i.sold, 0) AS sold
+FROM ancillaries a
+LEFT JOIN ancillary_inventory i ON i.ancillary_id = a.id AND i.flight_id = sqlc.arg('flight_id')
+WHERE a.active = TRUE
+  AND (a.flight_id IS NULL OR a.flight_id = sqlc.arg('flight_id'))
+  AND (a.origin IS NULL OR a.origin = sqlc.arg('origin'))
+  AND (a.destination IS NULL OR a.destination = sqlc.arg('destination'))
+ORDER BY a.category, a.id;
+
+-- name: EnsureAncillaryInventory :exec
+INSERT INTO ancillary_inventory (flight_id, ancillary_id, sold)
+VALUES (?, ?, 0)
+ON DUPLICATE KEY UPDATE flight_id = flight_id;
+
+-- name: ReserveAncillaryUnits :execrows
+UPDATE ancillary_inventory i
+JOIN ancillaries a ON a.id = i.ancillary_id
+SET i.sold = i.sold + sqlc.arg('quantity')
+WHERE i.flight_id = sqlc.arg('flight_id') AND i.ancillary_id = sqlc.arg('ancillary_id')
+  AND i.sold + sqlc.arg('quantity') <= a.capacity_per_flight;
+
+-- name: ReleaseTicketAncillaryUnits :exec
+UPDATE ancillary_inventory i
+JOIN (
+    SELECT a.id AS ancillary_id, COUNT(*) AS units
+    FROM ticket_line_items li
+    JOIN ancillaries a ON a.code = li.code
+    WHERE li.ticket_id = sqlc.arg('ticket_id') AND li.component = 'ancillary'
+    GROUP BY a.id
+) s ON s.ancillary_id = i.ancillary_id
+SET i.sold = GREATEST(i.sold - s.units, 0)
+WHERE i.flight_id = sqlc.arg('flight_id');
@@ -0,0 +1,13 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (actor, action, flight_id, seat_no, pnr_code, before_state, after_state, request_id, ip_address)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListAuditEvents :many
+SELECT * FROM audit_events
+WHERE (sqlc.arg('pnr_code') = '' OR pnr_code = sqlc.arg('pnr_code'))
+AND (sqlc.arg('actor') = '' OR actor = sqlc.arg('actor'))
+AND (sqlc.arg('flight_id') = 0 OR flight_id = sqlc.arg('flight_id'))
+AND (sqlc.narg('from') IS NULL OR occurred_at >= sqlc.narg('from'))
+AND (sqlc.narg('to') IS NULL OR occurred_at < sqlc.narg('to'))
+ORDER BY occurred_at DESC, id DESC
+LIMIT ?;
@@ -0,0 +1,45 @@
+-- name: CreateCheckIn :execlastid
+INSERT INTO check_ins (
+    ticket_id, flight_id, seat_no, boarding_sequence, first_name, last_name, date_of_birth,
+    nationality, document_type, document_number, document_country, document_expiry
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetCheckInByTicket :one
+SELECT * FROM check_ins WHERE ticket_id = ?;
+
+-- name: ListFlightCheckIns :many
+SELECT * FROM check_ins WHERE flight_id = ? ORDER BY boarding_sequence;
+
+-- name: MarkCheckInBoarded :execrows
+UPDATE check_ins SET boarded_at = ? WHERE ticket_id = ? AND boarded_at IS NULL;
+
+-- name: NextBoardingSequence :one
+-- Locks the flight row so concurrent check-ins are numbered one at a time
+SELECT COALESCE(MAX(c.boarding_sequence), 0) + 1
+FROM flights f
+LEFT JOIN check_ins c ON c.flight_id = f.id
+WHERE f.id = ?
+FOR UPDATE;
+
+-- name: GetFreeCabinSeat :one
+SELECT s.seat_no
+FROM seats s
+LEFT JOIN seat_locks l ON l.flight_id = s.flight_id AND l.seat_no = s.seat_no
+    AND l.status IN ('active', 'payment_pending', 'confirmed')
+LEFT JOIN tickets t ON t.flight_id = s.flight_id AND t.active_seat_no = s.seat_no
+WHERE s.flight_id = ? AND s.class = ? AND l.seat_no IS NULL AND t.id IS NULL
+ORDER BY s.id
+LIMIT 1;
+
+-- name: CreateConfirmedSeatLock :exec
+INSERT INTO seat_locks (flight_id, seat_no, holder_id, status, expires_at)
+VALUES (?, ?, ?, 'confirmed', NULL);
+
+-- name: ReclaimSeatLock :execrows
+UPDATE seat_locks
+SET holder_id = ?, status = 'confirmed', expires_at = NULL, hold_expires_at = NULL, payment_pending_at = NULL,
+    created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE flight_id = ? AND seat_no = ? AND status IN ('expired', 'released');
+
+-- name: UpdateTicketSeat :exec
+UPDATE tickets SET seat_no = ? WHERE id = ?;
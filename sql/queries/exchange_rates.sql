@@ -0,0 +1,16 @@
+-- name: UpsertExchangeRate :exec
+INSERT INTO exchange_rates (base_currency, quote_currency, rate, effective_from, source)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE rate = VALUES(rate), source = VALUES(source);
+
+-- name: GetEffectiveExchangeRate :one
+SELECT * FROM exchange_rates
+WHERE base_currency = sqlc.arg('base_currency') AND quote_currency = sqlc.arg('quote_currency')
+  AND effective_from <= sqlc.arg('at')
+ORDER BY effective_from DESC
+LIMIT 1;
+
+-- name: ListExchangeRates :many
+SELECT * FROM exchange_rates
+WHERE base_currency = ?
+ORDER BY quote_currency, effective_from DESC;
@@ -0,0 +1,6 @@
+-- name: ListActiveFareRules :many
+SELECT * FROM fare_rules
+WHERE active = TRUE
+  AND (origin_country IS NULL OR origin_country = sqlc.arg('origin_country'))
+  AND (destination_country IS NULL OR destination_country = sqlc.arg('destination_country'))
+ORDER BY id;
@@ -12,8 +12,8 @@ ORDER BY departure_time
 LIMIT ? OFFSET ?;
 
 -- name: CreateFlight :execlastid
-INSERT INTO flights (origin, destination, departure_time, arrival_time, airline, aircraft, fare_class)
-VALUES (?, ?, ?, ?, ?, ?, ?);
+INSERT INTO flights (origin, destination, departure_time, arrival_time, departure_time_local, arrival_time_local, airline, aircraft, fare_class, base_price)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateFlight :exec
 UPDATE flights 
@@ -23,3 +23,19 @@ WHERE id = ?;
 
 -- name: DeleteFlight :exec
 DELETE FROM flights WHERE id = ?;
+
+-- name: SearchFlights :many
+SELECT * FROM flights
+WHERE origin = @origin AND destination = @destination
+AND departure_time >= @departure_from AND departure_time < @departure_to
+AND (@fare_class = '' OR fare_class = @fare_class)
+AND (@airline = '' OR airline = @airline)
+ORDER BY departure_time, id
+LIMIT ? OFFSET ?;
+
+-- name: CountSearchFlights :one
+SELECT COUNT(*) FROM flights
+WHERE origin = @origin AND destination = @destination
+AND departure_time >= @departure_from AND departure_time < @departure_to
+AND (@fare_class = '' OR fare_class = @fare_class)
+AND (@airline = '' OR airline = @airline);
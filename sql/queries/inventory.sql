@@ -0,0 +1,52 @@
+-- name: IncrementInventoryCapacity :exec
+INSERT INTO flight_inventory (flight_id, cabin_class, capacity)
+VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE capacity = capacity + VALUES(capacity);
+
+-- name: AdjustSeatInventory :execrows
+UPDATE flight_inventory fi
+JOIN seats s ON s.flight_id = fi.flight_id AND s.class = fi.cabin_class
+SET fi.held = fi.held + sqlc.arg('held_delta'),
+    fi.sold = fi.sold + sqlc.arg('sold_delta')
+WHERE s.flight_id = ? AND s.seat_no = ?;
+
+-- name: ReleaseExpiredInventory :execrows
+UPDATE flight_inventory fi
+JOIN (
+    SELECT s.flight_id, s.class, COUNT(*) AS expired
+    FROM seat_locks l
+    JOIN seats s ON s.flight_id = l.flight_id AND s.seat_no = l.seat_no
+    WHERE l.status IN ('active', 'payment_pending') AND l.expires_at < ?
+    GROUP BY s.flight_id, s.class
+) x ON x.flight_id = fi.flight_id AND x.class = fi.cabin_class
+SET fi.held = fi.held - x.expired;
+
+-- name: SetOverbookingLimit :execrows
+UPDATE flight_inventory SET overbooking_limit = ?
+WHERE flight_id = ? AND cabin_class = ?;
+
+-- name: ReserveOversoldSeat :execrows
+UPDATE flight_inventory SET oversold = oversold + 1
+WHERE flight_id = ? AND cabin_class = ?
+AND held + sold + blocked + oversold < capacity + overbooking_limit;
+
+-- name: ReleaseOversoldSeat :execrows
+UPDATE flight_inventory SET oversold = oversold - 1
+WHERE flight_id = ? AND cabin_class = ? AND oversold > 0;
+
+-- name: ListFlightsAtRisk :many
+SELECT sqlc.embed(fi), f.origin, f.destination, f.airline, f.departure_time
+FROM flight_inventory fi
+JOIN flights f ON f.id = fi.flight_id
+WHERE f.departure_time >= ?
+AND fi.oversold > 0
+AND fi.held + fi.sold + fi.blocked + fi.oversold > fi.capacity
+ORDER BY f.departure_time, fi.flight_id, fi.cabin_class;
+
+-- name: GetFlightInventory :many
+SELECT * FROM flight_inventory WHERE flight_id = ? ORDER BY cabin_class;
+
+-- name: ListFlightInventory :many
+SELECT * FROM flight_inventory
+WHERE flight_id IN (sqlc.slice('flight_ids'))
+ORDER BY flight_id, cabin_class;
@@ -0,0 +1,19 @@
+-- name: CreatePaymentWebhookEvent :execlastid
+INSERT IGNORE INTO payment_webhook_events (provider, event_id, event_type, authorization_id, payload)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetPaymentWebhookEvent :one
+SELECT * FROM payment_webhook_events WHERE provider = ? AND event_id = ?;
+
+-- name: GetPaymentWebhookEventForUpdate :one
+SELECT * FROM payment_webhook_events WHERE id = ? FOR UPDATE;
+
+-- name: ListUnprocessedPaymentWebhookEvents :many
+SELECT * FROM payment_webhook_events
+WHERE provider = ? AND processed_at IS NULL
+ORDER BY received_at, id;
+
+-- name: MarkPaymentWebhookEventProcessed :exec
+UPDATE payment_webhook_events
+SET processed_at = IF(sqlc.narg('processing_error') IS NULL, NOW(), NULL), processing_error = sqlc.narg('processing_error')
+WHERE id = sqlc.arg('id');
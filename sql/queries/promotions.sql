@@ -0,0 +1,43 @@
+-- name: CreatePromotion :execlastid
+INSERT INTO promotions (code, description, discount_type, discount_value, max_discount_amount,
+                        origin, destination, airline, fare_class, travel_from, travel_until, valid_from, valid_until,
+                        max_redemptions, max_redemptions_per_user, stackable)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetPromotion :one
+SELECT * FROM promotions WHERE id = ?;
+
+-- name: GetPromotionByCode :one
+SELECT * FROM promotions WHERE code = ?;
+
+-- name: GetPromotionForUpdate :one
+SELECT * FROM promotions WHERE id = ? FOR UPDATE;
+
+-- name: ListPromotions :many
+SELECT * FROM promotions ORDER BY created_at DESC, id DESC;
+
+-- name: CountUserPromotionRedemptions :one
+SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = ? AND user_id = ?;
+
+-- name: CountUserPromotionRedemptionsForUpdate :one
+SELECT COUNT(*) FROM promotion_redemptions WHERE promotion_id = ? AND user_id = ? FOR UPDATE;
+
+-- name: CreatePromotionRedemption :exec
+INSERT INTO promotion_redemptions (promotion_id, user_id, ticket_id, discount_amount)
+VALUES (?, ?, ?, ?);
+
+-- name: IncrementPromotionRedemptions :exec
+UPDATE promotions SET redemption_count = redemption_count + 1 WHERE id = ?;
+
+-- name: DeleteHoldPromotions :exec
+DELETE FROM hold_promotions WHERE flight_id = ? AND seat_no = ?;
+
+-- name: CreateHoldPromotion :exec
+INSERT INTO hold_promotions (flight_id, seat_no, holder_id, promotion_id) VALUES (?, ?, ?, ?);
+
+-- name: ListHoldPromotionCodes :many
+SELECT p.code
+FROM hold_promotions hp
+JOIN promotions p ON p.id = hp.promotion_id
+WHERE hp.flight_id = ? AND hp.seat_no = ? AND hp.holder_id = ?
+ORDER BY hp.created_at, p.id;
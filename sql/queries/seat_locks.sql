@@ -2,32 +2,59 @@
 SELECT * FROM seat_locks WHERE flight_id = ? AND seat_no = ?;
 
 -- name: CreateSeatLock :exec
-INSERT INTO seat_locks (flight_id, seat_no, holder_id, expires_at)
-VALUES (?, ?, ?, ?)
-ON DUPLICATE KEY UPDATE
-    holder_id = VALUES(holder_id),
-    expires_at = VALUES(expires_at),
-    updated_at = CURRENT_TIMESTAMP;
+INSERT INTO seat_locks (flight_id, seat_no, holder_id, status, expires_at)
+VALUES (?, ?, ?, 'active', ?);
+
+-- name: ReactivateSeatLock :execrows
+UPDATE seat_locks
+SET holder_id = ?, status = 'active', expires_at = ?, hold_expires_at = NULL, payment_pending_at = NULL,
+    created_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE flight_id = ? AND seat_no = ? AND status IN ('expired', 'released');
 
 -- name: UpdateSeatLock :execrows
 UPDATE seat_locks 
 SET holder_id = ?, expires_at = ?, updated_at = CURRENT_TIMESTAMP
-WHERE flight_id = ? AND seat_no = ? 
+WHERE flight_id = ? AND seat_no = ? AND status = 'active'
 AND (expires_at < NOW() OR holder_id = ?);
 
+-- name: MarkSeatLockPaymentPending :execrows
+UPDATE seat_locks
+SET hold_expires_at = IF(status = 'active', expires_at, hold_expires_at),
+    status = 'payment_pending', expires_at = ?, payment_pending_at = NOW(), updated_at = CURRENT_TIMESTAMP
+WHERE flight_id = ? AND seat_no = ? AND holder_id = ?
+AND status IN ('active', 'payment_pending') AND expires_at > NOW();
+
+-- name: RevertSeatLockToActive :execrows
+UPDATE seat_locks
+SET status = 'active', expires_at = COALESCE(hold_expires_at, expires_at), hold_expires_at = NULL,
+    payment_pending_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE flight_id = ? AND seat_no = ? AND holder_id = ? AND status = 'payment_pending';
+
 -- name: ConfirmSeatLock :execrows
 UPDATE seat_locks 
-SET expires_at = NULL, updated_at = CURRENT_TIMESTAMP
-WHERE flight_id = ? AND seat_no = ? AND holder_id = ? AND expires_at > NOW();
+SET status = 'confirmed', expires_at = NULL, hold_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE flight_id = ? AND seat_no = ? AND holder_id = ?
+AND status IN ('active', 'payment_pending') AND expires_at > NOW();
+
+-- name: ReleaseSeatLock :execrows
+UPDATE seat_locks
+SET status = 'released', expires_at = NULL, hold_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE flight_id = ? AND seat_no = ? AND holder_id = ? AND status = 'active';
+
+-- name: DeleteSeatLock :execrows
+DELETE FROM seat_locks WHERE flight_id = ? AND seat_no = ?;
 
--- name: ReleaseSeatLock :exec
-DELETE FROM seat_locks 
-WHERE flight_id = ? AND seat_no = ? AND holder_id = ?;
+-- name: ListExpiredSeatLocks :many
+SELECT * FROM seat_locks
+WHERE status IN ('active', 'payment_pending') AND expires_at < ? FOR UPDATE;
 
--- name: CleanupExpiredLocks :exec
-DELETE FROM seat_locks WHERE expires_at < NOW();
+-- name: CleanupExpiredLocks :execrows
+UPDATE seat_locks
+SET status = IF(status = 'payment_pending', 'released', 'expired'),
+    hold_expires_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE status IN ('active', 'payment_pending') AND expires_at < ?;
 
 -- name: ListFlightSeatLocks :many
-SELECT flight_id, seat_no, holder_id, expires_at, created_at, updated_at
+SELECT id, flight_id, seat_no, holder_id, status, expires_at, created_at, updated_at
 FROM seat_locks 
 WHERE flight_id = ?;
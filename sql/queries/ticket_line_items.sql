@@ -0,0 +1,8 @@
+-- name: CreateTicketLineItem :exec
+INSERT INTO ticket_line_items (ticket_id, component, code, description, amount, base_amount)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListTicketLineItems :many
+SELECT * FROM ticket_line_items
+WHERE ticket_id = ?
+ORDER BY id;
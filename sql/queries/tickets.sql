@@ -4,15 +4,43 @@ SELECT * FROM tickets WHERE id = ?;
 -- name: GetTicketByPNR :one
 SELECT * FROM tickets WHERE pnr_code = ?;
 
+-- name: GetTicketByPNRForUpdate :one
+SELECT * FROM tickets WHERE pnr_code = ? FOR UPDATE;
+
 -- name: GetTicketByFlightSeat :one
-SELECT * FROM tickets WHERE flight_id = ? AND seat_no = ?;
+SELECT * FROM tickets WHERE flight_id = ? AND seat_no = ? AND status <> 'cancelled';
+
+-- name: CancelTicket :execrows
+UPDATE tickets SET status = 'cancelled', cancelled_at = NOW()
+WHERE id = ? AND status <> 'cancelled';
 
 -- name: CreateTicket :execlastid
-INSERT INTO tickets (flight_id, seat_no, user_id, price_amount, currency, pnr_code, payment_ref)
-VALUES (?, ?, ?, ?, ?, ?, ?);
+INSERT INTO tickets (flight_id, seat_no, cabin_class, user_id, price_amount, currency,
+                     base_price_amount, base_currency, exchange_rate, exchange_rate_effective_from,
+                     pnr_code, payment_ref, payment_authorization_id, payment_status)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: UpdateTicketPaymentStatus :exec
+UPDATE tickets SET payment_status = ? WHERE id = ?;
+
+-- name: GetTicketByPaymentAuthorizationForUpdate :one
+SELECT * FROM tickets WHERE payment_authorization_id = ? FOR UPDATE;
+
+-- name: UpdateTicketPaymentState :exec
+UPDATE tickets SET payment_status = ?, status = ? WHERE id = ?;
 
 -- name: ListUserTickets :many
 SELECT * FROM tickets WHERE user_id = ? ORDER BY created_at DESC;
 
 -- name: ListFlightTickets :many
 SELECT * FROM tickets WHERE flight_id = ? ORDER BY seat_no;
+
+-- name: ListDeniedBoardingCandidates :many
+SELECT * FROM tickets
+WHERE flight_id = sqlc.arg('flight_id') AND status <> 'cancelled'
+AND (sqlc.arg('cabin_class') = '' OR cabin_class = sqlc.arg('cabin_class'))
+ORDER BY price_amount ASC, created_at DESC, id DESC;
+
+-- name: AddTicketAmounts :exec
+UPDATE tickets SET price_amount = price_amount + ?, base_price_amount = base_price_amount + ?
+WHERE id = ?;
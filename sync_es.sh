@@ -5,10 +5,10 @@
 echo "Syncing flights from MySQL to Elasticsearch..."
 
 # First, get all flights from MySQL
-flights=$(docker exec -it airline_mysql mysql -u airline_user -pairline_pass airline_booking -s -N -e "SELECT id, origin, destination, departure_time, arrival_time, airline, aircraft, fare_class FROM flights;")
+flights=$(docker exec -it airline_mysql mysql -u airline_user -pairline_pass airline_booking -s -N -e "SELECT id, origin, destination, departure_time, arrival_time, airline, aircraft, fare_class, base_price FROM flights;")
 
 # Process each flight and index to Elasticsearch
-while IFS=$'\t' read -r id origin dest dep_time arr_time airline aircraft fare_class; do
+while IFS=$'\t' read -r id origin dest dep_time arr_time airline aircraft fare_class base_price; do
     if [ ! -z "$id" ]; then
         # Clean the data (remove any carriage returns)
         id=$(echo "$id" | tr -d '\r')
@@ -19,6 +19,7 @@ while IFS=$'\t' read -r id origin dest dep_time arr_time airline aircraft fare_c
         airline=$(echo "$airline" | tr -d '\r')
         aircraft=$(echo "$aircraft" | tr -d '\r')
         fare_class=$(echo "$fare_class" | tr -d '\r')
+        base_price=$(echo "$base_price" | tr -d '\r')
         
         # Convert datetime format for Elasticsearch
         dep_time_es=$(echo "$dep_time" | sed 's/ /T/' | sed 's/$/Z/')
@@ -34,7 +35,7 @@ while IFS=$'\t' read -r id origin dest dep_time arr_time airline aircraft fare_c
             \"airline\": \"$airline\",
             \"aircraft\": \"$aircraft\",
             \"fare_class\": \"$fare_class\",
-            \"base_price\": 299.99
+            \"base_price\": $base_price
         }"
         
         # Index to Elasticsearch
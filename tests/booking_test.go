@@ -15,6 +15,7 @@ import (
 	"airline-booking/internal/db"
 	"airline-booking/internal/es"
 	"airline-booking/internal/models"
+	"airline-booking/internal/payment"
 	"airline-booking/internal/repository"
 	"airline-booking/internal/service"
 )
@@ -33,7 +34,7 @@ func TestConcurrentHoldCreation(t *testing.T) {
 	defer database.Close()
 
 	// Run migrations
-	err = database.RunMigrations("../migrations")
+	err = db.RunMigrations(context.Background(), &cfg.Database, "../migrations", time.Minute, logger)
 	require.NoError(t, err)
 
 	// Setup Elasticsearch mock or skip ES tests
@@ -46,11 +47,25 @@ func TestConcurrentHoldCreation(t *testing.T) {
 	seatRepo := repository.NewSeatRepository(database, logger)
 	ticketRepo := repository.NewTicketRepository(database, logger)
 	flightRepo := repository.NewFlightRepository(database, logger)
+	airportRepo := repository.NewAirportRepository(database, logger)
+	inventoryRepo := repository.NewInventoryRepository(database, logger)
 
 	bookingService := service.NewBookingService(
 		seatRepo,
 		ticketRepo,
 		flightRepo,
+		airportRepo,
+		inventoryRepo,
+		repository.NewFareRuleRepository(database, logger),
+		payment.NewFakeGateway(payment.FakeConfig{}),
+		service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger),
+		service.NewPromotionService(repository.NewPromotionRepository(database, logger), logger),
+		service.NewAncillaryService(
+			repository.NewAncillaryRepository(database, logger),
+			service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger),
+			logger,
+		),
+		service.NewAuditService(repository.NewAuditRepository(database, logger), logger),
 		esClient,
 		database,
 		cfg,
@@ -68,6 +83,7 @@ func TestConcurrentHoldCreation(t *testing.T) {
 		Airline:       "AA",
 		Aircraft:      "Boeing 737",
 		FareClass:     "economy",
+		BasePrice:     29900,
 	}
 
 	createdFlight, err := flightRepo.CreateFlight(ctx, flight)
@@ -126,7 +142,7 @@ func TestConcurrentHoldCreation(t *testing.T) {
 
 	t.Run("ConcurrentHoldsDifferentSeats", func(t *testing.T) {
 		// Clean up previous test
-		err := seatRepo.CleanupExpiredHolds(ctx)
+		err := bookingService.CleanupExpiredHolds(ctx)
 		require.NoError(t, err)
 
 		numGoroutines := 2
@@ -178,6 +194,8 @@ func TestHoldExpiration(t *testing.T) {
 	seatRepo := repository.NewSeatRepository(database, logger)
 	ticketRepo := repository.NewTicketRepository(database, logger)
 	flightRepo := repository.NewFlightRepository(database, logger)
+	airportRepo := repository.NewAirportRepository(database, logger)
+	inventoryRepo := repository.NewInventoryRepository(database, logger)
 
 	esClient, err := es.NewClient(&cfg.Elasticsearch, logger)
 	if err != nil {
@@ -188,6 +206,18 @@ func TestHoldExpiration(t *testing.T) {
 		seatRepo,
 		ticketRepo,
 		flightRepo,
+		airportRepo,
+		inventoryRepo,
+		repository.NewFareRuleRepository(database, logger),
+		payment.NewFakeGateway(payment.FakeConfig{}),
+		service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger),
+		service.NewPromotionService(repository.NewPromotionRepository(database, logger), logger),
+		service.NewAncillaryService(
+			repository.NewAncillaryRepository(database, logger),
+			service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger),
+			logger,
+		),
+		service.NewAuditService(repository.NewAuditRepository(database, logger), logger),
 		esClient,
 		database,
 		cfg,
@@ -205,6 +235,7 @@ func TestHoldExpiration(t *testing.T) {
 		Airline:       "AA",
 		Aircraft:      "Boeing 737",
 		FareClass:     "economy",
+		BasePrice:     29900,
 	}
 
 	createdFlight, err := flightRepo.CreateFlight(ctx, flight)
@@ -251,6 +282,8 @@ func TestTicketConfirmation(t *testing.T) {
 	seatRepo := repository.NewSeatRepository(database, logger)
 	ticketRepo := repository.NewTicketRepository(database, logger)
 	flightRepo := repository.NewFlightRepository(database, logger)
+	airportRepo := repository.NewAirportRepository(database, logger)
+	inventoryRepo := repository.NewInventoryRepository(database, logger)
 
 	esClient, err := es.NewClient(&cfg.Elasticsearch, logger)
 	if err != nil {
@@ -261,6 +294,18 @@ func TestTicketConfirmation(t *testing.T) {
 		seatRepo,
 		ticketRepo,
 		flightRepo,
+		airportRepo,
+		inventoryRepo,
+		repository.NewFareRuleRepository(database, logger),
+		payment.NewFakeGateway(payment.FakeConfig{}),
+		service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger),
+		service.NewPromotionService(repository.NewPromotionRepository(database, logger), logger),
+		service.NewAncillaryService(
+			repository.NewAncillaryRepository(database, logger),
+			service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger),
+			logger,
+		),
+		service.NewAuditService(repository.NewAuditRepository(database, logger), logger),
 		esClient,
 		database,
 		cfg,
@@ -278,6 +323,7 @@ func TestTicketConfirmation(t *testing.T) {
 		Airline:       "AA",
 		Aircraft:      "Boeing 737",
 		FareClass:     "economy",
+		BasePrice:     29900,
 	}
 
 	createdFlight, err := flightRepo.CreateFlight(ctx, flight)
@@ -316,3 +362,192 @@ func TestTicketConfirmation(t *testing.T) {
 	_, err = bookingService.CreateHold(ctx, holdReq, anotherUserID, "")
 	assert.Error(t, err, "Should not be able to hold a sold seat")
 }
+
+func TestCreatedFlightSearchPrice(t *testing.T) {
+	// Setup test environment
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.Database.Name = "airline_booking_test"
+
+	logger, _ := zap.NewDevelopment()
+	database, err := db.NewDatabase(&cfg.Database, logger)
+	require.NoError(t, err)
+	defer database.Close()
+
+	err = db.RunMigrations(context.Background(), &cfg.Database, "../migrations", time.Minute, logger)
+	require.NoError(t, err)
+
+	esClient, err := es.NewClient(&cfg.Elasticsearch, logger)
+	if err != nil {
+		t.Skip("Elasticsearch not available, skipping test")
+	}
+
+	flightRepo := repository.NewFlightRepository(database, logger)
+	rateService := service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger)
+
+	bookingService := service.NewBookingService(
+		repository.NewSeatRepository(database, logger),
+		repository.NewTicketRepository(database, logger),
+		flightRepo,
+		repository.NewAirportRepository(database, logger),
+		repository.NewInventoryRepository(database, logger),
+		repository.NewFareRuleRepository(database, logger),
+		payment.NewFakeGateway(payment.FakeConfig{}),
+		rateService,
+		service.NewPromotionService(repository.NewPromotionRepository(database, logger), logger),
+		service.NewAncillaryService(repository.NewAncillaryRepository(database, logger), rateService, logger),
+		service.NewAuditService(repository.NewAuditRepository(database, logger), logger),
+		esClient,
+		database,
+		cfg,
+		logger,
+	)
+
+	ctx := context.Background()
+
+	// base_price is sent as a decimal amount in the base currency
+	created, err := bookingService.CreateFlight(ctx, models.CreateFlightRequest{
+		Origin:        "JFK",
+		Destination:   "LAX",
+		DepartureTime: "2030-06-15T12:00:00Z",
+		ArrivalTime:   "2030-06-15T18:00:00Z",
+		Airline:       "T9",
+		Aircraft:      "Boeing 737",
+		FareClass:     "economy",
+		BasePrice:     399.99,
+	})
+	require.NoError(t, err)
+
+	stored, err := flightRepo.GetFlight(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(39999), stored.BasePrice)
+
+	results, err := bookingService.SearchFlights(ctx, models.FlightSearchRequest{
+		Origin:      "JFK",
+		Destination: "LAX",
+		Date:        "2030-06-15",
+		Airline:     "T9",
+		Page:        1,
+		Size:        100,
+	})
+	require.NoError(t, err)
+
+	var found *models.FlightSearchResult
+	for i := range results.Flights {
+		if results.Flights[i].ID == created.ID {
+			found = &results.Flights[i]
+		}
+	}
+	require.NotNil(t, found, "created flight %d missing from the search results", created.ID)
+
+	// Quoted in the base currency, the price is the base price in minor units
+	assert.Equal(t, int64(39999), found.BasePrice)
+	assert.Equal(t, int64(39999), found.Price)
+	assert.Equal(t, cfg.Currency.Base, found.Currency)
+
+	// The MySQL fallback reads the same stored base price
+	fallback, _, err := flightRepo.SearchFlights(ctx, models.FlightSearchRequest{
+		Origin:      "JFK",
+		Destination: "LAX",
+		Airline:     "T9",
+		Page:        1,
+		Size:        100,
+	}, models.TimeRange{
+		From: time.Date(2030, 6, 15, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2030, 6, 16, 0, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	var fromDB *models.Flight
+	for i := range fallback {
+		if fallback[i].ID == created.ID {
+			fromDB = &fallback[i]
+		}
+	}
+	require.NotNil(t, fromDB, "created flight %d missing from the MySQL search", created.ID)
+	assert.Equal(t, int64(39999), fromDB.BasePrice)
+}
+
+func TestCancelTicketRefundsPayment(t *testing.T) {
+	// Setup test environment
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.Database.Name = "airline_booking_test"
+
+	logger, _ := zap.NewDevelopment()
+	database, err := db.NewDatabase(&cfg.Database, logger)
+	require.NoError(t, err)
+	defer database.Close()
+
+	esClient, err := es.NewClient(&cfg.Elasticsearch, logger)
+	if err != nil {
+		t.Skip("Elasticsearch not available, skipping test")
+	}
+
+	flightRepo := repository.NewFlightRepository(database, logger)
+	ticketRepo := repository.NewTicketRepository(database, logger)
+	gateway := payment.NewFakeGateway(payment.FakeConfig{})
+	rateService := service.NewExchangeRateService(repository.NewExchangeRateRepository(database, logger), database, &cfg.Currency, logger)
+
+	bookingService := service.NewBookingService(
+		repository.NewSeatRepository(database, logger),
+		ticketRepo,
+		flightRepo,
+		repository.NewAirportRepository(database, logger),
+		repository.NewInventoryRepository(database, logger),
+		repository.NewFareRuleRepository(database, logger),
+		gateway,
+		rateService,
+		service.NewPromotionService(repository.NewPromotionRepository(database, logger), logger),
+		service.NewAncillaryService(repository.NewAncillaryRepository(database, logger), rateService, logger),
+		service.NewAuditService(repository.NewAuditRepository(database, logger), logger),
+		esClient,
+		database,
+		cfg,
+		logger,
+	)
+
+	ctx := context.Background()
+
+	createdFlight, err := flightRepo.CreateFlight(ctx, models.Flight{
+		Origin:        "JFK",
+		Destination:   "LAX",
+		DepartureTime: time.Now().Add(24 * time.Hour),
+		ArrivalTime:   time.Now().Add(29 * time.Hour),
+		Airline:       "AA",
+		Aircraft:      "Boeing 737",
+		FareClass:     "economy",
+		BasePrice:     29900,
+	})
+	require.NoError(t, err)
+	err = flightRepo.CreateSeats(ctx, createdFlight.ID, []models.Seat{
+		{FlightID: createdFlight.ID, SeatNo: "20A", Class: "economy"},
+	})
+	require.NoError(t, err)
+
+	userID := "test_user"
+	_, err = bookingService.CreateHold(ctx, models.CreateHoldRequest{FlightID: createdFlight.ID, SeatNo: "20A"}, userID, "")
+	require.NoError(t, err)
+
+	confirmed, err := bookingService.ConfirmTicket(ctx, models.ConfirmTicketRequest{
+		FlightID:   createdFlight.ID,
+		SeatNo:     "20A",
+		PaymentRef: "payment_123",
+	}, userID, "")
+	require.NoError(t, err)
+	require.Equal(t, string(payment.StatusCaptured), confirmed.PaymentStatus)
+
+	cancelled, err := bookingService.CancelTicket(ctx, confirmed.PNRCode, userID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TicketStatusCancelled, cancelled.Status)
+	assert.Equal(t, string(payment.StatusRefunded), cancelled.PaymentStatus)
+
+	// The captured amount went back to the customer
+	auth, ok := gateway.Lookup(confirmed.PaymentAuthorizationID)
+	require.True(t, ok)
+	assert.Equal(t, payment.StatusRefunded, auth.Status)
+	assert.Equal(t, auth.CapturedAmount, auth.RefundedAmount)
+
+	stored, err := ticketRepo.GetTicketByPNR(ctx, confirmed.PNRCode)
+	require.NoError(t, err)
+	assert.Equal(t, string(payment.StatusRefunded), stored.PaymentStatus)
+}